@@ -15,24 +15,85 @@ import (
 type Assistant struct {
 	CommonResponse
 
-	ID           string            `json:"id"`
-	CreatedAt    int               `json:"created_at"`
-	Name         *string           `json:"name,omitempty"`
-	Description  *string           `json:"description,omitempty"`
-	Model        string            `json:"model"`
-	Instructions *string           `json:"instructions,omitempty"`
-	Tools        []Tool            `json:"tools"`
-	FileIDs      []string          `json:"file_ids"`
-	Metadata     map[string]string `json:"metadata"`
+	ID             string            `json:"id"`
+	CreatedAt      int               `json:"created_at"`
+	Name           *string           `json:"name,omitempty"`
+	Description    *string           `json:"description,omitempty"`
+	Model          string            `json:"model"`
+	Instructions   *string           `json:"instructions,omitempty"`
+	Tools          []Tool            `json:"tools"`
+	ToolResources  *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata       map[string]string `json:"metadata"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+	TopP           *float64          `json:"top_p,omitempty"`
+	ResponseFormat any               `json:"response_format,omitempty"` // 'auto' | ChatCompletionResponseFormat
+
+	// Deprecated: `file_ids` was removed in v2, use `ToolResources` instead.
+	FileIDs []string `json:"file_ids,omitempty"`
+}
+
+// ToolResources struct for resources used by the tools of assistants, threads, and runs
+//
+// https://platform.openai.com/docs/api-reference/assistants/object#assistants/object-tool_resources
+type ToolResources struct {
+	CodeInterpreter *CodeInterpreterResources `json:"code_interpreter,omitempty"`
+	FileSearch      *FileSearchResources      `json:"file_search,omitempty"`
+}
+
+// CodeInterpreterResources struct for ToolResources
+type CodeInterpreterResources struct {
+	FileIDs []string `json:"file_ids,omitempty"` // max 20 files
+}
+
+// FileSearchResources struct for ToolResources
+type FileSearchResources struct {
+	VectorStoreIDs []string `json:"vector_store_ids,omitempty"` // max 1 vector store
+
+	// helper for creating a vector store with files on assistant or thread creation
+	VectorStores []FileSearchVectorStore `json:"vector_stores,omitempty"`
+}
+
+// FileSearchVectorStore struct for FileSearchResources
+type FileSearchVectorStore struct {
+	FileIDs          []string          `json:"file_ids,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// NewToolResources returns a new ToolResources with given code interpreter `fileIDs` and file search `vectorStoreIDs`.
+//
+// Empty ones are omitted.
+func NewToolResources(codeInterpreterFileIDs, fileSearchVectorStoreIDs []string) ToolResources {
+	resources := ToolResources{}
+	if len(codeInterpreterFileIDs) > 0 {
+		resources.CodeInterpreter = &CodeInterpreterResources{FileIDs: codeInterpreterFileIDs}
+	}
+	if len(fileSearchVectorStoreIDs) > 0 {
+		resources.FileSearch = &FileSearchResources{VectorStoreIDs: fileSearchVectorStoreIDs}
+	}
+	return resources
 }
 
 // Tool struct for assistant object
 //
 // https://platform.openai.com/docs/api-reference/assistants/object#assistants/object-tools
 type Tool struct {
-	Type      string         `json:"type"`
-	Function  *ToolFunction  `json:"function,omitempty"`
-	Container *ToolContainer `json:"container,omitempty"`
+	Type       string          `json:"type"`
+	Function   *ToolFunction   `json:"function,omitempty"`
+	FileSearch *ToolFileSearch `json:"file_search,omitempty"`
+	Container  *ToolContainer  `json:"container,omitempty"`
+}
+
+// ToolFileSearch struct for overriding the behavior of 'file_search' tool
+type ToolFileSearch struct {
+	MaxNumResults  *int                          `json:"max_num_results,omitempty"` // 1 ~ 50
+	RankingOptions *ToolFileSearchRankingOptions `json:"ranking_options,omitempty"`
+}
+
+// ToolFileSearchRankingOptions struct for ToolFileSearch
+type ToolFileSearchRankingOptions struct {
+	Ranker         *string  `json:"ranker,omitempty"` // 'auto' | 'default_2024_08_21'
+	ScoreThreshold *float64 `json:"score_threshold,omitempty"`
 }
 
 // NewCodeInterpreterTool returns a tool with type: 'code_interpreter'.
@@ -42,7 +103,34 @@ func NewCodeInterpreterTool() Tool {
 	}
 }
 
+// NewFileSearchTool returns a tool with type: 'file_search'.
+func NewFileSearchTool() Tool {
+	return Tool{
+		Type: "file_search",
+	}
+}
+
+// NewFileSearchToolWithOptions returns a tool with type: 'file_search', with given `maxNumResults` and `scoreThreshold`.
+//
+// Non-positive values are omitted.
+func NewFileSearchToolWithOptions(maxNumResults int, scoreThreshold float64) Tool {
+	fileSearch := ToolFileSearch{}
+	if maxNumResults > 0 {
+		fileSearch.MaxNumResults = &maxNumResults
+	}
+	if scoreThreshold > 0 {
+		fileSearch.RankingOptions = &ToolFileSearchRankingOptions{ScoreThreshold: &scoreThreshold}
+	}
+
+	return Tool{
+		Type:       "file_search",
+		FileSearch: &fileSearch,
+	}
+}
+
 // NewRetrievalTool returns a tool with type: 'retrieval'.
+//
+// Deprecated: 'retrieval' tool was replaced with 'file_search' in v2, use `NewFileSearchTool` instead.
 func NewRetrievalTool() Tool {
 	return Tool{
 		Type: "retrieval",
@@ -120,8 +208,50 @@ func (o CreateAssistantOptions) SetTools(tools []Tool) CreateAssistantOptions {
 	return o
 }
 
+// SetToolResources sets the `tool_resources` parameter of assistant creation.
+//
+// https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-tool_resources
+func (o CreateAssistantOptions) SetToolResources(resources ToolResources) CreateAssistantOptions {
+	o["tool_resources"] = resources
+	return o
+}
+
+// SetTemperature sets the `temperature` parameter of assistant creation.
+//
+// https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-temperature
+func (o CreateAssistantOptions) SetTemperature(temperature float64) CreateAssistantOptions {
+	o["temperature"] = temperature
+	return o
+}
+
+// SetTopP sets the `top_p` parameter of assistant creation.
+//
+// https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-top_p
+func (o CreateAssistantOptions) SetTopP(topP float64) CreateAssistantOptions {
+	o["top_p"] = topP
+	return o
+}
+
+// SetResponseFormat sets the `response_format` parameter of assistant creation.
+//
+// https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-response_format
+func (o CreateAssistantOptions) SetResponseFormat(format ChatCompletionResponseFormat) CreateAssistantOptions {
+	o["response_format"] = format
+	return o
+}
+
+// SetResponseFormatAuto sets the `response_format` parameter of assistant creation to 'auto'.
+//
+// https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-response_format
+func (o CreateAssistantOptions) SetResponseFormatAuto() CreateAssistantOptions {
+	o["response_format"] = "auto"
+	return o
+}
+
 // SetFileIDs sets the `file_ids` parameter of assistant creation.
 //
+// Deprecated: `file_ids` was removed in v2, use `SetToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/createAssistant#assistants-createassistant-file_ids
 func (o CreateAssistantOptions) SetFileIDs(fileIDs []string) CreateAssistantOptions {
 	o["file_ids"] = fileIDs
@@ -230,8 +360,50 @@ func (o ModifyAssistantOptions) SetTools(tools []Tool) ModifyAssistantOptions {
 	return o
 }
 
+// SetToolResources sets the `tool_resources` parameter of assistant modification.
+//
+// https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-tool_resources
+func (o ModifyAssistantOptions) SetToolResources(resources ToolResources) ModifyAssistantOptions {
+	o["tool_resources"] = resources
+	return o
+}
+
+// SetTemperature sets the `temperature` parameter of assistant modification.
+//
+// https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-temperature
+func (o ModifyAssistantOptions) SetTemperature(temperature float64) ModifyAssistantOptions {
+	o["temperature"] = temperature
+	return o
+}
+
+// SetTopP sets the `top_p` parameter of assistant modification.
+//
+// https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-top_p
+func (o ModifyAssistantOptions) SetTopP(topP float64) ModifyAssistantOptions {
+	o["top_p"] = topP
+	return o
+}
+
+// SetResponseFormat sets the `response_format` parameter of assistant modification.
+//
+// https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-response_format
+func (o ModifyAssistantOptions) SetResponseFormat(format ChatCompletionResponseFormat) ModifyAssistantOptions {
+	o["response_format"] = format
+	return o
+}
+
+// SetResponseFormatAuto sets the `response_format` parameter of assistant modification to 'auto'.
+//
+// https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-response_format
+func (o ModifyAssistantOptions) SetResponseFormatAuto() ModifyAssistantOptions {
+	o["response_format"] = "auto"
+	return o
+}
+
 // SetFileIDs sets the `file_ids` parameter of assistant modification.
 //
+// Deprecated: `file_ids` was removed in v2, use `SetToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/modifyAssistant#assistants-modifyassistant-file_ids
 func (o ModifyAssistantOptions) SetFileIDs(fileIDs []string) ModifyAssistantOptions {
 	o["file_ids"] = fileIDs
@@ -380,6 +552,8 @@ func (c *Client) ListAssistants(options ListAssistantsOptions) (response Assista
 
 // AssistantFile struct for attached files of assistants
 //
+// Deprecated: assistant files were removed in v2, use `ToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/file-object
 type AssistantFile struct {
 	CommonResponse
@@ -391,6 +565,8 @@ type AssistantFile struct {
 
 // CreateAssistantFile creates an assistant file by attaching given `fileID` to an assistant with `assistantID`.
 //
+// Deprecated: assistant files were removed in v2, use `ToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/createAssistantFile
 func (c *Client) CreateAssistantFile(assistantID, fileID string) (response AssistantFile, err error) {
 	var bytes []byte
@@ -416,6 +592,8 @@ func (c *Client) CreateAssistantFile(assistantID, fileID string) (response Assis
 
 // RetrieveAssistantFile retrieves an assistant file by given `assistantID` and `fileID`.
 //
+// Deprecated: assistant files were removed in v2, use `ToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/getAssistantFile
 func (c *Client) RetrieveAssistantFile(assistantID, fileID string) (response AssistantFile, err error) {
 	var bytes []byte
@@ -447,6 +625,8 @@ type AssistantFileDeletionStatus struct {
 
 // DeleteAssistantFile deletes an assistant file by given `assistantID` and `fileID`.
 //
+// Deprecated: assistant files were removed in v2, use `ToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/deleteAssistantFile
 func (c *Client) DeleteAssistantFile(assistantID, fileID string) (response AssistantFileDeletionStatus, err error) {
 	var bytes []byte
@@ -517,6 +697,8 @@ func (o ListAssistantFilesOptions) SetBefore(before string) ListAssistantFilesOp
 
 // ListAssistantFiles lists all assistant files with given `assistantID` and `options`.
 //
+// Deprecated: assistant files were removed in v2, use `ToolResources` instead.
+//
 // https://platform.openai.com/docs/api-reference/assistants/listAssistantFiles
 func (c *Client) ListAssistantFiles(assistantID string, options ListAssistantFilesOptions) (response AssistantFiles, err error) {
 	if options == nil {
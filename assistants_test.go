@@ -1,6 +1,9 @@
 package openai
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -22,8 +25,6 @@ func TestAssistants(t *testing.T) {
 		t.Errorf("environment variables `OPENAI_API_KEY` and `OPENAI_ORGANIZATION` are needed")
 	}
 
-	client.SetBetaHeader(`assistants=v1`)
-
 	// === CreateAssistant ===
 	if created, err := client.CreateAssistant(assistantsModel, CreateAssistantOptions{}.
 		SetName("My assistant for testing api").
@@ -37,7 +38,8 @@ func TestAssistants(t *testing.T) {
 					AddPropertyWithEnums("unit", "string", "The unit of temperature", []string{"celsius", "fahrenheit"}).
 					SetRequiredParameters([]string{"location", "unit"}),
 			}),
-			NewRetrievalTool(),
+			NewCodeInterpreterTool(),
+			NewFileSearchTool(),
 		})); err != nil {
 		t.Errorf("failed to create assistant: %s", err)
 	} else {
@@ -78,38 +80,20 @@ func TestAssistants(t *testing.T) {
 				} else {
 					fileID := uploaded.ID
 
-					// === CreateAssistantFile ===
-					if created, err := client.CreateAssistantFile(assistantID, fileID); err != nil {
-						t.Errorf("failed to create assistant file: %s", err)
+					// === ModifyAssistant (tool resources) ===
+					if modified, err := client.ModifyAssistant(assistantID, ModifyAssistantOptions{}.
+						SetToolResources(NewToolResources([]string{fileID}, nil))); err != nil {
+						t.Errorf("failed to modify tool resources of assistant: %s", err)
 					} else {
-						assistantFileID := created.ID
-
-						// === ListAssistantFiles ===
-						if listed, err := client.ListAssistantFiles(assistantID, nil); err != nil {
-							t.Errorf("failed to list assistant files: %s", err)
-						} else {
-							if len(listed.Data) <= 0 {
-								t.Errorf("no assistant file was fetched while listing")
-							}
+						if modified.ToolResources == nil || modified.ToolResources.CodeInterpreter == nil ||
+							len(modified.ToolResources.CodeInterpreter.FileIDs) != 1 {
+							t.Errorf("modified tool resources differ from expectation: %+v", modified.ToolResources)
 						}
+					}
 
-						// === RetrieveAssistantFile ===
-						if retrieved, err := client.RetrieveAssistantFile(assistantID, assistantFileID); err != nil {
-							t.Errorf("failed to retrieve assistant file: %s", err)
-						} else {
-							if retrieved.ID != assistantFileID {
-								t.Errorf("retrieved assistant file's id: %s differs from the requested one: %s", retrieved.ID, assistantFileID)
-							}
-
-							// === DeleteAssistantFile ===
-							if deleted, err := client.DeleteAssistantFile(assistantID, assistantFileID); err != nil {
-								t.Errorf("failed to delete assistant file: %s", err)
-							} else {
-								if !deleted.Deleted {
-									t.Errorf("deleted status of deleted assistant file is not true")
-								}
-							}
-						}
+					// === DeleteFile ===
+					if _, err := client.DeleteFile(fileID); err != nil {
+						t.Errorf("failed to delete file: %s", err)
 					}
 				}
 			}
@@ -125,3 +109,110 @@ func TestAssistants(t *testing.T) {
 		}
 	}
 }
+
+func TestAssistantsV2Mock(t *testing.T) {
+	var beta string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		beta = r.Header.Get("OpenAI-Beta")
+		body = nil
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/assistants":
+			w.Write([]byte(`{"id":"asst_1","object":"assistant","model":"gpt-4o","tools":[{"type":"file_search","file_search":{"max_num_results":5}}],"tool_resources":{"file_search":{"vector_store_ids":["vs_1"]}},"temperature":0.2,"top_p":0.9,"response_format":"auto"}`))
+		case "/threads/thread_1/messages":
+			w.Write([]byte(`{"id":"msg_1","object":"thread.message","thread_id":"thread_1","role":"user","content":[{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"low"}}],"attachments":[{"file_id":"file_1","tools":[{"type":"code_interpreter"}]}]}`))
+		case "/threads/thread_1/runs":
+			w.Write([]byte(`{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"queued","truncation_strategy":{"type":"last_messages","last_messages":3}}`))
+		case "/models":
+			w.Write([]byte(`{"object":"list","data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// assistant with tool resources
+	assistant, err := client.CreateAssistant("gpt-4o", CreateAssistantOptions{}.
+		SetTools([]Tool{NewFileSearchToolWithOptions(5, 0)}).
+		SetToolResources(NewToolResources(nil, []string{"vs_1"})).
+		SetTemperature(0.2).
+		SetTopP(0.9).
+		SetResponseFormatAuto())
+	if err != nil {
+		t.Fatalf("CreateAssistant failed: %v", err)
+	}
+	if beta != "assistants=v2" {
+		t.Errorf("Expected beta header 'assistants=v2', got '%s'", beta)
+	}
+	if resources, ok := body["tool_resources"].(map[string]any); !ok || resources["file_search"] == nil || resources["code_interpreter"] != nil {
+		t.Errorf("Unexpected tool_resources: %v", body["tool_resources"])
+	}
+	if body["response_format"] != "auto" || body["temperature"] != 0.2 || body["top_p"] != 0.9 {
+		t.Errorf("Unexpected request body: %v", body)
+	}
+	if assistant.ToolResources == nil || assistant.ToolResources.FileSearch == nil || assistant.ToolResources.FileSearch.VectorStoreIDs[0] != "vs_1" {
+		t.Errorf("Unexpected tool resources: %+v", assistant.ToolResources)
+	}
+	if assistant.Tools[0].FileSearch == nil || *assistant.Tools[0].FileSearch.MaxNumResults != 5 {
+		t.Errorf("Unexpected tools: %+v", assistant.Tools)
+	}
+	if assistant.Temperature == nil || *assistant.Temperature != 0.2 {
+		t.Errorf("Expected temperature 0.2, got %v", assistant.Temperature)
+	}
+
+	// message with content parts and attachments
+	message, err := client.CreateMessage("thread_1", "user", "", CreateMessageOptions{}.
+		SetContentParts([]MessageContentPart{
+			NewMessageContentPartWithText("What is this?"),
+			NewMessageContentPartWithImageURL("https://example.com/cat.png", "low"),
+		}).
+		SetAttachments([]MessageAttachment{NewMessageAttachment("file_1", NewCodeInterpreterTool())}))
+	if err != nil {
+		t.Fatalf("CreateMessage failed: %v", err)
+	}
+	if parts, ok := body["content"].([]any); !ok || len(parts) != 2 {
+		t.Errorf("Expected 2 content parts, got %v", body["content"])
+	}
+	if len(message.Content) != 1 || message.Content[0].ImageURL == nil || *message.Content[0].ImageURL.Detail != "low" {
+		t.Errorf("Unexpected message content: %+v", message.Content)
+	}
+	if len(message.Attachments) != 1 || message.Attachments[0].Tools[0].Type != "code_interpreter" {
+		t.Errorf("Unexpected attachments: %+v", message.Attachments)
+	}
+
+	// run with truncation strategy
+	run, err := client.CreateRun("thread_1", "asst_1", CreateRunOptions{}.
+		SetTruncationStrategy(NewTruncationStrategyLastMessages(3)).
+		SetMaxCompletionTokens(100))
+	if err != nil {
+		t.Fatalf("CreateRun failed: %v", err)
+	}
+	if run.TruncationStrategy == nil || run.TruncationStrategy.Type != "last_messages" || *run.TruncationStrategy.LastMessages != 3 {
+		t.Errorf("Unexpected truncation strategy: %+v", run.TruncationStrategy)
+	}
+
+	// not for other endpoints
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if beta != "" {
+		t.Errorf("Expected no beta header, got '%s'", beta)
+	}
+
+	// explicitly set header takes precedence
+	client.SetBetaHeader("assistants=v1")
+	if _, err := client.CreateRun("thread_1", "asst_1", nil); err != nil {
+		t.Fatalf("CreateRun failed: %v", err)
+	}
+	if beta != "assistants=v1" {
+		t.Errorf("Expected beta header 'assistants=v1', got '%s'", beta)
+	}
+}
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -8,6 +9,9 @@ import (
 // https://platform.openai.com/docs/api-reference/audio
 
 // Transcription struct for response
+//
+// `JSON`, `Text`, `SRT`, `VerboseJSON`, or `VTT` has the raw response body of requested format,
+// and JSON responses are parsed into the other typed fields.
 type Transcription struct {
 	CommonResponse
 
@@ -16,6 +20,55 @@ type Transcription struct {
 	SRT         *string `json:"srt,omitempty"`
 	VerboseJSON *string `json:"verbose_json,omitempty"`
 	VTT         *string `json:"vtt,omitempty"`
+
+	// for `verbose_json`
+	Task     *string                `json:"task,omitempty"`
+	Language *string                `json:"language,omitempty"`
+	Duration *float64               `json:"duration,omitempty"`
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+
+	// for `include[]=logprobs`
+	Logprobs []TranscriptionLogprob `json:"logprobs,omitempty"`
+
+	Usage *TranscriptionUsage `json:"usage,omitempty"`
+}
+
+// TranscriptionSegment struct for segments of `verbose_json` transcriptions
+type TranscriptionSegment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"` // in seconds
+	End              float64 `json:"end"`   // in seconds
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens,omitempty"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+// TranscriptionWord struct for word timestamps of `verbose_json` transcriptions
+type TranscriptionWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"` // in seconds
+	End   float64 `json:"end"`   // in seconds
+}
+
+// TranscriptionLogprob struct for log probabilities of transcribed tokens
+type TranscriptionLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
+// TranscriptionUsage struct for usages of transcriptions
+type TranscriptionUsage struct {
+	Type         string   `json:"type"` // 'tokens' or 'duration'
+	InputTokens  int      `json:"input_tokens,omitempty"`
+	OutputTokens int      `json:"output_tokens,omitempty"`
+	TotalTokens  int      `json:"total_tokens,omitempty"`
+	Seconds      *float64 `json:"seconds,omitempty"`
 }
 
 // SpeechVoice type for constants
@@ -28,6 +81,13 @@ const (
 	SpeechVoiceOnyx    SpeechVoice = "onyx"
 	SpeechVoiceNova    SpeechVoice = "nova"
 	SpeechVoiceShimmer SpeechVoice = "shimmer"
+	SpeechVoiceAsh     SpeechVoice = "ash"
+	SpeechVoiceBallad  SpeechVoice = "ballad"
+	SpeechVoiceCoral   SpeechVoice = "coral"
+	SpeechVoiceSage    SpeechVoice = "sage"
+	SpeechVoiceVerse   SpeechVoice = "verse"
+	SpeechVoiceMarin   SpeechVoice = "marin"
+	SpeechVoiceCedar   SpeechVoice = "cedar"
 )
 
 // SpeechResponseFormat type for constants
@@ -38,6 +98,16 @@ const (
 	SpeechResponseFormatOpus SpeechResponseFormat = "opus"
 	SpeechResponseFormatAAC  SpeechResponseFormat = "aac"
 	SpeechResponseFormatFLAC SpeechResponseFormat = "flac"
+	SpeechResponseFormatWAV  SpeechResponseFormat = "wav"
+	SpeechResponseFormatPCM  SpeechResponseFormat = "pcm" // 24kHz, 16-bit signed, little-endian, mono
+)
+
+// SpeechStreamFormat type for constants
+type SpeechStreamFormat string
+
+const (
+	SpeechStreamFormatAudio SpeechStreamFormat = "audio"
+	SpeechStreamFormatSSE   SpeechStreamFormat = "sse"
 )
 
 // SpeechOptions for creating speech
@@ -55,19 +125,41 @@ func (o SpeechOptions) SetSpeed(speed float32) SpeechOptions {
 	return o
 }
 
+// SetInstructions sets the `instructions` parameter of speech request.
+//
+// It does not work with `tts-1` or `tts-1-hd`.
+//
+// https://platform.openai.com/docs/api-reference/audio/createSpeech#audio-createspeech-instructions
+func (o SpeechOptions) SetInstructions(instructions string) SpeechOptions {
+	o["instructions"] = instructions
+	return o
+}
+
+// SetStreamFormat sets the `stream_format` parameter of speech request.
+//
+// It does not work with `tts-1` or `tts-1-hd`.
+//
+// https://platform.openai.com/docs/api-reference/audio/createSpeech#audio-createspeech-stream_format
+func (o SpeechOptions) SetStreamFormat(format SpeechStreamFormat) SpeechOptions {
+	o["stream_format"] = format
+	return o
+}
+
 // CreateSpeech generates audio from the input text.
 //
 // https://platform.openai.com/docs/api-reference/audio/createSpeech
 func (c *Client) CreateSpeech(model string, input string, voice SpeechVoice, options SpeechOptions) (audio []byte, err error) {
-	if options == nil {
-		options = SpeechOptions{}
-	}
-	options["model"] = model
-	options["input"] = input
-	options["voice"] = voice
+	return c.CreateSpeechWithContext(context.Background(), model, input, voice, options)
+}
+
+// CreateSpeechWithContext generates audio from the input text, with context.
+//
+// https://platform.openai.com/docs/api-reference/audio/createSpeech
+func (c *Client) CreateSpeechWithContext(ctx context.Context, model string, input string, voice SpeechVoice, options SpeechOptions) (audio []byte, err error) {
+	options = speechParams(model, input, voice, options)
 
 	var bytes []byte
-	if bytes, err = c.post("audio/speech", options); err == nil {
+	if bytes, err = c.postWithContext(ctx, "audio/speech", options); err == nil {
 		return bytes, nil
 	} else {
 		var res CommonResponse
@@ -90,6 +182,45 @@ const (
 	TranscriptionResponseFormatVTT         TranscriptionResponseFormat = "vtt"
 )
 
+// TranscriptionTimestampGranularity type for constants
+type TranscriptionTimestampGranularity string
+
+const (
+	TranscriptionTimestampGranularityWord    TranscriptionTimestampGranularity = "word"
+	TranscriptionTimestampGranularitySegment TranscriptionTimestampGranularity = "segment"
+)
+
+// TranscriptionInclude type for constants
+type TranscriptionInclude string
+
+const (
+	TranscriptionIncludeLogprobs TranscriptionInclude = "logprobs"
+)
+
+// TranscriptionChunkingStrategy struct for chunking audio of transcription request
+type TranscriptionChunkingStrategy struct {
+	Type              string   `json:"type"` // == 'server_vad'
+	PrefixPaddingMs   *int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs *int     `json:"silence_duration_ms,omitempty"`
+	Threshold         *float64 `json:"threshold,omitempty"`
+}
+
+// NewTranscriptionServerVADChunkingStrategy returns a new TranscriptionChunkingStrategy with server-side voice activity detection.
+func NewTranscriptionServerVADChunkingStrategy() TranscriptionChunkingStrategy {
+	return TranscriptionChunkingStrategy{
+		Type: "server_vad",
+	}
+}
+
+// returns given values as a string slice, for repeated multipart fields
+func stringValues[T ~string](values []T) []string {
+	strs := []string{}
+	for _, v := range values {
+		strs = append(strs, string(v))
+	}
+	return strs
+}
+
 // TranscriptionOptions for creating transcription
 type TranscriptionOptions map[string]any
 
@@ -125,10 +256,51 @@ func (o TranscriptionOptions) SetLanguage(language string) TranscriptionOptions
 	return o
 }
 
+// SetTimestampGranularities sets the `timestamp_granularities[]` parameter of transcription request.
+//
+// `response_format` should be `verbose_json`.
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription#audio-createtranscription-timestamp_granularities
+func (o TranscriptionOptions) SetTimestampGranularities(granularities ...TranscriptionTimestampGranularity) TranscriptionOptions {
+	o["timestamp_granularities[]"] = stringValues(granularities)
+	return o
+}
+
+// SetInclude sets the `include[]` parameter of transcription request.
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription#audio-createtranscription-include
+func (o TranscriptionOptions) SetInclude(include ...TranscriptionInclude) TranscriptionOptions {
+	o["include[]"] = stringValues(include)
+	return o
+}
+
+// SetChunkingStrategy sets the `chunking_strategy` parameter of transcription request.
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription#audio-createtranscription-chunking_strategy
+func (o TranscriptionOptions) SetChunkingStrategy(strategy TranscriptionChunkingStrategy) TranscriptionOptions {
+	o["chunking_strategy"] = strategy
+	return o
+}
+
+// SetChunkingStrategyAuto sets the `chunking_strategy` parameter of transcription request to 'auto'.
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription#audio-createtranscription-chunking_strategy
+func (o TranscriptionOptions) SetChunkingStrategyAuto() TranscriptionOptions {
+	o["chunking_strategy"] = "auto"
+	return o
+}
+
 // CreateTranscription transcribes given audio file into the input language.
 //
 // https://platform.openai.com/docs/api-reference/audio/create
 func (c *Client) CreateTranscription(file FileParam, model string, options TranscriptionOptions) (response Transcription, err error) {
+	return c.CreateTranscriptionWithContext(context.Background(), file, model, options)
+}
+
+// CreateTranscriptionWithContext transcribes given audio file into the input language, with context.
+//
+// https://platform.openai.com/docs/api-reference/audio/create
+func (c *Client) CreateTranscriptionWithContext(ctx context.Context, file FileParam, model string, options TranscriptionOptions) (response Transcription, err error) {
 	if options == nil {
 		options = TranscriptionOptions{}
 	}
@@ -136,8 +308,8 @@ func (c *Client) CreateTranscription(file FileParam, model string, options Trans
 	options["model"] = model
 
 	var bytes []byte
-	if bytes, err = c.post("audio/transcriptions", options); err == nil {
-		if err = json.Unmarshal(bytes, &response); err == nil {
+	if bytes, err = c.postWithContext(ctx, "audio/transcriptions", options); err == nil {
+		if response, err = parseTranscription(bytes, options["response_format"]); err == nil {
 			if response.Error == nil {
 				return response, nil
 			}
@@ -187,6 +359,32 @@ func (o TranslationOptions) SetTemperature(temperature float64) TranslationOptio
 	return o
 }
 
+// SetTimestampGranularities sets the `timestamp_granularities[]` parameter of translation request.
+//
+// `response_format` should be `verbose_json`.
+func (o TranslationOptions) SetTimestampGranularities(granularities ...TranscriptionTimestampGranularity) TranslationOptions {
+	o["timestamp_granularities[]"] = stringValues(granularities)
+	return o
+}
+
+// SetInclude sets the `include[]` parameter of translation request.
+func (o TranslationOptions) SetInclude(include ...TranscriptionInclude) TranslationOptions {
+	o["include[]"] = stringValues(include)
+	return o
+}
+
+// SetChunkingStrategy sets the `chunking_strategy` parameter of translation request.
+func (o TranslationOptions) SetChunkingStrategy(strategy TranscriptionChunkingStrategy) TranslationOptions {
+	o["chunking_strategy"] = strategy
+	return o
+}
+
+// SetChunkingStrategyAuto sets the `chunking_strategy` parameter of translation request to 'auto'.
+func (o TranslationOptions) SetChunkingStrategyAuto() TranslationOptions {
+	o["chunking_strategy"] = "auto"
+	return o
+}
+
 // CreateTranslation translates given audio file into English.
 //
 // https://platform.openai.com/docs/api-reference/audio/create
@@ -199,12 +397,13 @@ func (c *Client) CreateTranslation(file FileParam, model string, options Transla
 
 	var bytes []byte
 	if bytes, err = c.post("audio/translations", options); err == nil {
-		if err = json.Unmarshal(bytes, &response); err == nil {
-			if response.Error == nil {
-				return response, nil
+		var transcription Transcription
+		if transcription, err = parseTranscription(bytes, options["response_format"]); err == nil {
+			if transcription.Error == nil {
+				return Translation(transcription), nil
 			}
 
-			err = response.Error.err()
+			err = transcription.Error.err()
 		}
 	} else {
 		var res CommonResponse
@@ -215,3 +414,34 @@ func (c *Client) CreateTranslation(file FileParam, model string, options Transla
 
 	return Translation{}, err
 }
+
+// parses the response body of transcription/translation request with given `responseFormat`
+func parseTranscription(bytes []byte, responseFormat any) (response Transcription, err error) {
+	body := string(bytes)
+
+	format := TranscriptionResponseFormatJSON
+	if responseFormat != nil {
+		format = TranscriptionResponseFormat(fmt.Sprintf("%v", responseFormat))
+	}
+
+	switch format {
+	case TranscriptionResponseFormatText:
+		response.Text = &body
+	case TranscriptionResponseFormatSRT:
+		response.SRT = &body
+	case TranscriptionResponseFormatVTT:
+		response.VTT = &body
+	default:
+		if err = json.Unmarshal(bytes, &response); err != nil {
+			return Transcription{}, err
+		}
+
+		if format == TranscriptionResponseFormatVerboseJSON {
+			response.VerboseJSON = &body
+		} else {
+			response.JSON = &body
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,354 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// helpers for transcribing audio files larger than the API's limit, by splitting them into chunks
+
+const (
+	defaultLongAudioModel          = "whisper-1"
+	defaultLongAudioMaxChunkBytes  = 24 * 1024 * 1024 // API limit is 25MB
+	defaultLongAudioOverlap        = 2 * time.Second
+	defaultLongAudioMaxConcurrency = 4
+
+	longAudioPromptMaxChars = 600 // the model only considers the last 224 tokens of prompt
+	longAudioMinDedupWords  = 2
+)
+
+// TranscribeLongOptions struct for transcribing long audio
+type TranscribeLongOptions struct {
+	// model for transcription, which should support `verbose_json` (default: whisper-1)
+	Model string
+
+	// options for each chunk
+	//
+	// `response_format` is overwritten with `verbose_json`, and `prompt` is used only for the first chunk.
+	Transcription TranscriptionOptions
+
+	// maximum size of a chunk in bytes (default: 24MB)
+	MaxChunkBytes int
+
+	// maximum duration of a chunk (default: 0, limited only by size)
+	MaxChunkDuration time.Duration
+
+	// overlapping duration between chunks (default: 2 seconds)
+	Overlap time.Duration
+
+	// maximum number of chunks transcribed in parallel (default: 4)
+	//
+	// Set this to 1 for transcribing chunks one after another, so that every chunk gets the previous one's text as `prompt`.
+	MaxConcurrency int
+
+	// do not pass the tail of the previous chunk's text as `prompt`
+	//
+	// By default, it is passed only when the previous chunk is already transcribed at the time a chunk starts.
+	DisablePromptChaining bool
+}
+
+// TranscribeLong transcribes audio from `reader` which can be larger than the API's limit.
+//
+// WAV is split on sample boundaries, and MP3 on frame boundaries, into chunks overlapping each other.
+// Audio which fits in a single chunk can be in any format.
+//
+// Segments (and words, if requested with `timestamp_granularities[]`) of chunks are stitched together
+// with timestamps in the original audio, and duplicated texts in the overlaps are removed.
+//
+// Chunks are started in order, up to `MaxConcurrency` in parallel. The tail of the previous chunk's text
+// is passed as `prompt` only if it is already available, so set `MaxConcurrency` to 1 for chaining all of them.
+func (c *Client) TranscribeLong(ctx context.Context, reader io.Reader, options TranscribeLongOptions) (result Transcription, err error) {
+	if options.Model == "" {
+		options.Model = defaultLongAudioModel
+	}
+	if options.MaxChunkBytes <= 0 {
+		options.MaxChunkBytes = defaultLongAudioMaxChunkBytes
+	}
+	if options.Overlap <= 0 {
+		options.Overlap = defaultLongAudioOverlap
+	}
+	if options.MaxConcurrency <= 0 {
+		options.MaxConcurrency = defaultLongAudioMaxConcurrency
+	}
+
+	var data []byte
+	if data, err = io.ReadAll(reader); err != nil {
+		return result, fmt.Errorf("failed to read audio: %s", err)
+	}
+
+	var chunks []audioChunk
+	if chunks, err = splitAudio(data, options.MaxChunkBytes, options.MaxChunkDuration, options.Overlap); err != nil {
+		return result, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	transcriptions := make([]Transcription, len(chunks))
+	finished := make([]chan struct{}, len(chunks))
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+	semaphore := make(chan struct{}, options.MaxConcurrency)
+
+	// the first error of transcription requests, which cancels the others
+	var failure error
+	var failOnce sync.Once
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		// take slots in order, so that the previous chunk is more likely to be transcribed for the prompt
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		chunkOptions := longAudioChunkOptions(options.Transcription)
+		if i > 0 {
+			delete(chunkOptions, "prompt")
+
+			if !options.DisablePromptChaining {
+				select {
+				case <-finished[i-1]:
+					if prompt := longAudioPrompt(transcriptionText(transcriptions[i-1])); prompt != "" {
+						chunkOptions.SetPrompt(prompt)
+					}
+				default:
+					// the previous chunk is not transcribed yet, so start without its text
+				}
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, chunk audioChunk, chunkOptions TranscriptionOptions) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			defer close(finished[i])
+
+			transcription, err := c.CreateTranscriptionWithContext(ctx, NewFileParamFromBytes(chunk.data), options.Model, chunkOptions)
+			if err != nil {
+				failOnce.Do(func() {
+					failure = fmt.Errorf("failed to transcribe chunk %d/%d (%s - %s): %w", i+1, len(chunks), chunk.start, chunk.end, err)
+					cancel()
+				})
+				return
+			}
+			transcriptions[i] = transcription
+		}(i, chunk, chunkOptions)
+	}
+	wg.Wait()
+
+	if err = parent.Err(); err != nil {
+		return result, err
+	}
+	if failure != nil {
+		return result, failure
+	}
+
+	return stitchTranscriptions(chunks, transcriptions), nil
+}
+
+// returns a copy of `options` for transcribing a chunk
+func longAudioChunkOptions(options TranscriptionOptions) TranscriptionOptions {
+	copied := TranscriptionOptions{}
+	for k, v := range options {
+		copied[k] = v
+	}
+	copied.SetResponseFormat(TranscriptionResponseFormatVerboseJSON)
+
+	granularities := []TranscriptionTimestampGranularity{TranscriptionTimestampGranularitySegment}
+	if requested, exists := options["timestamp_granularities[]"].([]string); exists {
+		for _, granularity := range requested {
+			if granularity == string(TranscriptionTimestampGranularityWord) {
+				granularities = append(granularities, TranscriptionTimestampGranularityWord)
+			}
+		}
+	}
+	copied.SetTimestampGranularities(granularities...)
+
+	return copied
+}
+
+// returns the text of a transcription
+func transcriptionText(transcription Transcription) string {
+	if transcription.Text != nil {
+		return *transcription.Text
+	}
+
+	texts := []string{}
+	for _, segment := range transcription.Segments {
+		texts = append(texts, strings.TrimSpace(segment.Text))
+	}
+	return strings.Join(texts, " ")
+}
+
+// returns the tail of `text` for the prompt of the next chunk, cut on a word boundary
+func longAudioPrompt(text string) string {
+	text = strings.TrimSpace(text)
+
+	runes := []rune(text)
+	if len(runes) <= longAudioPromptMaxChars {
+		return text
+	}
+
+	tail := string(runes[len(runes)-longAudioPromptMaxChars:])
+	if i := strings.IndexFunc(tail, unicode.IsSpace); i >= 0 {
+		tail = tail[i:]
+	}
+	return strings.TrimSpace(tail)
+}
+
+// stitches transcriptions of chunks into one
+//
+// In each overlap, segments and words are taken from the earlier chunk before its midpoint,
+// and from the later chunk after it. Then repeated words around the midpoint are removed.
+func stitchTranscriptions(chunks []audioChunk, transcriptions []Transcription) (result Transcription) {
+	result.Segments = []TranscriptionSegment{}
+
+	hasWords := false
+	for i, transcription := range transcriptions {
+		if i == 0 {
+			result.Task = transcription.Task
+			result.Language = transcription.Language
+		}
+
+		offset := chunks[i].start.Seconds()
+		lower, upper := 0.0, math.Inf(1)
+		if i > 0 {
+			lower = (chunks[i].start.Seconds() + chunks[i-1].end.Seconds()) / 2
+		}
+		if i < len(chunks)-1 {
+			upper = (chunks[i+1].start.Seconds() + chunks[i].end.Seconds()) / 2
+		}
+
+		// segments
+		segments := []TranscriptionSegment{}
+		for _, segment := range transcription.Segments {
+			segment.Start += offset
+			segment.End += offset
+			if segment.Start >= lower && segment.Start < upper {
+				segments = append(segments, segment)
+			}
+		}
+		if len(segments) > 0 && len(result.Segments) > 0 {
+			previous := strings.Fields(result.Segments[len(result.Segments)-1].Text)
+			words := strings.Fields(segments[0].Text)
+			if n := overlappingWords(previous, words); n == len(words) {
+				segments = segments[1:]
+			} else if n > 0 {
+				segments[0].Text = " " + strings.Join(words[n:], " ")
+			}
+		}
+		for _, segment := range segments {
+			segment.ID = len(result.Segments)
+			result.Segments = append(result.Segments, segment)
+		}
+
+		// words
+		words := []TranscriptionWord{}
+		for _, word := range transcription.Words {
+			hasWords = true
+
+			word.Start += offset
+			word.End += offset
+			if word.Start >= lower && word.Start < upper {
+				words = append(words, word)
+			}
+		}
+		if len(words) > 0 && len(result.Words) > 0 {
+			previous, next := []string{}, []string{}
+			for _, w := range result.Words {
+				previous = append(previous, w.Word)
+			}
+			for _, w := range words {
+				next = append(next, w.Word)
+			}
+			words = words[overlappingWords(previous, next):]
+		}
+		result.Words = append(result.Words, words...)
+
+		// usage
+		if transcription.Usage != nil {
+			if result.Usage == nil {
+				result.Usage = &TranscriptionUsage{Type: transcription.Usage.Type}
+			}
+			result.Usage.InputTokens += transcription.Usage.InputTokens
+			result.Usage.OutputTokens += transcription.Usage.OutputTokens
+			result.Usage.TotalTokens += transcription.Usage.TotalTokens
+			if transcription.Usage.Seconds != nil {
+				seconds := *transcription.Usage.Seconds
+				if result.Usage.Seconds != nil {
+					seconds += *result.Usage.Seconds
+				}
+				result.Usage.Seconds = &seconds
+			}
+		}
+	}
+	if !hasWords {
+		result.Words = nil
+	}
+
+	// text
+	texts := []string{}
+	if len(result.Segments) > 0 {
+		for _, segment := range result.Segments {
+			texts = append(texts, strings.TrimSpace(segment.Text))
+		}
+	} else {
+		// no segments (eg. models without `verbose_json`), so just dedup texts
+		words := []string{}
+		for _, transcription := range transcriptions {
+			next := strings.Fields(transcriptionText(transcription))
+			words = append(words, next[overlappingWords(words, next):]...)
+		}
+		texts = words
+	}
+	text := strings.Join(texts, " ")
+	result.Text = &text
+
+	if len(chunks) > 0 {
+		duration := chunks[len(chunks)-1].end.Seconds()
+		result.Duration = &duration
+	}
+
+	return result
+}
+
+// returns the number of leading words of `next` which repeat the trailing words of `previous`
+// (compared case-insensitively, ignoring punctuation)
+func overlappingWords(previous, next []string) int {
+	normalize := func(word string) string {
+		return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}))
+	}
+
+	maxWords := len(previous)
+	if len(next) < maxWords {
+		maxWords = len(next)
+	}
+
+	for n := maxWords; n >= longAudioMinDedupWords; n-- {
+		matched := true
+		for i := 0; i < n; i++ {
+			if normalize(previous[len(previous)-n+i]) != normalize(next[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,201 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTranscribeLongMock(t *testing.T) {
+	// 10 seconds of audio, each sample has the value of its second
+	wav := newTestWAV(1000, 10000, func(i int) int16 { return int16(i / 1000) })
+
+	var mutex sync.Mutex
+	prompts := map[int]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Failed to read file: %v", err)
+			return
+		}
+		bs, _ := io.ReadAll(file)
+		format, err := parseWAV(bs)
+		if err != nil {
+			t.Errorf("Invalid wav chunk: %v", err)
+			return
+		}
+		if r.FormValue("response_format") != "verbose_json" {
+			t.Errorf("Expected verbose_json, got %s", r.FormValue("response_format"))
+		}
+		if granularities := r.MultipartForm.Value["timestamp_granularities[]"]; strings.Join(granularities, ",") != "segment,word" {
+			t.Errorf("Unexpected timestamp_granularities[]: %v", granularities)
+		}
+
+		// chunk starts at the second of its first sample
+		start := int(binary.LittleEndian.Uint16(format.data))
+		seconds := len(format.data) / 2 / 1000
+
+		mutex.Lock()
+		prompts[start] = r.FormValue("prompt")
+		mutex.Unlock()
+
+		// a segment and a word for each second
+		segments := []TranscriptionSegment{}
+		words := []TranscriptionWord{}
+		texts := []string{}
+		for s := 0; s < seconds; s++ {
+			word := fmt.Sprintf("word%d", start+s)
+			segments = append(segments, TranscriptionSegment{ID: s, Start: float64(s), End: float64(s + 1), Text: " " + word})
+			words = append(words, TranscriptionWord{Word: word, Start: float64(s), End: float64(s) + 0.5})
+			texts = append(texts, word)
+		}
+		text := strings.Join(texts, " ")
+		language := "english"
+		duration := float64(seconds)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Transcription{Text: &text, Language: &language, Duration: &duration, Segments: segments, Words: words})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	result, err := client.TranscribeLong(context.Background(), bytes.NewReader(wav), TranscribeLongOptions{
+		Transcription: TranscriptionOptions{}.
+			SetPrompt("A list of words.").
+			SetTimestampGranularities(TranscriptionTimestampGranularityWord),
+		MaxChunkDuration: 4 * time.Second,
+		Overlap:          time.Second,
+		MaxConcurrency:   1, // for chaining all prompts
+	})
+	if err != nil {
+		t.Fatalf("TranscribeLong failed: %v", err)
+	}
+
+	expected := "word0 word1 word2 word3 word4 word5 word6 word7 word8 word9"
+	if result.Text == nil || *result.Text != expected {
+		t.Errorf("Expected '%s', got %v", expected, result.Text)
+	}
+	if len(result.Segments) != 10 {
+		t.Fatalf("Expected 10 segments, got %d: %+v", len(result.Segments), result.Segments)
+	}
+	for i, segment := range result.Segments {
+		if segment.ID != i || segment.Start != float64(i) || strings.TrimSpace(segment.Text) != fmt.Sprintf("word%d", i) {
+			t.Errorf("Unexpected segment %d: %+v", i, segment)
+		}
+	}
+	if len(result.Words) != 10 || result.Words[7].Word != "word7" || result.Words[7].Start != 7 {
+		t.Errorf("Unexpected words: %+v", result.Words)
+	}
+	if result.Duration == nil || *result.Duration != 10 {
+		t.Errorf("Unexpected duration: %v", result.Duration)
+	}
+	if result.Language == nil || *result.Language != "english" {
+		t.Errorf("Unexpected language: %v", result.Language)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if prompts[0] != "A list of words." {
+		t.Errorf("Expected the given prompt for the first chunk, got '%s'", prompts[0])
+	}
+	if prompts[3] != "word0 word1 word2 word3" || prompts[6] != "word3 word4 word5 word6" {
+		t.Errorf("Expected the previous chunks' texts as prompts, got %v", prompts)
+	}
+}
+
+func TestTranscribeLongConcurrency(t *testing.T) {
+	wav := newTestWAV(1000, 10000, func(i int) int16 { return 0 })
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(100 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"words","segments":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	for _, tc := range []struct {
+		maxConcurrency int
+		expected       int
+	}{
+		{0, 4}, // default: all of 4 chunks in parallel
+		{1, 1},
+	} {
+		mutex.Lock()
+		maxRunning = 0
+		mutex.Unlock()
+
+		if _, err := client.TranscribeLong(context.Background(), bytes.NewReader(wav), TranscribeLongOptions{
+			MaxChunkDuration: 4 * time.Second,
+			MaxConcurrency:   tc.maxConcurrency,
+		}); err != nil {
+			t.Fatalf("TranscribeLong failed: %v", err)
+		}
+
+		mutex.Lock()
+		if maxRunning != tc.expected {
+			t.Errorf("Expected %d chunks in parallel with MaxConcurrency: %d, got %d", tc.expected, tc.maxConcurrency, maxRunning)
+		}
+		mutex.Unlock()
+	}
+}
+
+func TestTranscribeLongError(t *testing.T) {
+	wav := newTestWAV(1000, 10000, func(i int) int16 { return 0 })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Invalid file format.","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	_, err := client.TranscribeLong(context.Background(), bytes.NewReader(wav), TranscribeLongOptions{
+		MaxChunkDuration:      4 * time.Second,
+		DisablePromptChaining: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "Invalid file format.") {
+		t.Errorf("Expected transcription error, got %v", err)
+	}
+}
+
+func TestOverlappingWords(t *testing.T) {
+	previous := strings.Fields("and then we went to the park.")
+	if n := overlappingWords(previous, strings.Fields("The park, was empty.")); n != 2 {
+		t.Errorf("Expected 2 overlapping words, got %d", n)
+	}
+	if n := overlappingWords(previous, strings.Fields("park was empty")); n != 0 {
+		t.Errorf("Expected no overlap for a single word, got %d", n)
+	}
+}
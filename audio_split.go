@@ -0,0 +1,334 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// splitting audio files (WAV and MP3) into chunks without decoding them
+
+// a chunk of split audio
+type audioChunk struct {
+	data []byte
+
+	// position of this chunk in the original audio
+	start time.Duration
+	end   time.Duration
+}
+
+// splits audio `data` into chunks no larger than `maxBytes` and no longer than `maxDuration` (0 for no limit),
+// overlapping by `overlap`.
+//
+// WAV is split on sample boundaries, and MP3 on frame boundaries.
+// Data which fits in a single chunk is returned as it is, in any format.
+func splitAudio(data []byte, maxBytes int, maxDuration, overlap time.Duration) (chunks []audioChunk, err error) {
+	if isWAV(data) {
+		return splitWAV(data, maxBytes, maxDuration, overlap)
+	}
+	if isMP3(data) {
+		return splitMP3(data, maxBytes, maxDuration, overlap)
+	}
+
+	if len(data) <= maxBytes && maxDuration <= 0 {
+		return []audioChunk{{data: data}}, nil
+	}
+	return nil, fmt.Errorf("unsupported audio format for splitting: only WAV and MP3 are supported")
+}
+
+// checks if `data` is a RIFF WAVE file
+func isWAV(data []byte) bool {
+	return len(data) >= 12 &&
+		bytes.Equal(data[0:4], []byte("RIFF")) &&
+		bytes.Equal(data[8:12], []byte("WAVE"))
+}
+
+// checks if `data` is an MP3 file (with an ID3v2 tag, or starting with a frame)
+func isMP3(data []byte) bool {
+	if len(data) >= 3 && bytes.Equal(data[0:3], []byte("ID3")) {
+		return true
+	}
+	_, ok := parseMP3FrameHeader(data)
+	return ok
+}
+
+// WAV format values needed for splitting
+type wavFormat struct {
+	fmtChunk   []byte // whole `fmt ` chunk, including its header
+	sampleRate int
+	blockAlign int
+	data       []byte // samples in `data` chunk
+}
+
+// parses chunks of a WAV file
+func parseWAV(data []byte) (format wavFormat, err error) {
+	offset := 12
+	for offset+8 <= len(data) {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 || body+size > len(data) {
+				return format, fmt.Errorf("invalid wav fmt chunk")
+			}
+			format.fmtChunk = data[offset : body+size]
+			format.sampleRate = int(binary.LittleEndian.Uint32(data[body+4 : body+8]))
+			format.blockAlign = int(binary.LittleEndian.Uint16(data[body+12 : body+14]))
+		case "data":
+			// some encoders write 0 or a too large size for streamed data
+			end := body + size
+			if size == 0 || end > len(data) {
+				end = len(data)
+			}
+			format.data = data[body:end]
+		}
+		if format.fmtChunk != nil && format.data != nil {
+			break
+		}
+
+		offset = body + size + size%2 // chunks are word-aligned
+	}
+
+	if format.fmtChunk == nil || format.data == nil {
+		return format, fmt.Errorf("invalid wav file: no fmt or data chunk")
+	}
+	if format.sampleRate <= 0 || format.blockAlign <= 0 {
+		return format, fmt.Errorf("invalid wav format: sample rate = %d, block align = %d", format.sampleRate, format.blockAlign)
+	}
+	return format, nil
+}
+
+// builds a WAV file with given `fmt ` chunk and `samples`
+func buildWAV(fmtChunk, samples []byte) []byte {
+	size := 4 + len(fmtChunk) + 8 + len(samples) + len(samples)%2
+
+	buf := make([]byte, 0, 8+size)
+	buf = append(buf, "RIFF"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(size))
+	buf = append(buf, "WAVE"...)
+	buf = append(buf, fmtChunk...)
+	buf = append(buf, "data"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(samples)))
+	buf = append(buf, samples...)
+	if len(samples)%2 != 0 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+// splits a WAV file on sample boundaries
+func splitWAV(data []byte, maxBytes int, maxDuration, overlap time.Duration) (chunks []audioChunk, err error) {
+	var format wavFormat
+	if format, err = parseWAV(data); err != nil {
+		return nil, err
+	}
+
+	totalSamples := len(format.data) / format.blockAlign
+	headerSize := 12 + len(format.fmtChunk) + 8
+
+	chunkSamples := (maxBytes - headerSize) / format.blockAlign
+	if maxDuration > 0 {
+		if bySamples := int(int64(maxDuration) * int64(format.sampleRate) / int64(time.Second)); bySamples < chunkSamples {
+			chunkSamples = bySamples
+		}
+	}
+	if chunkSamples <= 0 {
+		return nil, fmt.Errorf("chunk size is too small for this wav file")
+	}
+
+	overlapSamples := int(int64(overlap) * int64(format.sampleRate) / int64(time.Second))
+	if overlapSamples > chunkSamples/2 {
+		overlapSamples = chunkSamples / 2
+	}
+
+	toDuration := func(samples int) time.Duration {
+		return time.Duration(int64(samples) * int64(time.Second) / int64(format.sampleRate))
+	}
+
+	chunks = []audioChunk{}
+	for start := 0; ; start += chunkSamples - overlapSamples {
+		end := start + chunkSamples
+		if end > totalSamples {
+			end = totalSamples
+		}
+
+		chunks = append(chunks, audioChunk{
+			data:  buildWAV(format.fmtChunk, format.data[start*format.blockAlign:end*format.blockAlign]),
+			start: toDuration(start),
+			end:   toDuration(end),
+		})
+
+		if end >= totalSamples {
+			break
+		}
+	}
+
+	return chunks, nil
+}
+
+// an MP3 frame
+type mp3Frame struct {
+	offset   int
+	length   int
+	duration time.Duration
+}
+
+// bitrates (kbps) of MPEG audio, indexed by [version is MPEG-1 ? 0 : 1][layer - 1][bitrate index]
+var mp3Bitrates = [2][3][16]int{
+	{ // MPEG-1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0}, // layer I
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},    // layer II
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},     // layer III
+	},
+	{ // MPEG-2, MPEG-2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0}, // layer I
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // layer II
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // layer III
+	},
+}
+
+// sample rates (Hz) of MPEG audio, indexed by [version bits][sample rate index]
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},  // MPEG-2.5
+	{0, 0, 0},             // reserved
+	{22050, 24000, 16000}, // MPEG-2
+	{44100, 48000, 32000}, // MPEG-1
+}
+
+// parses an MP3 frame header at the beginning of `data`
+func parseMP3FrameHeader(data []byte) (frame mp3Frame, ok bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1]&0xE0 != 0xE0 {
+		return frame, false
+	}
+
+	version := int(data[1]>>3) & 0x03
+	layerBits := int(data[1]>>1) & 0x03
+	bitrateIndex := int(data[2]>>4) & 0x0F
+	sampleRateIndex := int(data[2]>>2) & 0x03
+	padding := int(data[2]>>1) & 0x01
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return frame, false
+	}
+
+	layer := 4 - layerBits // 1, 2, or 3
+	mpeg1 := version == 3
+	table := 1
+	if mpeg1 {
+		table = 0
+	}
+	bitrate := mp3Bitrates[table][layer-1][bitrateIndex] * 1000
+	sampleRate := mp3SampleRates[version][sampleRateIndex]
+
+	var samples int
+	switch {
+	case layer == 1:
+		samples = 384
+		frame.length = (12*bitrate/sampleRate + padding) * 4
+	case layer == 2 || mpeg1:
+		samples = 1152
+		frame.length = 144*bitrate/sampleRate + padding
+	default: // layer III of MPEG-2 and MPEG-2.5
+		samples = 576
+		frame.length = 72*bitrate/sampleRate + padding
+	}
+	frame.duration = time.Duration(int64(samples) * int64(time.Second) / int64(sampleRate))
+
+	return frame, frame.length > 4
+}
+
+// returns the size of an ID3v2 tag at the beginning of `data`, or 0 if there is none
+func id3v2TagSize(data []byte) int {
+	if len(data) < 10 || !bytes.Equal(data[0:3], []byte("ID3")) {
+		return 0
+	}
+
+	size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+	size += 10
+	if data[5]&0x10 != 0 { // footer
+		size += 10
+	}
+	return size
+}
+
+// scans MP3 frames in `data`, skipping ID3 tags, garbage between frames, and the Xing/Info frame
+func scanMP3Frames(data []byte) (frames []mp3Frame) {
+	frames = []mp3Frame{}
+
+	offset := id3v2TagSize(data)
+	for offset+4 <= len(data) {
+		frame, ok := parseMP3FrameHeader(data[offset:])
+		if !ok || offset+frame.length > len(data) {
+			offset++ // resync
+			continue
+		}
+		frame.offset = offset
+
+		// skip the Xing/Info frame (which only has metadata for the whole file)
+		head := data[offset : offset+minInt(frame.length, 64)]
+		if len(frames) == 0 && (bytes.Contains(head, []byte("Xing")) || bytes.Contains(head, []byte("Info"))) {
+			offset += frame.length
+			continue
+		}
+
+		frames = append(frames, frame)
+		offset += frame.length
+	}
+
+	return frames
+}
+
+// splits an MP3 file on frame boundaries
+func splitMP3(data []byte, maxBytes int, maxDuration, overlap time.Duration) (chunks []audioChunk, err error) {
+	frames := scanMP3Frames(data)
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("invalid mp3 file: no frames found")
+	}
+
+	chunks = []audioChunk{}
+	var position time.Duration // start time of frames[start]
+	for start := 0; ; {
+		// take frames as many as possible
+		size, duration := 0, time.Duration(0)
+		end := start
+		for end < len(frames) {
+			if end > start &&
+				(size+frames[end].length > maxBytes || (maxDuration > 0 && duration+frames[end].duration > maxDuration)) {
+				break
+			}
+			size += frames[end].length
+			duration += frames[end].duration
+			end++
+		}
+
+		buf := make([]byte, 0, size)
+		for _, frame := range frames[start:end] {
+			buf = append(buf, data[frame.offset:frame.offset+frame.length]...)
+		}
+		chunks = append(chunks, audioChunk{
+			data:  buf,
+			start: position,
+			end:   position + duration,
+		})
+
+		if end >= len(frames) {
+			break
+		}
+
+		// go back for the overlap, but always move forward
+		next := end
+		var overlapped time.Duration
+		for next > start+1 && overlapped+frames[next-1].duration <= overlap && end-next < (end-start)/2 {
+			next--
+			overlapped += frames[next].duration
+		}
+		for _, frame := range frames[start:next] {
+			position += frame.duration
+		}
+		start = next
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// returns a 16-bit mono PCM WAV file with given `sampleRate`, with each sample of `value(index)`
+func newTestWAV(sampleRate, samples int, value func(index int) int16) []byte {
+	fmtChunk := []byte("fmt ")
+	fmtChunk = binary.LittleEndian.AppendUint32(fmtChunk, 16)
+	fmtChunk = binary.LittleEndian.AppendUint16(fmtChunk, 1) // PCM
+	fmtChunk = binary.LittleEndian.AppendUint16(fmtChunk, 1) // mono
+	fmtChunk = binary.LittleEndian.AppendUint32(fmtChunk, uint32(sampleRate))
+	fmtChunk = binary.LittleEndian.AppendUint32(fmtChunk, uint32(sampleRate*2))
+	fmtChunk = binary.LittleEndian.AppendUint16(fmtChunk, 2)  // block align
+	fmtChunk = binary.LittleEndian.AppendUint16(fmtChunk, 16) // bits per sample
+
+	data := []byte{}
+	for i := 0; i < samples; i++ {
+		data = binary.LittleEndian.AppendUint16(data, uint16(value(i)))
+	}
+	return buildWAV(fmtChunk, data)
+}
+
+// returns an MP3 file with an ID3v2 tag and `n` frames (MPEG-1 layer III, 128kbps, 44.1kHz)
+func newTestMP3(n int) []byte {
+	data := []byte("ID3\x03\x00\x00\x00\x00\x00\x0a")
+	data = append(data, make([]byte, 10)...)
+	for i := 0; i < n; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+		frame[4] = byte(i)
+		data = append(data, frame...)
+	}
+	return data
+}
+
+func TestSplitWAV(t *testing.T) {
+	wav := newTestWAV(1000, 10000, func(i int) int16 { return int16(i / 1000) })
+
+	chunks, err := splitAudio(wav, 1024*1024, 4*time.Second, time.Second)
+	if err != nil {
+		t.Fatalf("splitAudio failed: %v", err)
+	}
+
+	expected := [][2]time.Duration{{0, 4 * time.Second}, {3 * time.Second, 7 * time.Second}, {6 * time.Second, 10 * time.Second}}
+	if len(chunks) != len(expected) {
+		t.Fatalf("Expected %d chunks, got %d", len(expected), len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.start != expected[i][0] || chunk.end != expected[i][1] {
+			t.Errorf("Expected chunk %d at %v, got %v - %v", i, expected[i], chunk.start, chunk.end)
+		}
+
+		format, err := parseWAV(chunk.data)
+		if err != nil {
+			t.Fatalf("Chunk %d is not a valid wav: %v", i, err)
+		}
+		if first := int16(binary.LittleEndian.Uint16(format.data)); int(first) != int(chunk.start/time.Second) {
+			t.Errorf("Expected chunk %d to start with sample of second %d, got %d", i, chunk.start/time.Second, first)
+		}
+	}
+
+	// by size
+	chunks, err = splitAudio(wav, 44+2000, 0, 0)
+	if err != nil {
+		t.Fatalf("splitAudio failed: %v", err)
+	}
+	if len(chunks) != 10 || len(chunks[0].data) > 44+2000 {
+		t.Errorf("Expected 10 chunks no larger than the limit, got %d", len(chunks))
+	}
+}
+
+func TestSplitMP3(t *testing.T) {
+	mp3 := newTestMP3(100)
+
+	chunks, err := splitAudio(mp3, 417*40, 0, 5*26*time.Millisecond)
+	if err != nil {
+		t.Fatalf("splitAudio failed: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+
+	frameDuration := time.Duration(1152 * int64(time.Second) / 44100)
+	for i, chunk := range chunks {
+		if len(chunk.data)%417 != 0 || len(chunk.data) > 417*40 {
+			t.Errorf("Chunk %d is not split on frame boundaries: %d bytes", i, len(chunk.data))
+		}
+		if !bytes.HasPrefix(chunk.data, []byte{0xFF, 0xFB}) {
+			t.Errorf("Chunk %d does not start with a frame header", i)
+		}
+		if first := int(chunk.data[4]); time.Duration(first)*frameDuration != chunk.start {
+			t.Errorf("Expected chunk %d to start at frame %d, got %v", i, first, chunk.start)
+		}
+	}
+	if chunks[1].start != 36*frameDuration {
+		t.Errorf("Expected the second chunk to overlap by 4 frames, got start %v", chunks[1].start)
+	}
+	if chunks[2].end != 100*frameDuration {
+		t.Errorf("Expected the last chunk to end at %v, got %v", 100*frameDuration, chunks[2].end)
+	}
+
+	if _, err := splitAudio([]byte("not an audio file"), 4, 0, 0); err == nil {
+		t.Errorf("Expected an error for unsupported format")
+	}
+	if chunks, err := splitAudio([]byte("small"), 1024, 0, 0); err != nil || len(chunks) != 1 {
+		t.Errorf("Expected small data as a single chunk, got %v, %v", chunks, err)
+	}
+}
@@ -1,7 +1,10 @@
 package openai
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -65,3 +68,89 @@ func TestAudio(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateTranscriptionVerboseJSONMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1024 * 1024); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+			return
+		}
+
+		values := r.MultipartForm.Value
+		if granularities := values["timestamp_granularities[]"]; strings.Join(granularities, ",") != "word,segment" {
+			t.Errorf("Expected repeated timestamp_granularities[], got %v", granularities)
+		}
+		if include := values["include[]"]; strings.Join(include, ",") != "logprobs" {
+			t.Errorf("Expected include[]=logprobs, got %v", include)
+		}
+		if strategy := values["chunking_strategy"]; len(strategy) != 1 || strategy[0] != `{"type":"server_vad","silence_duration_ms":500}` {
+			t.Errorf("Unexpected chunking_strategy: %v", strategy)
+		}
+		if format := values["response_format"]; len(format) != 1 || format[0] != "verbose_json" {
+			t.Errorf("Unexpected response_format: %v", format)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"task":"transcribe","language":"english","duration":2.5,"text":"Hello world.",` +
+			`"segments":[{"id":0,"seek":0,"start":0.0,"end":2.5,"text":"Hello world.","tokens":[50364,2425],"temperature":0.0,"avg_logprob":-0.25,"compression_ratio":0.8,"no_speech_prob":0.01}],` +
+			`"words":[{"word":"Hello","start":0.0,"end":1.0},{"word":"world","start":1.2,"end":2.4}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	silence := 500
+	strategy := NewTranscriptionServerVADChunkingStrategy()
+	strategy.SilenceDurationMs = &silence
+
+	transcription, err := client.CreateTranscription(NewFileParamFromBytes([]byte("fake audio")), audioModel, TranscriptionOptions{}.
+		SetResponseFormat(TranscriptionResponseFormatVerboseJSON).
+		SetTimestampGranularities(TranscriptionTimestampGranularityWord, TranscriptionTimestampGranularitySegment).
+		SetInclude(TranscriptionIncludeLogprobs).
+		SetChunkingStrategy(strategy))
+	if err != nil {
+		t.Fatalf("CreateTranscription failed: %v", err)
+	}
+
+	if transcription.Text == nil || *transcription.Text != "Hello world." {
+		t.Errorf("Unexpected text: %v", transcription.Text)
+	}
+	if transcription.VerboseJSON == nil {
+		t.Errorf("Expected raw verbose_json response")
+	}
+	if transcription.Duration == nil || *transcription.Duration != 2.5 {
+		t.Errorf("Unexpected duration: %v", transcription.Duration)
+	}
+	if len(transcription.Segments) != 1 ||
+		transcription.Segments[0].End != 2.5 ||
+		transcription.Segments[0].AvgLogprob != -0.25 ||
+		transcription.Segments[0].NoSpeechProb != 0.01 {
+		t.Errorf("Unexpected segments: %+v", transcription.Segments)
+	}
+	if len(transcription.Words) != 2 || transcription.Words[1].Word != "world" || transcription.Words[1].Start != 1.2 {
+		t.Errorf("Unexpected words: %+v", transcription.Words)
+	}
+}
+
+func TestCreateTranslationSRTMock(t *testing.T) {
+	srt := "1\n00:00:00,000 --> 00:00:02,500\nHello world.\n\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	translation, err := client.CreateTranslation(NewFileParamFromBytes([]byte("fake audio")), audioModel, TranslationOptions{}.
+		SetResponseFormat(TranslationResponseFormat(TranscriptionResponseFormatSRT)))
+	if err != nil {
+		t.Fatalf("CreateTranslation failed: %v", err)
+	}
+	if translation.SRT == nil || *translation.SRT != srt {
+		t.Errorf("Expected SRT '%s', got %v", srt, translation.SRT)
+	}
+}
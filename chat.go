@@ -63,8 +63,19 @@ type ChatMessageContent struct {
 	ImageURL any     `json:"image_url,omitempty"`
 	Filename *string `json:"filename,omitempty"`  // optional filename for the file data
 	FileData *string `json:"file_data,omitempty"` // base64-encoded file data
+
+	Detail *ImageDetail `json:"detail,omitempty"` // for `input_image` of responses API
 }
 
+// ImageDetail type for constants
+type ImageDetail string
+
+const (
+	ImageDetailLow  ImageDetail = "low"
+	ImageDetailHigh ImageDetail = "high"
+	ImageDetailAuto ImageDetail = "auto"
+)
+
 // NewChatMessageContentWithText returns a ChatMessageContent struct with given `text`.
 func NewChatMessageContentWithText(text string) ChatMessageContent {
 	return ChatMessageContent{
@@ -96,6 +107,58 @@ func NewChatMessageContentWithBytes(bytes []byte) ChatMessageContent {
 	}
 }
 
+// NewResponseInputImageWithURL returns a ChatMessageContent struct of `input_image` with given `url`, for responses API.
+func NewResponseInputImageWithURL(url string) ChatMessageContent {
+	return ChatMessageContent{
+		Type:     "input_image",
+		ImageURL: url,
+	}
+}
+
+// NewResponseInputImageWithBytes returns a ChatMessageContent struct of `input_image` with given `bytes`, for responses API.
+func NewResponseInputImageWithBytes(bytes []byte) ChatMessageContent {
+	return NewResponseInputImageWithURL(bytesToDataURL(bytes))
+}
+
+// WithDetail returns a copy of an image content with given `detail`.
+//
+// It sets `image_url.detail` of `image_url` contents, and `detail` of `input_image` contents.
+//
+// https://platform.openai.com/docs/guides/images-vision#specify-image-input-detail-level
+func (c ChatMessageContent) WithDetail(detail ImageDetail) ChatMessageContent {
+	switch c.Type {
+	case "image_url":
+		url, _ := imageURLAndDetail(c.ImageURL)
+		c.ImageURL = map[string]string{
+			"url":    url,
+			"detail": string(detail),
+		}
+	case "input_image":
+		c.Detail = &detail
+	}
+	return c
+}
+
+// ImageTokens returns the estimated token cost of an image content for `model`, or 0 if it is not an image.
+//
+// Dimensions are read from base64-encoded data URLs; for other URLs,
+// the largest possible cost for its detail is returned.
+func (c ChatMessageContent) ImageTokens(model string) int {
+	switch c.Type {
+	case "image_url":
+		url, detail := imageURLAndDetail(c.ImageURL)
+		return ImageTokensForURL(model, url, detail)
+	case "input_image":
+		url, _ := imageURLAndDetail(c.ImageURL)
+		detail := ""
+		if c.Detail != nil {
+			detail = string(*c.Detail)
+		}
+		return ImageTokensForURL(model, url, detail)
+	}
+	return 0
+}
+
 // NewChatMessageContentFileWithBytes returns a ChatMessageContent struct with given file `bytes`.
 func NewChatMessageContentFileWithBytes(bytes []byte, filename string) ChatMessageContent {
 	base64 := bytesToDataURL(bytes)
@@ -121,6 +184,9 @@ type ChatMessage struct {
 	// for function call
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // when role == 'assistant'
 	ToolCallID *string    `json:"tool_call_id,omitempty"` // when role == 'tool'
+
+	// for structured outputs
+	Refusal *string `json:"refusal,omitempty"` // when role == 'assistant'
 }
 
 // ContentString tries to return the `content` value as a string.
@@ -167,6 +233,7 @@ type ChatCompletionToolFunction struct {
 	Name        string                 `json:"name"`
 	Description *string                `json:"description,omitempty"`
 	Parameters  ToolFunctionParameters `json:"parameters"`
+	Strict      *bool                  `json:"strict,omitempty"`
 }
 
 // NewChatCompletionTool returns a ChatCompletionTool.
@@ -310,7 +377,18 @@ type ChatCompletion struct {
 
 // ChatCompletionResponseFormat struct for chat completion request
 type ChatCompletionResponseFormat struct {
-	Type ChatCompletionResponseFormatType `json:"type,omitempty"`
+	Type       ChatCompletionResponseFormatType        `json:"type,omitempty"`
+	JSONSchema *ChatCompletionResponseFormatJSONSchema `json:"json_schema,omitempty"` // when type == 'json_schema'
+}
+
+// ChatCompletionResponseFormatJSONSchema struct for `json_schema` response format
+//
+// https://platform.openai.com/docs/api-reference/chat/create#chat-create-response_format
+type ChatCompletionResponseFormatJSONSchema struct {
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Strict      *bool          `json:"strict,omitempty"`
 }
 
 // ChatCompletionResponseFormatType type for constants
@@ -320,6 +398,7 @@ type ChatCompletionResponseFormatType string
 const (
 	ChatCompletionResponseFormatTypeText       ChatCompletionResponseFormatType = "text"
 	ChatCompletionResponseFormatTypeJSONObject ChatCompletionResponseFormatType = "json_object"
+	ChatCompletionResponseFormatTypeJSONSchema ChatCompletionResponseFormatType = "json_schema"
 )
 
 // ChatCompletionOptions for creating chat completions
@@ -1,9 +1,14 @@
 package openai
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 const (
@@ -87,6 +92,52 @@ func TestChatCompletionsStream(t *testing.T) {
 	}
 }
 
+// stream closed without `[DONE]` still ends with a done callback
+func TestChatCompletionsStreamWithoutDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`+"\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	type completion struct {
+		response ChatCompletion
+		done     bool
+		err      error
+	}
+	ch := make(chan completion, 10)
+	if err := client.CreateChatCompletionStreamWithContext(context.Background(), chatCompletionModel, []ChatMessage{NewChatUserMessage("Hello!")}, nil, func(response ChatCompletion, done bool, err error) {
+		ch <- completion{response: response, done: done, err: err}
+	}); err != nil {
+		t.Fatalf("failed to create chat completion with stream: %s", err)
+	}
+
+	completions := []completion{}
+	for len(completions) == 0 || !completions[len(completions)-1].done {
+		select {
+		case comp := <-ch:
+			completions = append(completions, comp)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("stream did not finish, got %+v", completions)
+		}
+	}
+
+	if len(completions) != 2 || completions[0].done {
+		t.Fatalf("Expected a chunk and a done callback, got %+v", completions)
+	}
+	if last := completions[1]; last.err != nil || len(last.response.Choices) != 0 {
+		t.Errorf("Expected an empty done callback without error, got %+v", last)
+	}
+	select {
+	case comp := <-ch:
+		t.Errorf("Unexpected callback after done: %+v", comp)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // === CreateChatCompletion (function) ===
 //
 // example from: https://platform.openai.com/docs/guides/function-calling/parallel-function-calling
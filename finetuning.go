@@ -16,21 +16,56 @@ type FineTuningJobs struct {
 }
 
 // FineTuningJob struct
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/object
 type FineTuningJob struct {
 	CommonResponse
 
-	ID              string                    `json:"id"`
-	CreatedAt       int64                     `json:"created_at"`
-	FinishedAt      int64                     `json:"finished_at"`
-	Model           string                    `json:"model"`
-	FineTunedModel  *string                   `json:"fine_tuned_model,omitempty"`
-	OrganizationID  string                    `json:"organization_id"`
-	Status          FineTuningJobStatus       `json:"status"`
-	Hyperparameters FineTuningHyperparameters `json:"hyperparameters"`
-	TrainingFile    string                    `json:"training_file"`
-	ValidationFile  *string                   `json:"validation_file,omitempty"`
-	ResultFiles     []string                  `json:"result_files"`
-	TrainedTokens   int                       `json:"trained_tokens"`
+	ID                 string                    `json:"id"`
+	CreatedAt          int64                     `json:"created_at"`
+	FinishedAt         int64                     `json:"finished_at"`
+	EstimatedFinish    *int64                    `json:"estimated_finish,omitempty"`
+	Model              string                    `json:"model"`
+	FineTunedModel     *string                   `json:"fine_tuned_model,omitempty"`
+	OrganizationID     string                    `json:"organization_id"`
+	Status             FineTuningJobStatus       `json:"status"`
+	JobError           *FineTuningJobError       `json:"error,omitempty"` // NOTE: not nil only when the job failed; API errors go to `CommonResponse.Error`
+	Hyperparameters    FineTuningHyperparameters `json:"hyperparameters"`
+	Method             *FineTuningMethod         `json:"method,omitempty"`
+	TrainingFile       string                    `json:"training_file"`
+	ValidationFile     *string                   `json:"validation_file,omitempty"`
+	ResultFiles        []string                  `json:"result_files"`
+	TrainedTokens      int                       `json:"trained_tokens"`
+	Integrations       []FineTuningIntegration   `json:"integrations,omitempty"`
+	Seed               int64                     `json:"seed"`
+	UserProvidedSuffix *string                   `json:"user_provided_suffix,omitempty"`
+	Metadata           map[string]string         `json:"metadata,omitempty"`
+}
+
+// UnmarshalJSON decodes `error` of a fine-tuning job into `JobError`, and `error` of an API error response into `CommonResponse.Error`
+func (j *FineTuningJob) UnmarshalJSON(data []byte) error {
+	type fineTuningJob FineTuningJob // without this method
+
+	var job fineTuningJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return err
+	}
+	*j = FineTuningJob(job)
+
+	// not a fine-tuning job, but an API error response
+	if j.ID == "" && j.JobError != nil {
+		j.JobError = nil
+		return json.Unmarshal(data, &j.CommonResponse)
+	}
+
+	return nil
+}
+
+// FineTuningJobError struct for failed fine-tuning jobs
+type FineTuningJobError struct {
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+	Param   *string `json:"param,omitempty"`
 }
 
 // FineTuningJobStatus type
@@ -38,17 +73,161 @@ type FineTuningJobStatus string
 
 // FineTuningJobStatus constants
 const (
-	FineTuningJobStatusCreated   FineTuningJobStatus = "created"
-	FineTuningJobStatusPending   FineTuningJobStatus = "pending"
-	FineTuningJobStatusRunning   FineTuningJobStatus = "running"
-	FineTuningJobStatusSucceeded FineTuningJobStatus = "succeeded"
-	FineTuningJobStatusFailed    FineTuningJobStatus = "failed"
-	FineTuningJobStatusCancelled FineTuningJobStatus = "cancelled"
+	FineTuningJobStatusValidatingFiles FineTuningJobStatus = "validating_files"
+	FineTuningJobStatusQueued          FineTuningJobStatus = "queued"
+	FineTuningJobStatusCreated         FineTuningJobStatus = "created"
+	FineTuningJobStatusPending         FineTuningJobStatus = "pending"
+	FineTuningJobStatusRunning         FineTuningJobStatus = "running"
+	FineTuningJobStatusPaused          FineTuningJobStatus = "paused"
+	FineTuningJobStatusSucceeded       FineTuningJobStatus = "succeeded"
+	FineTuningJobStatusFailed          FineTuningJobStatus = "failed"
+	FineTuningJobStatusCancelled       FineTuningJobStatus = "cancelled"
 )
 
 // FineTuningHyperparameters struct
+//
+// Each value is either string("auto") or a number.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-method
 type FineTuningHyperparameters struct {
-	NEpochs any `json:"n_epochs"` // string("Auto") or int
+	NEpochs                any `json:"n_epochs,omitempty"`                 // string("auto") or int
+	BatchSize              any `json:"batch_size,omitempty"`               // string("auto") or int
+	LearningRateMultiplier any `json:"learning_rate_multiplier,omitempty"` // string("auto") or float64
+
+	// for method: 'dpo'
+	Beta any `json:"beta,omitempty"` // string("auto") or float64
+
+	// for method: 'reinforcement'
+	ComputeMultiplier any     `json:"compute_multiplier,omitempty"` // string("auto") or float64
+	EvalInterval      any     `json:"eval_interval,omitempty"`      // string("auto") or int
+	EvalSamples       any     `json:"eval_samples,omitempty"`       // string("auto") or int
+	ReasoningEffort   *string `json:"reasoning_effort,omitempty"`   // 'default' | 'low' | 'medium' | 'high'
+}
+
+// FineTuningMethodType type for constants
+type FineTuningMethodType string
+
+// FineTuningMethodType constants
+const (
+	FineTuningMethodTypeSupervised    FineTuningMethodType = "supervised"
+	FineTuningMethodTypeDPO           FineTuningMethodType = "dpo"
+	FineTuningMethodTypeReinforcement FineTuningMethodType = "reinforcement"
+)
+
+// FineTuningMethod struct for the `method` of fine-tuning jobs
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-method
+type FineTuningMethod struct {
+	Type FineTuningMethodType `json:"type"`
+
+	Supervised    *FineTuningMethodSupervised    `json:"supervised,omitempty"`    // Type == FineTuningMethodTypeSupervised
+	DPO           *FineTuningMethodDPO           `json:"dpo,omitempty"`           // Type == FineTuningMethodTypeDPO
+	Reinforcement *FineTuningMethodReinforcement `json:"reinforcement,omitempty"` // Type == FineTuningMethodTypeReinforcement
+}
+
+// FineTuningMethodSupervised struct for FineTuningMethod
+type FineTuningMethodSupervised struct {
+	Hyperparameters *FineTuningHyperparameters `json:"hyperparameters,omitempty"`
+}
+
+// FineTuningMethodDPO struct for FineTuningMethod
+type FineTuningMethodDPO struct {
+	Hyperparameters *FineTuningHyperparameters `json:"hyperparameters,omitempty"`
+}
+
+// FineTuningMethodReinforcement struct for FineTuningMethod
+type FineTuningMethodReinforcement struct {
+	Grader          map[string]any             `json:"grader"`
+	Hyperparameters *FineTuningHyperparameters `json:"hyperparameters,omitempty"`
+}
+
+// NewFineTuningMethodSupervised returns a FineTuningMethod with type: 'supervised'.
+func NewFineTuningMethodSupervised(hyperparameters *FineTuningHyperparameters) FineTuningMethod {
+	return FineTuningMethod{
+		Type: FineTuningMethodTypeSupervised,
+		Supervised: &FineTuningMethodSupervised{
+			Hyperparameters: hyperparameters,
+		},
+	}
+}
+
+// NewFineTuningMethodDPO returns a FineTuningMethod with type: 'dpo'.
+func NewFineTuningMethodDPO(hyperparameters *FineTuningHyperparameters) FineTuningMethod {
+	return FineTuningMethod{
+		Type: FineTuningMethodTypeDPO,
+		DPO: &FineTuningMethodDPO{
+			Hyperparameters: hyperparameters,
+		},
+	}
+}
+
+// NewFineTuningMethodReinforcement returns a FineTuningMethod with type: 'reinforcement'.
+//
+// https://platform.openai.com/docs/api-reference/graders
+func NewFineTuningMethodReinforcement(grader map[string]any, hyperparameters *FineTuningHyperparameters) FineTuningMethod {
+	return FineTuningMethod{
+		Type: FineTuningMethodTypeReinforcement,
+		Reinforcement: &FineTuningMethodReinforcement{
+			Grader:          grader,
+			Hyperparameters: hyperparameters,
+		},
+	}
+}
+
+// FineTuningIntegration struct for the `integrations` of fine-tuning jobs
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-integrations
+type FineTuningIntegration struct {
+	Type  string                      `json:"type"` // == 'wandb'
+	Wandb *FineTuningIntegrationWandb `json:"wandb,omitempty"`
+}
+
+// FineTuningIntegrationWandb struct for FineTuningIntegration
+type FineTuningIntegrationWandb struct {
+	Project string   `json:"project"`
+	Name    *string  `json:"name,omitempty"`
+	Entity  *string  `json:"entity,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// NewFineTuningIntegrationWandb returns a FineTuningIntegration with type: 'wandb'.
+func NewFineTuningIntegrationWandb(project string) FineTuningIntegration {
+	return FineTuningIntegration{
+		Type: "wandb",
+		Wandb: &FineTuningIntegrationWandb{
+			Project: project,
+		},
+	}
+}
+
+// SetName sets the `name` value of FineTuningIntegration and returns it.
+func (i FineTuningIntegration) SetName(name string) FineTuningIntegration {
+	if i.Wandb != nil {
+		wandb := *i.Wandb
+		wandb.Name = &name
+		i.Wandb = &wandb
+	}
+	return i
+}
+
+// SetEntity sets the `entity` value of FineTuningIntegration and returns it.
+func (i FineTuningIntegration) SetEntity(entity string) FineTuningIntegration {
+	if i.Wandb != nil {
+		wandb := *i.Wandb
+		wandb.Entity = &entity
+		i.Wandb = &wandb
+	}
+	return i
+}
+
+// SetTags sets the `tags` value of FineTuningIntegration and returns it.
+func (i FineTuningIntegration) SetTags(tags []string) FineTuningIntegration {
+	if i.Wandb != nil {
+		wandb := *i.Wandb
+		wandb.Tags = tags
+		i.Wandb = &wandb
+	}
+	return i
 }
 
 // FineTuningJobsOptions for listing fine-tuning jobs
@@ -70,6 +249,16 @@ func (o FineTuningJobsOptions) SetLimit(limit int) FineTuningJobsOptions {
 	return o
 }
 
+// SetMetadata sets the `metadata` filter of fine-tuning jobs listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/list#fine-tuning-list-metadata
+func (o FineTuningJobsOptions) SetMetadata(metadata map[string]string) FineTuningJobsOptions {
+	for k, v := range metadata {
+		o[fmt.Sprintf("metadata[%s]", k)] = v
+	}
+	return o
+}
+
 // FineTuningJobOptions for retrieving fine-tuning jobs
 type FineTuningJobOptions map[string]any
 
@@ -83,6 +272,8 @@ func (o FineTuningJobOptions) SetValidationFile(validationFileID string) FineTun
 
 // SetHyperparameters sets the `hyperparameters` parameter of fine-tuning job request.
 //
+// NOTE: deprecated in favor of `method`, see `SetMethod`.
+//
 // https://platform.openai.com/docs/api-reference/fine-tuning/create#model
 func (o FineTuningJobOptions) SetHyperparameters(hyperparameters FineTuningHyperparameters) FineTuningJobOptions {
 	o["hyperparameters"] = hyperparameters
@@ -97,6 +288,38 @@ func (o FineTuningJobOptions) SetSuffix(suffix string) FineTuningJobOptions {
 	return o
 }
 
+// SetMethod sets the `method` parameter of fine-tuning job request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-method
+func (o FineTuningJobOptions) SetMethod(method FineTuningMethod) FineTuningJobOptions {
+	o["method"] = method
+	return o
+}
+
+// SetIntegrations sets the `integrations` parameter of fine-tuning job request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-integrations
+func (o FineTuningJobOptions) SetIntegrations(integrations []FineTuningIntegration) FineTuningJobOptions {
+	o["integrations"] = integrations
+	return o
+}
+
+// SetSeed sets the `seed` parameter of fine-tuning job request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-seed
+func (o FineTuningJobOptions) SetSeed(seed int64) FineTuningJobOptions {
+	o["seed"] = seed
+	return o
+}
+
+// SetMetadata sets the `metadata` parameter of fine-tuning job request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create#fine-tuning-create-metadata
+func (o FineTuningJobOptions) SetMetadata(metadata map[string]string) FineTuningJobOptions {
+	o["metadata"] = metadata
+	return o
+}
+
 // CreateFineTuningJob creates a job that fine-tunes a specified model from given data
 //
 // https://platform.openai.com/docs/api-reference/fine-tuning/create
@@ -199,6 +422,52 @@ func (c *Client) CancelFineTuningJob(fineTuningJobID string) (response FineTunin
 	return FineTuningJob{}, err
 }
 
+// PauseFineTuningJob pauses a running fine-tuning job.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/pause
+func (c *Client) PauseFineTuningJob(fineTuningJobID string) (response FineTuningJob, err error) {
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("fine_tuning/jobs/%s/pause", fineTuningJobID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningJob{}, err
+}
+
+// ResumeFineTuningJob resumes a paused fine-tuning job.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/resume
+func (c *Client) ResumeFineTuningJob(fineTuningJobID string) (response FineTuningJob, err error) {
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("fine_tuning/jobs/%s/resume", fineTuningJobID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningJob{}, err
+}
+
 // FineTuningJobEvents struct
 type FineTuningJobEvents struct {
 	CommonResponse
@@ -261,3 +530,231 @@ func (c *Client) ListFineTuningJobEvents(fineTuningJobID string, options FineTun
 
 	return FineTuningJobEvents{}, err
 }
+
+// FineTuningJobCheckpoints struct
+type FineTuningJobCheckpoints struct {
+	CommonResponse
+
+	Data    []FineTuningJobCheckpoint `json:"data"`
+	FirstID *string                   `json:"first_id,omitempty"`
+	LastID  *string                   `json:"last_id,omitempty"`
+	HasMore bool                      `json:"has_more"`
+}
+
+// FineTuningJobCheckpoint struct
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/checkpoint-object
+type FineTuningJobCheckpoint struct {
+	CommonResponse
+
+	ID                       string                         `json:"id"`
+	CreatedAt                int64                          `json:"created_at"`
+	FineTunedModelCheckpoint string                         `json:"fine_tuned_model_checkpoint"`
+	StepNumber               int                            `json:"step_number"`
+	Metrics                  FineTuningJobCheckpointMetrics `json:"metrics"`
+	FineTuningJobID          string                         `json:"fine_tuning_job_id"`
+}
+
+// FineTuningJobCheckpointMetrics struct for FineTuningJobCheckpoint
+type FineTuningJobCheckpointMetrics struct {
+	Step                       float64  `json:"step"`
+	TrainLoss                  *float64 `json:"train_loss,omitempty"`
+	TrainMeanTokenAccuracy     *float64 `json:"train_mean_token_accuracy,omitempty"`
+	ValidLoss                  *float64 `json:"valid_loss,omitempty"`
+	ValidMeanTokenAccuracy     *float64 `json:"valid_mean_token_accuracy,omitempty"`
+	FullValidLoss              *float64 `json:"full_valid_loss,omitempty"`
+	FullValidMeanTokenAccuracy *float64 `json:"full_valid_mean_token_accuracy,omitempty"`
+}
+
+// FineTuningJobCheckpointsOptions for listing fine-tuning job checkpoints
+type FineTuningJobCheckpointsOptions map[string]any
+
+// SetAfter sets the `after` parameter of fine-tuning job checkpoints listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/list-checkpoints#fine-tuning-list-checkpoints-after
+func (o FineTuningJobCheckpointsOptions) SetAfter(checkpointID string) FineTuningJobCheckpointsOptions {
+	o["after"] = checkpointID
+	return o
+}
+
+// SetLimit sets the `limit` parameter of fine-tuning job checkpoints listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/list-checkpoints#fine-tuning-list-checkpoints-limit
+func (o FineTuningJobCheckpointsOptions) SetLimit(limit int) FineTuningJobCheckpointsOptions {
+	o["limit"] = limit
+	return o
+}
+
+// ListFineTuningJobCheckpoints lists checkpoints of a given fine-tuning job.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/list-checkpoints
+func (c *Client) ListFineTuningJobCheckpoints(fineTuningJobID string, options FineTuningJobCheckpointsOptions) (response FineTuningJobCheckpoints, err error) {
+	if options == nil {
+		options = FineTuningJobCheckpointsOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("fine_tuning/jobs/%s/checkpoints", fineTuningJobID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningJobCheckpoints{}, err
+}
+
+// FineTuningCheckpointPermissions struct
+type FineTuningCheckpointPermissions struct {
+	CommonResponse
+
+	Data    []FineTuningCheckpointPermission `json:"data"`
+	FirstID *string                          `json:"first_id,omitempty"`
+	LastID  *string                          `json:"last_id,omitempty"`
+	HasMore bool                             `json:"has_more"`
+}
+
+// FineTuningCheckpointPermission struct
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/permission-object
+type FineTuningCheckpointPermission struct {
+	CommonResponse
+
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+	ProjectID string `json:"project_id"`
+}
+
+// FineTuningCheckpointPermissionDeletionStatus struct for API response
+type FineTuningCheckpointPermissionDeletionStatus struct {
+	CommonResponse
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// FineTuningCheckpointPermissionsOptions for listing fine-tuning checkpoint permissions
+type FineTuningCheckpointPermissionsOptions map[string]any
+
+// SetAfter sets the `after` parameter of checkpoint permissions listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/get-checkpoint-permissions#fine-tuning-get-checkpoint-permissions-after
+func (o FineTuningCheckpointPermissionsOptions) SetAfter(permissionID string) FineTuningCheckpointPermissionsOptions {
+	o["after"] = permissionID
+	return o
+}
+
+// SetLimit sets the `limit` parameter of checkpoint permissions listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/get-checkpoint-permissions#fine-tuning-get-checkpoint-permissions-limit
+func (o FineTuningCheckpointPermissionsOptions) SetLimit(limit int) FineTuningCheckpointPermissionsOptions {
+	o["limit"] = limit
+	return o
+}
+
+// SetOrder sets the `order` parameter of checkpoint permissions listing request.
+//
+// `order` can be one of 'ascending' or 'descending'. (default: 'descending')
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/get-checkpoint-permissions#fine-tuning-get-checkpoint-permissions-order
+func (o FineTuningCheckpointPermissionsOptions) SetOrder(order string) FineTuningCheckpointPermissionsOptions {
+	o["order"] = order
+	return o
+}
+
+// SetProjectID sets the `project_id` parameter of checkpoint permissions listing request.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/get-checkpoint-permissions#fine-tuning-get-checkpoint-permissions-project_id
+func (o FineTuningCheckpointPermissionsOptions) SetProjectID(projectID string) FineTuningCheckpointPermissionsOptions {
+	o["project_id"] = projectID
+	return o
+}
+
+// CreateFineTuningCheckpointPermissions grants given projects access to a fine-tuned model checkpoint.
+//
+// NOTE: requires an admin API key.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/create-checkpoint-permission
+func (c *Client) CreateFineTuningCheckpointPermissions(fineTunedModelCheckpoint string, projectIDs []string) (response FineTuningCheckpointPermissions, err error) {
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("fine_tuning/checkpoints/%s/permissions", fineTunedModelCheckpoint), map[string]any{
+		"project_ids": projectIDs,
+	}); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningCheckpointPermissions{}, err
+}
+
+// ListFineTuningCheckpointPermissions lists permissions of a fine-tuned model checkpoint.
+//
+// NOTE: requires an admin API key.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/get-checkpoint-permissions
+func (c *Client) ListFineTuningCheckpointPermissions(fineTunedModelCheckpoint string, options FineTuningCheckpointPermissionsOptions) (response FineTuningCheckpointPermissions, err error) {
+	if options == nil {
+		options = FineTuningCheckpointPermissionsOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("fine_tuning/checkpoints/%s/permissions", fineTunedModelCheckpoint), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningCheckpointPermissions{}, err
+}
+
+// DeleteFineTuningCheckpointPermission deletes a permission of a fine-tuned model checkpoint.
+//
+// NOTE: requires an admin API key.
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/delete-checkpoint-permission
+func (c *Client) DeleteFineTuningCheckpointPermission(fineTunedModelCheckpoint, permissionID string) (response FineTuningCheckpointPermissionDeletionStatus, err error) {
+	var bytes []byte
+	if bytes, err = c.delete(fmt.Sprintf("fine_tuning/checkpoints/%s/permissions/%s", fineTunedModelCheckpoint, permissionID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return FineTuningCheckpointPermissionDeletionStatus{}, err
+}
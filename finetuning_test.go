@@ -1,7 +1,11 @@
 package openai
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -60,3 +64,144 @@ func TestFineTuning(t *testing.T) {
 		}
 	}
 }
+
+func TestFineTuningMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/fine_tuning/jobs":
+			var requestBody map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+
+			method, _ := requestBody["method"].(map[string]any)
+			if method["type"] != "dpo" {
+				t.Errorf("Expected method type 'dpo', got %v", method["type"])
+			}
+			dpo, _ := method["dpo"].(map[string]any)
+			hyperparameters, _ := dpo["hyperparameters"].(map[string]any)
+			if hyperparameters["beta"] != 0.1 || hyperparameters["batch_size"] != "auto" {
+				t.Errorf("Unexpected dpo hyperparameters: %v", hyperparameters)
+			}
+			if _, exists := hyperparameters["n_epochs"]; exists {
+				t.Errorf("Expected unset n_epochs to be omitted, got %v", hyperparameters["n_epochs"])
+			}
+			integrations, _ := requestBody["integrations"].([]any)
+			if len(integrations) != 1 {
+				t.Errorf("Expected 1 integration, got %v", requestBody["integrations"])
+			}
+			if requestBody["seed"] != float64(42) {
+				t.Errorf("Expected seed 42, got %v", requestBody["seed"])
+			}
+
+			w.Write([]byte(`{
+				"object": "fine_tuning.job",
+				"id": "ftjob-abc123",
+				"model": "gpt-4o-mini",
+				"created_at": 1721764800,
+				"status": "validating_files",
+				"training_file": "file-abc123",
+				"result_files": [],
+				"seed": 42,
+				"user_provided_suffix": "custom",
+				"method": {"type": "dpo", "dpo": {"hyperparameters": {"beta": 0.1, "batch_size": "auto"}}},
+				"integrations": [{"type": "wandb", "wandb": {"project": "my-project"}}],
+				"metadata": {"key": "value"}
+			}`))
+		case "/fine_tuning/jobs/ftjob-abc123/pause":
+			w.Write([]byte(`{
+				"object": "fine_tuning.job",
+				"id": "ftjob-abc123",
+				"status": "failed",
+				"error": {"code": "invalid_training_file", "message": "The file is invalid.", "param": "training_file"}
+			}`))
+		case "/fine_tuning/jobs/ftjob-unknown/resume":
+			w.Write([]byte(`{
+				"error": {"message": "No such fine-tuning job: ftjob-unknown", "type": "invalid_request_error", "param": null, "code": "fine_tune_not_found"}
+			}`))
+		case "/fine_tuning/jobs/ftjob-abc123/checkpoints":
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("Expected limit 2, got %s", r.URL.Query().Get("limit"))
+			}
+
+			w.Write([]byte(`{
+				"object": "list",
+				"data": [
+					{
+						"object": "fine_tuning.job.checkpoint",
+						"id": "ftckpt_zc4Q7MP6XxulcVzj4MZdwsAB",
+						"created_at": 1721764867,
+						"fine_tuned_model_checkpoint": "ft:gpt-4o-mini:org:custom:9fakeid:ckpt-step-1000",
+						"metrics": {"step": 1000, "train_loss": 0.5, "full_valid_loss": 0.7},
+						"fine_tuning_job_id": "ftjob-abc123",
+						"step_number": 1000
+					}
+				],
+				"first_id": "ftckpt_zc4Q7MP6XxulcVzj4MZdwsAB",
+				"last_id": "ftckpt_zc4Q7MP6XxulcVzj4MZdwsAB",
+				"has_more": false
+			}`))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// === CreateFineTuningJob ===
+	created, err := client.CreateFineTuningJob("file-abc123", "gpt-4o-mini", FineTuningJobOptions{}.
+		SetMethod(NewFineTuningMethodDPO(&FineTuningHyperparameters{
+			Beta:      0.1,
+			BatchSize: "auto",
+		})).
+		SetIntegrations([]FineTuningIntegration{
+			NewFineTuningIntegrationWandb("my-project").SetTags([]string{"test"}),
+		}).
+		SetSeed(42).
+		SetMetadata(map[string]string{"key": "value"}))
+	if err != nil {
+		t.Errorf("CreateFineTuningJob failed: %v", err)
+	} else {
+		if created.Method == nil || created.Method.DPO == nil || created.Method.DPO.Hyperparameters.Beta != 0.1 {
+			t.Errorf("Expected dpo method with beta 0.1, got %+v", created.Method)
+		}
+		if created.UserProvidedSuffix == nil || *created.UserProvidedSuffix != "custom" {
+			t.Errorf("Expected user_provided_suffix 'custom', got %v", created.UserProvidedSuffix)
+		}
+		if len(created.Integrations) != 1 || created.Integrations[0].Wandb.Project != "my-project" {
+			t.Errorf("Unexpected integrations: %+v", created.Integrations)
+		}
+		if created.Metadata["key"] != "value" {
+			t.Errorf("Expected metadata with key='value', got %v", created.Metadata)
+		}
+	}
+
+	// === PauseFineTuningJob ===
+	if paused, err := client.PauseFineTuningJob("ftjob-abc123"); err != nil {
+		t.Errorf("PauseFineTuningJob failed: %v", err)
+	} else if paused.JobError == nil || paused.JobError.Code != "invalid_training_file" {
+		t.Errorf("Expected job error with code 'invalid_training_file', got %+v", paused.JobError)
+	}
+
+	// === ResumeFineTuningJob (error) ===
+	if _, err := client.ResumeFineTuningJob("ftjob-unknown"); err == nil || !strings.Contains(err.Error(), "No such fine-tuning job") {
+		t.Errorf("Expected an error for unknown job, got %v", err)
+	}
+
+	// === ListFineTuningJobCheckpoints ===
+	if checkpoints, err := client.ListFineTuningJobCheckpoints("ftjob-abc123", FineTuningJobCheckpointsOptions{}.SetLimit(2)); err != nil {
+		t.Errorf("ListFineTuningJobCheckpoints failed: %v", err)
+	} else if len(checkpoints.Data) != 1 {
+		t.Errorf("Expected 1 checkpoint, got %d", len(checkpoints.Data))
+	} else {
+		metrics := checkpoints.Data[0].Metrics
+		if metrics.TrainLoss == nil || *metrics.TrainLoss != 0.5 || metrics.ValidLoss != nil {
+			t.Errorf("Unexpected checkpoint metrics: %+v", metrics)
+		}
+	}
+}
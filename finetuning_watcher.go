@@ -0,0 +1,242 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// helpers for watching fine-tuning jobs until they finish
+
+const (
+	defaultFineTuningWatchInitialInterval = 5 * time.Second
+	defaultFineTuningWatchMaxInterval     = 60 * time.Second
+
+	fineTuningWatchEventsLimit = 100
+)
+
+// IsTerminal returns if the status is a final one.
+func (s FineTuningJobStatus) IsTerminal() bool {
+	switch s {
+	case FineTuningJobStatusSucceeded, FineTuningJobStatusFailed, FineTuningJobStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// FineTuningJobEventHandler type for handling events of a watched fine-tuning job
+//
+// `job` is the most recently retrieved state of the job.
+type FineTuningJobEventHandler func(job FineTuningJob, event FineTuningJobEvent)
+
+// FineTuningJobWatchOptions struct for watching fine-tuning jobs
+type FineTuningJobWatchOptions struct {
+	// polling interval, reset to this value whenever a new event arrives (default: 5 seconds)
+	InitialInterval time.Duration
+
+	// polling interval grows up to this value while nothing happens (default: 60 seconds)
+	MaxInterval time.Duration
+
+	// if true, result files will not be downloaded and parsed
+	SkipMetrics bool
+}
+
+// FineTuningJobWatchResult struct for the result of a watched fine-tuning job
+type FineTuningJobWatchResult struct {
+	Job     FineTuningJob
+	Metrics []FineTuningStepMetrics
+}
+
+// FineTuningStepMetrics struct for each step in the result file of a fine-tuning job
+type FineTuningStepMetrics struct {
+	Step int
+
+	TrainLoss          *float64
+	TrainTokenAccuracy *float64
+	ValidLoss          *float64
+	ValidTokenAccuracy *float64
+
+	FullValidLoss          *float64
+	FullValidTokenAccuracy *float64
+}
+
+// WatchFineTuningJob polls a fine-tuning job with given `fineTuningJobID` until it reaches a terminal status,
+// calling `handler` exactly once for each new event in chronological order.
+//
+// When the job succeeds, its first result file is downloaded and parsed into per-step metrics.
+func (c *Client) WatchFineTuningJob(ctx context.Context, fineTuningJobID string, handler FineTuningJobEventHandler) (result FineTuningJobWatchResult, err error) {
+	return c.WatchFineTuningJobWithOptions(ctx, fineTuningJobID, FineTuningJobWatchOptions{}, handler)
+}
+
+// WatchFineTuningJobWithOptions does the same as `WatchFineTuningJob` with given `options`.
+func (c *Client) WatchFineTuningJobWithOptions(ctx context.Context, fineTuningJobID string, options FineTuningJobWatchOptions, handler FineTuningJobEventHandler) (result FineTuningJobWatchResult, err error) {
+	initial := options.InitialInterval
+	if initial <= 0 {
+		initial = defaultFineTuningWatchInitialInterval
+	}
+	maxInterval := options.MaxInterval
+	if maxInterval < initial {
+		maxInterval = defaultFineTuningWatchMaxInterval
+		if maxInterval < initial {
+			maxInterval = initial
+		}
+	}
+
+	seen := map[string]bool{}
+	interval := initial
+	for {
+		var job FineTuningJob
+		if job, err = c.RetrieveFineTuningJob(fineTuningJobID); err != nil {
+			return FineTuningJobWatchResult{}, fmt.Errorf("failed to retrieve fine-tuning job: %s", err)
+		}
+
+		var events []FineTuningJobEvent
+		if events, err = c.newFineTuningJobEvents(fineTuningJobID, seen); err != nil {
+			return FineTuningJobWatchResult{}, fmt.Errorf("failed to list fine-tuning job events: %s", err)
+		}
+		for _, event := range events {
+			if handler != nil {
+				handler(job, event)
+			}
+		}
+
+		if job.Status.IsTerminal() {
+			result.Job = job
+
+			if !options.SkipMetrics && job.Status == FineTuningJobStatusSucceeded && len(job.ResultFiles) > 0 {
+				var content []byte
+				if content, err = c.RetrieveFileContent(job.ResultFiles[0]); err != nil {
+					return result, fmt.Errorf("failed to retrieve result file: %s", err)
+				}
+				if result.Metrics, err = ParseFineTuningResultMetrics(content); err != nil {
+					return result, err
+				}
+			}
+
+			return result, nil
+		}
+
+		// back off while nothing happens
+		if len(events) > 0 {
+			interval = initial
+		} else {
+			interval *= 2
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return FineTuningJobWatchResult{Job: job}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetches events which are not in `seen` yet, and returns them in chronological order
+func (c *Client) newFineTuningJobEvents(fineTuningJobID string, seen map[string]bool) (events []FineTuningJobEvent, err error) {
+	options := FineTuningJobEventsOptions{}.SetLimit(fineTuningWatchEventsLimit)
+
+	// events are listed from the newest one
+	newest := []FineTuningJobEvent{}
+	for {
+		var listed FineTuningJobEvents
+		if listed, err = c.ListFineTuningJobEvents(fineTuningJobID, options); err != nil {
+			return nil, err
+		}
+
+		reachedSeen := false
+		for _, event := range listed.Data {
+			if seen[event.ID] {
+				reachedSeen = true
+				break
+			}
+			newest = append(newest, event)
+		}
+
+		if reachedSeen || !listed.HasMore || len(listed.Data) <= 0 {
+			break
+		}
+		options.SetAfter(listed.Data[len(listed.Data)-1].ID)
+	}
+
+	for i := len(newest) - 1; i >= 0; i-- {
+		seen[newest[i].ID] = true
+		events = append(events, newest[i])
+	}
+
+	return events, nil
+}
+
+// ParseFineTuningResultMetrics parses the content of a fine-tuning job's result file (CSV) into per-step metrics.
+//
+// Content which is base64-encoded (as returned from the files API) is decoded first.
+func ParseFineTuningResultMetrics(content []byte) (metrics []FineTuningStepMetrics, err error) {
+	content = bytes.TrimSpace(content)
+	if !bytes.HasPrefix(content, []byte("step")) {
+		if decoded, e := base64.StdEncoding.DecodeString(string(content)); e == nil {
+			content = bytes.TrimSpace(decoded)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	if header, err = reader.Read(); err != nil {
+		return nil, fmt.Errorf("failed to read header of result file: %s", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, exists := columns["step"]; !exists {
+		return nil, fmt.Errorf("no `step` column in result file header: %v", header)
+	}
+
+	metrics = []FineTuningStepMetrics{}
+	for {
+		var record []string
+		if record, err = reader.Read(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to read record of result file: %s", err)
+		}
+
+		value := func(names ...string) *float64 {
+			for _, name := range names {
+				if i, exists := columns[name]; exists && i < len(record) {
+					if f, err := strconv.ParseFloat(strings.TrimSpace(record[i]), 64); err == nil {
+						return &f
+					}
+				}
+			}
+			return nil
+		}
+
+		step := value("step")
+		if step == nil {
+			return nil, fmt.Errorf("invalid `step` value in result file record: %v", record)
+		}
+
+		metrics = append(metrics, FineTuningStepMetrics{
+			Step:                   int(*step),
+			TrainLoss:              value("train_loss"),
+			TrainTokenAccuracy:     value("train_mean_token_accuracy", "train_accuracy"),
+			ValidLoss:              value("valid_loss"),
+			ValidTokenAccuracy:     value("valid_mean_token_accuracy", "valid_accuracy"),
+			FullValidLoss:          value("full_valid_loss"),
+			FullValidTokenAccuracy: value("full_valid_mean_token_accuracy"),
+		})
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,124 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchFineTuningJobMock(t *testing.T) {
+	resultCSV := `step,train_loss,train_accuracy,valid_loss,valid_mean_token_accuracy
+1,1.5,0.4,,
+2,1.2,0.5,1.3,0.45
+`
+
+	var mutex sync.Mutex
+	polls := 0
+	allEvents := []FineTuningJobEvent{} // newest first
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/fine_tuning/jobs/ftjob-abc123":
+			polls++
+
+			// add an event on every other poll
+			if polls%2 == 1 {
+				allEvents = append([]FineTuningJobEvent{{
+					ID:      fmt.Sprintf("ftevent-%d", polls),
+					Level:   "info",
+					Message: fmt.Sprintf("poll %d", polls),
+				}}, allEvents...)
+			}
+
+			status := FineTuningJobStatusRunning
+			if polls >= 5 {
+				status = FineTuningJobStatusSucceeded
+			}
+			json.NewEncoder(w).Encode(FineTuningJob{
+				ID:          "ftjob-abc123",
+				Status:      status,
+				ResultFiles: []string{"file-result"},
+			})
+		case "/fine_tuning/jobs/ftjob-abc123/events":
+			json.NewEncoder(w).Encode(FineTuningJobEvents{
+				Data: allEvents,
+			})
+		case "/files/file-result/content":
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(resultCSV))))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	received := []string{}
+	result, err := client.WatchFineTuningJobWithOptions(context.Background(), "ftjob-abc123", FineTuningJobWatchOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+	}, func(job FineTuningJob, event FineTuningJobEvent) {
+		received = append(received, event.ID)
+	})
+	if err != nil {
+		t.Fatalf("WatchFineTuningJob failed: %v", err)
+	}
+
+	expected := []string{"ftevent-1", "ftevent-3", "ftevent-5"}
+	if fmt.Sprintf("%v", received) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Expected events %v, got %v", expected, received)
+	}
+
+	if result.Job.Status != FineTuningJobStatusSucceeded {
+		t.Errorf("Expected status succeeded, got %s", result.Job.Status)
+	}
+
+	if len(result.Metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(result.Metrics))
+	}
+	if result.Metrics[0].ValidLoss != nil {
+		t.Errorf("Expected empty valid_loss at step 1, got %v", *result.Metrics[0].ValidLoss)
+	}
+	if m := result.Metrics[1]; m.Step != 2 || *m.TrainLoss != 1.2 || *m.TrainTokenAccuracy != 0.5 || *m.ValidTokenAccuracy != 0.45 {
+		t.Errorf("Unexpected metrics at step 2: %+v", m)
+	}
+}
+
+func TestWatchFineTuningJobCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/fine_tuning/jobs/ftjob-abc123":
+			json.NewEncoder(w).Encode(FineTuningJob{ID: "ftjob-abc123", Status: FineTuningJobStatusRunning})
+		default:
+			json.NewEncoder(w).Encode(FineTuningJobEvents{Data: []FineTuningJobEvent{}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.WatchFineTuningJobWithOptions(ctx, "ftjob-abc123", FineTuningJobWatchOptions{
+		InitialInterval: time.Millisecond,
+	}, nil); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
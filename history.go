@@ -0,0 +1,270 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// helpers for keeping chat histories under a token budget
+
+const (
+	// prefix of system messages generated as summaries of trimmed histories
+	HistorySummaryPrefix = "Summary of the earlier conversation:\n"
+
+	defaultHistorySummaryMaxTokens = 512
+	defaultHistorySummaryPrompt    = `Summarize the following conversation concisely. Keep names, facts, decisions, and open questions which may be needed to continue the conversation.`
+)
+
+// HistorySummarizer type for summarizing trimmed chat messages in `maxTokens`
+type HistorySummarizer func(ctx context.Context, messages []ChatMessage, maxTokens int) (summary string, err error)
+
+// HistoryManager keeps chat histories under a token budget of a model,
+// by dropping the oldest turns or replacing them with a summary.
+//
+// System messages are always kept, and tool messages are trimmed together with
+// the assistant message which requested them.
+type HistoryManager struct {
+	model     string
+	maxTokens int
+	tools     []ChatCompletionTool
+	tokenizer *Tokenizer
+
+	summarizer       HistorySummarizer
+	summaryMaxTokens int
+}
+
+// NewHistoryManager returns a new HistoryManager which keeps histories under `maxTokens` for `model`,
+// with the tokenizer registered with `RegisterTokenizer`.
+func NewHistoryManager(model string, maxTokens int) (manager *HistoryManager, err error) {
+	var tokenizer *Tokenizer
+	if tokenizer, err = TokenizerForModel(model); err != nil {
+		return nil, err
+	}
+
+	return &HistoryManager{
+		model:            model,
+		maxTokens:        maxTokens,
+		tokenizer:        tokenizer,
+		summaryMaxTokens: defaultHistorySummaryMaxTokens,
+	}, nil
+}
+
+// SetTokenizer sets the tokenizer for counting tokens.
+func (m *HistoryManager) SetTokenizer(tokenizer *Tokenizer) *HistoryManager {
+	m.tokenizer = tokenizer
+	return m
+}
+
+// SetTools sets the tools which will be sent along with the history, for counting their tokens.
+func (m *HistoryManager) SetTools(tools []ChatCompletionTool) *HistoryManager {
+	m.tools = tools
+	return m
+}
+
+// SetSummarizer makes trimmed messages be replaced with a summary generated by `summarizer`,
+// instead of being dropped. (nil for dropping them again)
+//
+// `maxTokens` is reserved in the budget for the summary, and passed to `summarizer` as its limit.
+func (m *HistoryManager) SetSummarizer(summarizer HistorySummarizer, maxTokens int) *HistoryManager {
+	m.summarizer = summarizer
+	if maxTokens > 0 {
+		m.summaryMaxTokens = maxTokens
+	}
+	return m
+}
+
+// NewChatCompletionSummarizer returns a HistorySummarizer which summarizes messages with `CreateChatCompletionWithContext`.
+//
+// If `prompt` is empty, a default one is used.
+// The completion is limited with `max_completion_tokens` to the token budget of the summary.
+func NewChatCompletionSummarizer(client *Client, model, prompt string, options ChatCompletionOptions) HistorySummarizer {
+	if prompt == "" {
+		prompt = defaultHistorySummaryPrompt
+	}
+
+	return func(ctx context.Context, messages []ChatMessage, maxTokens int) (summary string, err error) {
+		opts := ChatCompletionOptions{}
+		for k, v := range options {
+			opts[k] = v
+		}
+		delete(opts, "max_tokens")
+		opts["max_completion_tokens"] = maxTokens
+
+		var completion ChatCompletion
+		if completion, err = client.CreateChatCompletionWithContext(ctx, model, []ChatMessage{
+			NewChatSystemMessage(prompt),
+			NewChatUserMessage(historyTranscript(messages)),
+		}, opts); err != nil {
+			return "", err
+		}
+		if len(completion.Choices) <= 0 {
+			return "", fmt.Errorf("no choice in summary completion")
+		}
+
+		return completion.Choices[0].Message.ContentString()
+	}
+}
+
+// CountTokens counts the tokens of given `messages` (with tools set by `SetTools`).
+func (m *HistoryManager) CountTokens(messages []ChatMessage) (int, error) {
+	return m.tokenizer.CountChatTokens(m.model, messages, m.tools)
+}
+
+// Fit returns `messages` trimmed (or summarized) to fit in the token budget.
+//
+// The most recent turn is always kept; if it alone exceeds the budget, an error is returned with the trimmed messages.
+func (m *HistoryManager) Fit(ctx context.Context, messages []ChatMessage) (fitted []ChatMessage, err error) {
+	if m.tokenizer == nil {
+		return nil, fmt.Errorf("no tokenizer for counting tokens")
+	}
+
+	// count tokens of each message
+	counts := make([]int, len(messages))
+	total := tokensPerReplyPrimer
+	if len(m.tools) > 0 {
+		total += m.tokenizer.CountToolsTokens(m.tools)
+	}
+	for i, message := range messages {
+		if counts[i], err = m.tokenizer.countMessageTokens(m.model, message); err != nil {
+			return nil, fmt.Errorf("failed to count tokens of message at index %d: %s", i, err)
+		}
+		total += counts[i]
+	}
+	if total <= m.maxTokens {
+		return append([]ChatMessage{}, messages...), nil
+	}
+
+	budget := m.maxTokens
+	if m.summarizer != nil {
+		budget -= tokensPerMessage + m.tokenizer.Count(string(ChatMessageRoleSystem)) + m.tokenizer.Count(HistorySummaryPrefix) + m.summaryMaxTokens
+	}
+
+	// trim the oldest turns until it fits
+	turns := historyTurns(messages)
+	trimmed := map[int]bool{}
+	for t := 0; t < len(turns)-1 && total > budget; t++ {
+		for _, i := range turns[t] {
+			trimmed[i] = true
+			total -= counts[i]
+		}
+	}
+
+	var summary *ChatMessage
+	if m.summarizer != nil && len(trimmed) > 0 {
+		toBeSummarized := []ChatMessage{}
+		for i, message := range messages {
+			if trimmed[i] {
+				toBeSummarized = append(toBeSummarized, message)
+			}
+		}
+
+		var summarized string
+		if summarized, err = m.summarizer(ctx, toBeSummarized, m.summaryMaxTokens); err != nil {
+			return nil, fmt.Errorf("failed to summarize trimmed messages: %s", err)
+		}
+		message := NewChatSystemMessage(HistorySummaryPrefix + summarized)
+		summary = &message
+	}
+
+	fitted = []ChatMessage{}
+	for i, message := range messages {
+		if trimmed[i] {
+			if summary != nil {
+				fitted = append(fitted, *summary)
+				summary = nil
+			}
+			continue
+		}
+		fitted = append(fitted, message)
+	}
+
+	// recount with the summary, which may not fit in its reserved budget
+	if total, err = m.CountTokens(fitted); err != nil {
+		return nil, err
+	}
+	if total > m.maxTokens {
+		return fitted, fmt.Errorf("messages exceed the token budget (%d) even after trimming", m.maxTokens)
+	}
+
+	return fitted, nil
+}
+
+// groups indices of non-pinned messages into turns, each starting with a user message
+//
+// Tool messages always follow the assistant message which requested them, so they stay in the same turn.
+func historyTurns(messages []ChatMessage) (turns [][]int) {
+	current := []int{}
+	for i, message := range messages {
+		if isPinnedHistoryMessage(message) {
+			continue
+		}
+
+		if message.Role == ChatMessageRoleUser && len(current) > 0 {
+			turns = append(turns, current)
+			current = []int{}
+		}
+		current = append(current, i)
+	}
+	if len(current) > 0 {
+		turns = append(turns, current)
+	}
+	return turns
+}
+
+// checks if given message should never be trimmed
+func isPinnedHistoryMessage(message ChatMessage) bool {
+	if message.Role != ChatMessageRoleSystem {
+		return false
+	}
+
+	// previous summaries can be summarized again
+	text, _ := chatMessageText(message)
+	return !strings.HasPrefix(text, HistorySummaryPrefix)
+}
+
+// returns the text of a chat message, with string or content array
+func chatMessageText(message ChatMessage) (text string, err error) {
+	switch content := message.Content.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return content, nil
+	case *string:
+		if content != nil {
+			return *content, nil
+		}
+		return "", nil
+	case []ChatMessageContent:
+		texts := []string{}
+		for _, part := range content {
+			if part.Text != nil {
+				texts = append(texts, *part.Text)
+			} else if part.Type == "image_url" {
+				texts = append(texts, "[image]")
+			} else if part.Filename != nil {
+				texts = append(texts, fmt.Sprintf("[file: %s]", *part.Filename))
+			}
+		}
+		return strings.Join(texts, "\n"), nil
+	}
+
+	return "", fmt.Errorf("unsupported type of content: %T", message.Content)
+}
+
+// generates a plain text transcript of given messages
+func historyTranscript(messages []ChatMessage) string {
+	lines := []string{}
+	for _, message := range messages {
+		text, _ := chatMessageText(message)
+		text = strings.TrimPrefix(text, HistorySummaryPrefix)
+
+		if text != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", message.Role, text))
+		}
+		for _, toolCall := range message.ToolCalls {
+			lines = append(lines, fmt.Sprintf("%s: (called `%s` with %s)", message.Role, toolCall.Function.Name, toolCall.Function.Arguments))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
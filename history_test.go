@@ -0,0 +1,159 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func historyFixture() []ChatMessage {
+	return []ChatMessage{
+		NewChatSystemMessage("You are a helpful assistant."),
+		NewChatUserMessage("What is the weather in Seoul today?"),
+		{
+			Role: ChatMessageRoleAssistant,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Seoul"}`}},
+			},
+		},
+		NewChatToolMessage("call_1", "sunny, 25 degrees celsius"),
+		NewChatAssistantMessage("It is sunny and 25 degrees celsius in Seoul."),
+		NewChatUserMessage([]ChatMessageContent{
+			NewChatMessageContentWithText("And what about tomorrow?"),
+		}),
+		NewChatAssistantMessage("It will be rainy tomorrow."),
+		NewChatUserMessage("Thanks!"),
+	}
+}
+
+func TestHistoryManagerDrop(t *testing.T) {
+	RegisterTokenizer(fixtureTokenizer(t, TokenizerEncodingO200kBase))
+
+	messages := historyFixture()
+
+	unlimited, err := NewHistoryManager("gpt-4o", 1000000)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+	if fitted, err := unlimited.Fit(context.Background(), messages); err != nil || len(fitted) != len(messages) {
+		t.Errorf("Expected untouched messages, got %d messages (%v)", len(fitted), err)
+	}
+
+	// budget for system + the last two turns
+	last := messages[5:]
+	budget, _ := unlimited.CountTokens(append([]ChatMessage{messages[0]}, last...))
+
+	manager, _ := NewHistoryManager("gpt-4o", budget)
+	fitted, err := manager.Fit(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+	if len(fitted) != 4 {
+		t.Fatalf("Expected 4 messages, got %d", len(fitted))
+	}
+	if fitted[0].Role != ChatMessageRoleSystem {
+		t.Errorf("Expected system message to be pinned, got role: %s", fitted[0].Role)
+	}
+	for _, message := range fitted {
+		if message.Role == ChatMessageRoleTool || len(message.ToolCalls) > 0 {
+			t.Errorf("Expected tool call and its result to be dropped together")
+		}
+	}
+	if count, _ := manager.CountTokens(fitted); count > budget {
+		t.Errorf("Expected %d tokens at most, got %d", budget, count)
+	}
+
+	// too small budget: only the last turn is kept, with an error
+	tiny, _ := NewHistoryManager("gpt-4o", 10)
+	fitted, err = tiny.Fit(context.Background(), messages)
+	if err == nil {
+		t.Errorf("Expected error for too small budget")
+	}
+	if len(fitted) != 2 {
+		t.Errorf("Expected system message and the last turn, got %d messages", len(fitted))
+	}
+}
+
+func TestHistoryManagerSummarize(t *testing.T) {
+	RegisterTokenizer(fixtureTokenizer(t, TokenizerEncodingO200kBase))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Model               string `json:"model"`
+			MaxCompletionTokens int    `json:"max_completion_tokens"`
+			Messages            []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if requestBody.MaxCompletionTokens != 40 {
+			t.Errorf("Expected max_completion_tokens: 40, got %d", requestBody.MaxCompletionTokens)
+		}
+		if len(requestBody.Messages) != 2 {
+			t.Errorf("Expected 2 messages for summarization, got %d", len(requestBody.Messages))
+		} else {
+			transcript := requestBody.Messages[1].Content
+			if !strings.Contains(transcript, "user: What is the weather in Seoul today?") ||
+				!strings.Contains(transcript, "called `get_weather`") {
+				t.Errorf("Unexpected transcript: %s", transcript)
+			}
+			if strings.Contains(transcript, "Thanks!") {
+				t.Errorf("Expected the last turn not to be summarized: %s", transcript)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"User asked about weather in Seoul."},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	messages := historyFixture()
+
+	unlimited, err := NewHistoryManager("gpt-4o", 1000000)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+
+	// budget for system + summary + the last turn
+	budget, _ := unlimited.CountTokens([]ChatMessage{messages[0], NewChatSystemMessage(HistorySummaryPrefix), messages[7]})
+	budget += 40
+
+	manager, _ := NewHistoryManager("gpt-4o", budget)
+	manager.SetSummarizer(NewChatCompletionSummarizer(client, "gpt-4o-mini", "", ChatCompletionOptions{}.SetMaxTokens(1000)), 40)
+
+	fitted, err := manager.Fit(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+	if len(fitted) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(fitted))
+	}
+	if fitted[0].Role != ChatMessageRoleSystem {
+		t.Errorf("Expected system message to be pinned, got role: %s", fitted[0].Role)
+	}
+	if summary, _ := fitted[1].ContentString(); summary != HistorySummaryPrefix+"User asked about weather in Seoul." {
+		t.Errorf("Expected summary as the second message, got '%s'", summary)
+	}
+	if last, _ := fitted[len(fitted)-1].ContentString(); last != "Thanks!" {
+		t.Errorf("Expected the last message to be kept, got '%s'", last)
+	}
+
+	// a summary longer than its budget
+	manager.SetSummarizer(func(ctx context.Context, messages []ChatMessage, maxTokens int) (string, error) {
+		return strings.Repeat("This summary is far too long. ", 50), nil
+	}, 40)
+	if fitted, err = manager.Fit(context.Background(), messages); err == nil {
+		t.Errorf("Expected error for a summary exceeding the budget")
+	} else if count, _ := manager.CountTokens(fitted); count <= budget {
+		t.Errorf("Expected over-budget messages with the error, got %d tokens", count)
+	}
+}
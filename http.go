@@ -15,6 +15,8 @@ import (
 	"net/http/httputil"
 	"net/textproto"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 )
 
@@ -27,6 +29,8 @@ const (
 	kAuthorization      = "Authorization"
 	kOrganization       = "OpenAI-Organization"
 	kBeta               = "OpenAI-Beta"
+
+	assistantsBetaHeader = "assistants=v2"
 )
 
 var (
@@ -34,6 +38,25 @@ var (
 	StreamDone = []byte("[DONE]")
 )
 
+// betaHeader returns the beta HTTP header value for given `endpoint`
+//
+// The realtime endpoint always gets `realtime=v1`. Other endpoints get the header set with `SetBetaHeader`,
+// or `assistants=v2` for the assistants API if it is not set.
+func (c *Client) betaHeader(endpoint string) string {
+	if endpoint == realtimeEndpoint {
+		return realtimeBetaHeader
+	}
+	if c.beta != nil {
+		return *c.beta
+	}
+	for _, prefix := range []string{"assistants", "threads", "vector_stores"} {
+		if endpoint == prefix || strings.HasPrefix(endpoint, prefix+"/") {
+			return assistantsBetaHeader
+		}
+	}
+	return ""
+}
+
 // isSuccessStatus checks if HTTP status code indicates success
 func isSuccessStatus(code int) bool {
 	return code >= 200 && code < 300
@@ -89,6 +112,10 @@ func (e *Error) err() error {
 	}
 }
 
+// reads chat completion chunks from `res`, and streams them to `cb`
+//
+// `cb` is always called once with `done` == true at the end; when the stream is closed without `[DONE]`,
+// it is called with an empty ChatCompletion and no error.
 func streamWithCtx(ctx context.Context, res *http.Response, cb callback) {
 	defer res.Body.Close()
 
@@ -171,6 +198,9 @@ func streamWithCtx(ctx context.Context, res *http.Response, cb callback) {
 	// Check for scanner error
 	if err := scanner.Err(); err != nil {
 		cb(ChatCompletion{}, true, err)
+	} else {
+		// stream was closed without `[DONE]`
+		cb(ChatCompletion{}, true, nil)
 	}
 }
 
@@ -205,6 +235,9 @@ func (c *Client) postCBResponsesWithContext(ctx context.Context, endpoint string
 	// set authentication headers
 	req.Header.Set(kAuthorization, fmt.Sprintf("Bearer %s", c.APIKey))
 	req.Header.Set(kOrganization, c.OrganizationID)
+	if beta := c.betaHeader(endpoint); beta != "" {
+		req.Header.Set(kBeta, beta)
+	}
 
 	if c.Verbose {
 		if dumped, err := httputil.DumpRequest(req, true); err == nil {
@@ -276,7 +309,7 @@ func streamResponsesWithCtx(ctx context.Context, res *http.Response, cb response
 			}
 
 			// Check if this is a completion event
-			done := event.Type == "response.completed" || event.Type == "response.failed" || event.Type == "response.cancelled"
+			done := event.Type == "response.completed" || event.Type == "response.failed" || event.Type == "response.cancelled" || event.Type == "response.incomplete"
 			cb(event, done, nil)
 
 			if done {
@@ -288,27 +321,229 @@ func streamResponsesWithCtx(ctx context.Context, res *http.Response, cb response
 	// Check for scanner error
 	if err := scanner.Err(); err != nil {
 		cb(ResponseStreamEvent{}, true, err)
+	} else {
+		// stream was closed without a completion event
+		cb(ResponseStreamEvent{}, true, nil)
+	}
+}
+
+// opens a streaming HTTP POST request with context, and returns its response for reading events
+//
+// `params` are sent as multipart/form-data if they have a file, or as application/json otherwise.
+func (c *Client) openStreamWithContext(ctx context.Context, endpoint string, params map[string]any) (resp *http.Response, err error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	url := baseURL
+	if c.baseURL != nil {
+		url = *c.baseURL
+	}
+	apiURL := fmt.Sprintf("%s/%s", url, endpoint)
+
+	var body *bytes.Buffer
+	contentType := defaultContentType
+	if hasFileInParams(params) {
+		if body, contentType, err = multipartBody(params); err != nil {
+			return nil, err
+		}
+	} else {
+		var serialized []byte
+		if serialized, err = json.Marshal(params); err != nil {
+			return nil, fmt.Errorf("failed to serialize params: %s", err)
+		}
+		body = bytes.NewBuffer(serialized)
+	}
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, apiURL, body); err != nil {
+		return nil, fmt.Errorf("failed to create streaming request: %s", err)
+	}
+
+	// set headers
+	req.Header.Set(kContentType, contentType)
+	req.Header.Set(kAuthorization, fmt.Sprintf("Bearer %s", c.APIKey))
+	req.Header.Set(kOrganization, c.OrganizationID)
+	if beta := c.betaHeader(endpoint); beta != "" {
+		req.Header.Set(kBeta, beta)
+	}
+
+	if c.Verbose {
+		if dumped, err := httputil.DumpRequest(req, true); err == nil {
+			log.Printf("dump request:\n\n%s", string(dumped))
+		}
 	}
+
+	if resp, err = c.httpClient.Do(req); err != nil {
+		return nil, err
+	}
+	if !isSuccessStatus(resp.StatusCode) {
+		defer resp.Body.Close()
+		errbody := struct {
+			Error Error `json:"error"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&errbody); err != nil {
+			return nil, fmt.Errorf("failed to decode error body: %v", err)
+		}
+		return nil, errbody.Error.err()
+	}
+
+	return resp, nil
+}
+
+// maximum size of a line in server-sent events
+const maxServerSentEventLineSize = 4 * 1024 * 1024
+
+// readServerSentEvents reads server-sent events from `r`,
+// calling `fn` with the name and data of each event until it returns false.
+//
+// Returns nil at the end of `r`.
+func readServerSentEvents(ctx context.Context, r io.Reader, fn func(event string, data []byte) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxServerSentEventLineSize)
+
+	var event string
+	var data [][]byte
+	dispatch := func() bool {
+		defer func() {
+			event, data = "", nil
+		}()
+		if len(data) <= 0 {
+			return true
+		}
+		return fn(event, bytes.Join(data, []byte("\n")))
+	}
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		switch {
+		case len(line) == 0:
+			if !dispatch() {
+				return nil
+			}
+		case bytes.HasPrefix(line, []byte(":")):
+			// comment
+		case bytes.HasPrefix(line, []byte("event:")):
+			event = string(bytes.TrimSpace(bytes.TrimPrefix(line, []byte("event:"))))
+		case bytes.HasPrefix(line, []byte("data:")):
+			value := bytes.TrimPrefix(line, []byte("data:"))
+			value = bytes.TrimPrefix(value, []byte(" "))
+			data = append(data, append([]byte{}, value...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// last event without a trailing blank line
+	dispatch()
+
+	return nil
+}
+
+// event of server-sent event streams which are decoded into typed events (eg. SpeechStreamEvent)
+type typedServerSentEvent[T any] interface {
+	// returns the event with `name` as its type, if it has no type
+	withName(name string) T
+
+	// returns the error of the event, if any
+	eventError() *Error
+
+	// returns whether it is the last event of the stream
+	isLast() bool
+}
+
+// reads server-sent events from `r` and decodes them into `T`,
+// calling `fn` with each of them until it returns an error, or the last event is read.
+//
+// Returns nil at the end of `r`, or after the last event.
+func readTypedServerSentEvents[T typedServerSentEvent[T]](ctx context.Context, r io.Reader, fn func(event T) error) (err error) {
+	e := readServerSentEvents(ctx, r, func(name string, data []byte) bool {
+		if bytes.Equal(data, StreamDone) {
+			return false
+		}
+
+		var event T
+		if err = json.Unmarshal(data, &event); err != nil {
+			err = fmt.Errorf("failed to parse stream event: %s", err)
+			return false
+		}
+		event = event.withName(name)
+		if e := event.eventError(); e != nil {
+			err = e.err()
+			return false
+		}
+
+		if err = fn(event); err != nil {
+			return false
+		}
+		return !event.isLast()
+	})
+	if err != nil {
+		return err
+	}
+	return e
+}
+
+// reads server-sent events of `resp` in background, and streams them to `cb` as `T`
+//
+// `cb` is called with `done` == true on the last event, or with an error (or nothing)
+// when the stream is closed before the last event.
+func streamTypedEvents[T typedServerSentEvent[T]](ctx context.Context, resp *http.Response, cb func(event T, done bool, err error)) {
+	go func() {
+		defer resp.Body.Close()
+
+		finished := false
+		err := readTypedServerSentEvents(ctx, resp.Body, func(event T) error {
+			finished = event.isLast()
+			cb(event, finished, nil)
+			return nil
+		})
+		if !finished {
+			// stream was closed with an error, or without the last event
+			var empty T
+			cb(empty, true, err)
+		}
+	}()
 }
 
 // FileParam struct for multipart requests
 type FileParam struct {
-	bs []byte
+	bs       []byte
+	filename string // optional
 }
 
 // NewFileParamFromBytes returns a new FileParam with given bytes
+//
+// Its filename in multipart requests will have an extension detected from the bytes.
 func NewFileParamFromBytes(bs []byte) FileParam {
 	return FileParam{
 		bs: bs,
 	}
 }
 
-// NewFileParamFromFilepath returns a new FileParam with bytes read from given filepath
+// NewFileParamFromBytesWithFilename returns a new FileParam with given bytes and filename
+//
+// The extension of `filename` (eg. '.md', '.docx') is used by the API for detecting the file type.
+func NewFileParamFromBytesWithFilename(bs []byte, filename string) FileParam {
+	return FileParam{
+		bs:       bs,
+		filename: filename,
+	}
+}
+
+// NewFileParamFromFilepath returns a new FileParam with bytes and filename read from given filepath
 func NewFileParamFromFilepath(path string) (f FileParam, err error) {
 	var bs []byte
 	if bs, err = os.ReadFile(path); err == nil {
 		return FileParam{
-			bs: bs,
+			bs:       bs,
+			filename: filepath.Base(path),
 		}, nil
 	}
 	return FileParam{}, err
@@ -337,8 +572,8 @@ func (c *Client) doWithContext(ctx context.Context, method, endpoint string, par
 		// headers
 		req.Header.Set(kAuthorization, fmt.Sprintf("Bearer %s", c.APIKey))
 		req.Header.Set(kOrganization, c.OrganizationID)
-		if c.beta != nil {
-			req.Header.Set(kBeta, *c.beta)
+		if beta := c.betaHeader(endpoint); beta != "" {
+			req.Header.Set(kBeta, beta)
 		}
 
 		if c.Verbose {
@@ -409,32 +644,10 @@ func (c *Client) postWithContext(ctx context.Context, endpoint string, params ma
 
 	if hasFileInParams(params) {
 		// multipart/form-data
-		body := &bytes.Buffer{}
-		writer := multipart.NewWriter(body)
-
-		for k, v := range params {
-			switch val := v.(type) {
-			case FileParam:
-				bs := val.bs
-				filename := fmt.Sprintf("%s.%s", k, getExtension(bs))
-
-				var part io.Writer
-				if part, err = writer.CreatePart(mimeHeaderForBytes(bs, k, filename)); err == nil {
-					if _, err = io.Copy(part, bytes.NewReader(bs)); err != nil {
-						return nil, fmt.Errorf("could not write bytes to multipart for param '%s': %s", k, err)
-					}
-				} else {
-					return nil, fmt.Errorf("could not create part for param '%s': %s", k, err)
-				}
-			default:
-				if err := writer.WriteField(k, fmt.Sprintf("%v", v)); err != nil {
-					return nil, fmt.Errorf("could not write field with key: %s, value: %v", k, v)
-				}
-			}
-		}
-
-		if err = writer.Close(); err != nil {
-			return nil, fmt.Errorf("error while closing multipart form data writer: %s", err)
+		var body *bytes.Buffer
+		var contentType string
+		if body, contentType, err = multipartBody(params); err != nil {
+			return nil, err
 		}
 
 		if req, err = http.NewRequestWithContext(ctx, http.MethodPost, apiURL, body); err != nil {
@@ -442,7 +655,7 @@ func (c *Client) postWithContext(ctx context.Context, endpoint string, params ma
 		}
 
 		// set content-type header
-		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Content-Type", contentType)
 	} else {
 		// application/json
 		var serialized []byte
@@ -459,8 +672,8 @@ func (c *Client) postWithContext(ctx context.Context, endpoint string, params ma
 	// set authentication headers
 	req.Header.Set(kAuthorization, fmt.Sprintf("Bearer %s", c.APIKey))
 	req.Header.Set(kOrganization, c.OrganizationID)
-	if c.beta != nil {
-		req.Header.Set(kBeta, *c.beta)
+	if beta := c.betaHeader(endpoint); beta != "" {
+		req.Header.Set(kBeta, beta)
 	}
 
 	if c.Verbose {
@@ -529,6 +742,9 @@ func (c *Client) postCBWithContext(ctx context.Context, endpoint string, params
 	// set authentication headers
 	req.Header.Set(kAuthorization, fmt.Sprintf("Bearer %s", c.APIKey))
 	req.Header.Set(kOrganization, c.OrganizationID)
+	if beta := c.betaHeader(endpoint); beta != "" {
+		req.Header.Set(kBeta, beta)
+	}
 
 	if c.Verbose {
 		if dumped, err := httputil.DumpRequest(req, true); err == nil {
@@ -576,13 +792,90 @@ func (c *Client) postCBWithContext(ctx context.Context, endpoint string, params
 // checks if given params include any file param
 func hasFileInParams(params map[string]any) bool {
 	for _, v := range params {
-		if _, ok := v.(FileParam); ok {
+		switch v.(type) {
+		case FileParam, []FileParam:
 			return true
 		}
 	}
 	return false
 }
 
+// returns a multipart/form-data body of `params`, with its content type
+func multipartBody(params map[string]any) (body *bytes.Buffer, contentType string, err error) {
+	body = &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for k, v := range params {
+		switch val := v.(type) {
+		case FileParam:
+			if err = writeFilePart(writer, k, k, val); err != nil {
+				return nil, "", err
+			}
+		case []FileParam: // repeated files, eg. `image[]`
+			for i, file := range val {
+				if err = writeFilePart(writer, k, fmt.Sprintf("%s-%d", strings.TrimSuffix(k, "[]"), i+1), file); err != nil {
+					return nil, "", err
+				}
+			}
+		case []string: // repeated fields, eg. `include[]`
+			for _, s := range val {
+				if err := writer.WriteField(k, s); err != nil {
+					return nil, "", fmt.Errorf("could not write field with key: %s, value: %v", k, s)
+				}
+			}
+		default:
+			var field string
+			if field, err = multipartFieldValue(v); err != nil {
+				return nil, "", fmt.Errorf("could not encode field with key: %s, value: %v: %s", k, v, err)
+			}
+			if err := writer.WriteField(k, field); err != nil {
+				return nil, "", fmt.Errorf("could not write field with key: %s, value: %v", k, v)
+			}
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("error while closing multipart form data writer: %s", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// writes a file part of `key` with its bytes, named with its filename or `name` with an extension
+func writeFilePart(writer *multipart.Writer, key, name string, file FileParam) error {
+	bs := file.bs
+	filename := file.filename
+	if filename == "" {
+		filename = fmt.Sprintf("%s.%s", name, getExtension(bs))
+	}
+
+	part, err := writer.CreatePart(mimeHeaderForBytes(bs, key, filename))
+	if err != nil {
+		return fmt.Errorf("could not create part for param '%s': %s", key, err)
+	}
+	if _, err = io.Copy(part, bytes.NewReader(bs)); err != nil {
+		return fmt.Errorf("could not write bytes to multipart for param '%s': %s", key, err)
+	}
+	return nil
+}
+
+// returns the value of a multipart field: as it is for primitive values, or JSON-encoded for the others
+func multipartFieldValue(v any) (string, error) {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%v", v), nil
+	}
+
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
 // get file extension from given bytes array
 //
 // https://www.w3.org/Protocols/rfc1341/4_Content-Type.html
@@ -604,10 +897,13 @@ func getExtension(bytes []byte) string {
 	return ""
 }
 
+// escapes quotes and backslashes in multipart header values (same as mime/multipart)
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+
 // generates mime header
 func mimeHeaderForBytes(bs []byte, key, filename string) textproto.MIMEHeader {
 	h := make(textproto.MIMEHeader)
-	h.Set(kContentDisposition, fmt.Sprintf(`form-data; name="%s"; filename="%s"`, key, filename))
+	h.Set(kContentDisposition, fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(key), quoteEscaper.Replace(filename)))
 	h.Set(kContentType, http.DetectContentType(bs))
 	return h
 }
@@ -11,11 +11,35 @@ import (
 type GeneratedImages struct {
 	CommonResponse
 
-	Created int64 `json:"created"`
-	Data    []struct {
-		URL        *string `json:"url,omitempty"`
-		Base64JSON *string `json:"b64_json,omitempty"`
-	} `json:"data"`
+	Created int64            `json:"created"`
+	Data    []GeneratedImage `json:"data"`
+
+	// for gpt-image-1
+	Background   *ImageBackground   `json:"background,omitempty"`
+	OutputFormat *ImageOutputFormat `json:"output_format,omitempty"`
+	Quality      *ImageQuality      `json:"quality,omitempty"`
+	Size         *ImageSize         `json:"size,omitempty"`
+	Usage        *ImageUsage        `json:"usage,omitempty"`
+}
+
+// GeneratedImage struct for an image in image creation responses
+type GeneratedImage struct {
+	URL           *string `json:"url,omitempty"`
+	Base64JSON    *string `json:"b64_json,omitempty"`
+	RevisedPrompt *string `json:"revised_prompt,omitempty"` // for dall-e-3
+}
+
+// ImageUsage struct for token usages of image generation (gpt-image-1)
+//
+// https://platform.openai.com/docs/api-reference/images/object#images/object-usage
+type ImageUsage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails *struct {
+		TextTokens  int `json:"text_tokens"`
+		ImageTokens int `json:"image_tokens"`
+	} `json:"input_tokens_details,omitempty"`
 }
 
 // ImageSize type for constants
@@ -31,6 +55,61 @@ const (
 	ImageSize1024x1024_DallE3 ImageSize = "1024x1024"
 	ImageSize1792x1024_DallE3 ImageSize = "1792x1024"
 	ImageSize1024x1792_DallE3 ImageSize = "1024x1792"
+
+	// for gpt-image-1
+	ImageSize1024x1024_GPTImage1 ImageSize = "1024x1024"
+	ImageSize1536x1024_GPTImage1 ImageSize = "1536x1024"
+	ImageSize1024x1536_GPTImage1 ImageSize = "1024x1536"
+	ImageSizeAuto                ImageSize = "auto"
+)
+
+// ImageQuality type for constants
+type ImageQuality string
+
+const (
+	// for dall-e-3
+	ImageQualityStandard ImageQuality = "standard"
+	ImageQualityHD       ImageQuality = "hd"
+
+	// for gpt-image-1
+	ImageQualityLow    ImageQuality = "low"
+	ImageQualityMedium ImageQuality = "medium"
+	ImageQualityHigh   ImageQuality = "high"
+	ImageQualityAuto   ImageQuality = "auto"
+)
+
+// ImageBackground type for constants
+type ImageBackground string
+
+const (
+	ImageBackgroundTransparent ImageBackground = "transparent"
+	ImageBackgroundOpaque      ImageBackground = "opaque"
+	ImageBackgroundAuto        ImageBackground = "auto"
+)
+
+// ImageOutputFormat type for constants
+type ImageOutputFormat string
+
+const (
+	ImageOutputFormatPNG  ImageOutputFormat = "png"
+	ImageOutputFormatJPEG ImageOutputFormat = "jpeg"
+	ImageOutputFormatWebP ImageOutputFormat = "webp"
+)
+
+// ImageModeration type for constants
+type ImageModeration string
+
+const (
+	ImageModerationLow  ImageModeration = "low"
+	ImageModerationAuto ImageModeration = "auto"
+)
+
+// ImageInputFidelity type for constants
+type ImageInputFidelity string
+
+const (
+	ImageInputFidelityHigh ImageInputFidelity = "high"
+	ImageInputFidelityLow  ImageInputFidelity = "low"
 )
 
 // ImageStyle type for constants
@@ -72,7 +151,8 @@ func (o ImageOptions) SetN(n int) ImageOptions {
 
 // SetQuality sets the `quality` parameter of image generation request.
 //
-// NOTE: 'hd' supported only for model: `dall-e-3`
+// NOTE: 'hd' supported only for model: `dall-e-3`, and 'low', 'medium', 'high' only for model: `gpt-image-1`
+// (ImageQuality constants can be given like `string(ImageQualityHigh)`)
 //
 // https://platform.openai.com/docs/api-reference/images/create#images-create-quality
 func (o ImageOptions) SetQuality(quality string) ImageOptions {
@@ -80,6 +160,56 @@ func (o ImageOptions) SetQuality(quality string) ImageOptions {
 	return o
 }
 
+// SetBackground sets the `background` parameter of image generation request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-background
+func (o ImageOptions) SetBackground(background ImageBackground) ImageOptions {
+	o["background"] = background
+	return o
+}
+
+// SetOutputFormat sets the `output_format` parameter of image generation request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-output_format
+func (o ImageOptions) SetOutputFormat(format ImageOutputFormat) ImageOptions {
+	o["output_format"] = format
+	return o
+}
+
+// SetOutputCompression sets the `output_compression` parameter (0-100) of image generation request.
+//
+// NOTE: supported only for model: `gpt-image-1` with `output_format` of 'jpeg' or 'webp'
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-output_compression
+func (o ImageOptions) SetOutputCompression(compression int) ImageOptions {
+	o["output_compression"] = compression
+	return o
+}
+
+// SetModeration sets the `moderation` parameter of image generation request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-moderation
+func (o ImageOptions) SetModeration(moderation ImageModeration) ImageOptions {
+	o["moderation"] = moderation
+	return o
+}
+
+// SetPartialImages sets the `partial_images` parameter (0-3) of streaming image generation request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-partial_images
+func (o ImageOptions) SetPartialImages(partialImages int) ImageOptions {
+	o["partial_images"] = partialImages
+	return o
+}
+
 // SetResponseFormat sets the `response_format` parameter of image generation request.
 //
 // https://platform.openai.com/docs/api-reference/images/create#images/create-response_format
@@ -195,6 +325,66 @@ func (o ImageEditOptions) SetUser(user string) ImageEditOptions {
 	return o
 }
 
+// SetQuality sets the `quality` parameter of image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-quality
+func (o ImageEditOptions) SetQuality(quality ImageQuality) ImageEditOptions {
+	o["quality"] = quality
+	return o
+}
+
+// SetBackground sets the `background` parameter of image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-background
+func (o ImageEditOptions) SetBackground(background ImageBackground) ImageEditOptions {
+	o["background"] = background
+	return o
+}
+
+// SetOutputFormat sets the `output_format` parameter of image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-output_format
+func (o ImageEditOptions) SetOutputFormat(format ImageOutputFormat) ImageEditOptions {
+	o["output_format"] = format
+	return o
+}
+
+// SetOutputCompression sets the `output_compression` parameter (0-100) of image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1` with `output_format` of 'jpeg' or 'webp'
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-output_compression
+func (o ImageEditOptions) SetOutputCompression(compression int) ImageEditOptions {
+	o["output_compression"] = compression
+	return o
+}
+
+// SetInputFidelity sets the `input_fidelity` parameter of image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-input_fidelity
+func (o ImageEditOptions) SetInputFidelity(fidelity ImageInputFidelity) ImageEditOptions {
+	o["input_fidelity"] = fidelity
+	return o
+}
+
+// SetPartialImages sets the `partial_images` parameter (0-3) of streaming image edit request.
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-partial_images
+func (o ImageEditOptions) SetPartialImages(partialImages int) ImageEditOptions {
+	o["partial_images"] = partialImages
+	return o
+}
+
 // CreateImageEdit creates an edited or extended image with given file and prompt.
 //
 // https://platform.openai.com/docs/api-reference/images/create-edit
@@ -205,6 +395,26 @@ func (c *Client) CreateImageEdit(image FileParam, prompt string, options ImageEd
 	options["image"] = image
 	options["prompt"] = prompt
 
+	return c.createImageEdit(options)
+}
+
+// CreateImageEditWithImages creates an edited image with given files and prompt.
+//
+// Images are sent as repeated `image[]` parts, so it is supported only for model: `gpt-image-1`.
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-image
+func (c *Client) CreateImageEditWithImages(images []FileParam, prompt string, options ImageEditOptions) (response GeneratedImages, err error) {
+	if options == nil {
+		options = ImageEditOptions{}
+	}
+	options["image[]"] = images
+	options["prompt"] = prompt
+
+	return c.createImageEdit(options)
+}
+
+// requests image edits with given options
+func (c *Client) createImageEdit(options ImageEditOptions) (response GeneratedImages, err error) {
 	var bytes []byte
 	if bytes, err = c.post("images/edits", options); err == nil {
 		if err = json.Unmarshal(bytes, &response); err == nil {
@@ -0,0 +1,399 @@
+package openai
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // for decoding images
+	_ "image/jpeg" // for decoding images
+	_ "image/png"  // for decoding images
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// helpers for decoding generated images, and preparing images for edits and variations
+
+const (
+	imageInputMaxBytes = 4 * 1024 * 1024 // API limit of `image` and `mask` for dall-e-2
+
+	imageDownscaleMargin = 0.95 // scale a little more than estimated, for avoiding re-encoding
+)
+
+// Bytes returns the bytes of a generated image, decoded from `b64_json` or downloaded from `url`.
+func (i GeneratedImage) Bytes(ctx context.Context) (bs []byte, err error) {
+	if i.Base64JSON != nil {
+		if bs, err = base64.StdEncoding.DecodeString(*i.Base64JSON); err != nil {
+			return nil, fmt.Errorf("failed to decode base64 image: %s", err)
+		}
+		return bs, nil
+	}
+	if i.URL == nil {
+		return nil, fmt.Errorf("no `url` or `b64_json` in generated image")
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, *i.URL, nil); err != nil {
+		return nil, fmt.Errorf("failed to create image download request: %s", err)
+	}
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		return nil, fmt.Errorf("failed to download image: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: http status %d", resp.StatusCode)
+	}
+	if bs, err = io.ReadAll(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read downloaded image: %s", err)
+	}
+	return bs, nil
+}
+
+// Image returns a generated image decoded into `image.Image`, with its format name (eg. 'png').
+//
+// NOTE: images of `output_format` 'webp' cannot be decoded with the standard library.
+func (i GeneratedImage) Image(ctx context.Context) (img image.Image, format string, err error) {
+	var bs []byte
+	if bs, err = i.Bytes(ctx); err != nil {
+		return nil, "", err
+	}
+	if img, format, err = image.Decode(bytes.NewReader(bs)); err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %s", err)
+	}
+	return img, format, nil
+}
+
+// Save writes a generated image to the file at `path`.
+func (i GeneratedImage) Save(ctx context.Context, path string) (err error) {
+	var bs []byte
+	if bs, err = i.Bytes(ctx); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bs, 0644)
+}
+
+// Save writes all generated images to files in `dir`, named `name`-1.png, `name`-2.png, and so on,
+// and returns their paths.
+//
+// Extensions of files are detected from their contents.
+func (r GeneratedImages) Save(ctx context.Context, dir, name string) (paths []string, err error) {
+	paths = []string{}
+	for i, generated := range r.Data {
+		var bs []byte
+		if bs, err = generated.Bytes(ctx); err != nil {
+			return paths, fmt.Errorf("failed to get image %d: %s", i+1, err)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%s-%d.%s", name, i+1, getExtension(bs)))
+		if err = os.WriteFile(path, bs, 0644); err != nil {
+			return paths, fmt.Errorf("failed to save image %d: %s", i+1, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// ImageSquareMode type for constants
+type ImageSquareMode string
+
+const (
+	ImageSquareNone ImageSquareMode = ""     // keep the aspect ratio
+	ImageSquarePad  ImageSquareMode = "pad"  // pad the shorter side with transparent pixels
+	ImageSquareCrop ImageSquareMode = "crop" // crop the longer side around the center
+)
+
+// PrepareImageOptions struct for preparing images for edits and variations
+type PrepareImageOptions struct {
+	// how to make the image square (dall-e-2 accepts only square images)
+	Square ImageSquareMode
+
+	// maximum size of the encoded image in bytes (default: 4MB)
+	MaxBytes int
+}
+
+// PrepareImage converts `img` into an RGBA PNG for `CreateImageEdit` or `CreateImageVariation`,
+// making it square and downscaling it under the size limit as requested in `options`.
+func PrepareImage(img image.Image, options PrepareImageOptions) (file FileParam, err error) {
+	var files []FileParam
+	if files, err = prepareImages([]image.Image{img}, options); err != nil {
+		return FileParam{}, err
+	}
+	return files[0], nil
+}
+
+// PrepareImageFromBytes decodes `bs` (PNG, JPEG, or GIF) and prepares it with `PrepareImage`.
+func PrepareImageFromBytes(bs []byte, options PrepareImageOptions) (file FileParam, err error) {
+	var img image.Image
+	if img, _, err = image.Decode(bytes.NewReader(bs)); err != nil {
+		return FileParam{}, fmt.Errorf("failed to decode image: %s", err)
+	}
+	return PrepareImage(img, options)
+}
+
+// PrepareImageAndMask validates `mask` against `img` with `ValidateImageMask`,
+// and prepares both of them with `PrepareImage` in the same way, so that they still match.
+func PrepareImageAndMask(img, mask image.Image, options PrepareImageOptions) (imageFile, maskFile FileParam, err error) {
+	if err = ValidateImageMask(img, mask); err != nil {
+		return FileParam{}, FileParam{}, err
+	}
+
+	var files []FileParam
+	if files, err = prepareImages([]image.Image{img, mask}, options); err != nil {
+		return FileParam{}, FileParam{}, err
+	}
+	return files[0], files[1], nil
+}
+
+// ValidateImageMask checks if `mask` has the same dimensions as `img`,
+// and has fully transparent pixels which indicate where `img` should be edited.
+func ValidateImageMask(img, mask image.Image) error {
+	ib, mb := img.Bounds(), mask.Bounds()
+	if ib.Dx() != mb.Dx() || ib.Dy() != mb.Dy() {
+		return fmt.Errorf("mask dimensions (%dx%d) do not match image dimensions (%dx%d)", mb.Dx(), mb.Dy(), ib.Dx(), ib.Dy())
+	}
+
+	for y := mb.Min.Y; y < mb.Max.Y; y++ {
+		for x := mb.Min.X; x < mb.Max.X; x++ {
+			if _, _, _, a := mask.At(x, y).RGBA(); a == 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("mask has no fully transparent pixels: it needs an alpha channel where transparent areas indicate the edit")
+}
+
+// converts `images` into RGBA PNGs of the same dimensions, downscaled together until all of them fit in the limit
+func prepareImages(images []image.Image, options PrepareImageOptions) (files []FileParam, err error) {
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = imageInputMaxBytes
+	}
+
+	converted := make([]*image.NRGBA, len(images))
+	for i, img := range images {
+		if img.Bounds().Empty() {
+			return nil, fmt.Errorf("image has no pixels")
+		}
+		converted[i] = squareImage(toNRGBA(img), options.Square)
+	}
+
+	for {
+		encoded := make([][]byte, len(converted))
+		largest := 0
+		for i, img := range converted {
+			if encoded[i], err = encodeRGBAPNG(img); err != nil {
+				return nil, fmt.Errorf("failed to encode png: %s", err)
+			}
+			if len(encoded[i]) > largest {
+				largest = len(encoded[i])
+			}
+		}
+
+		if largest <= options.MaxBytes {
+			files = []FileParam{}
+			for _, bs := range encoded {
+				files = append(files, NewFileParamFromBytes(bs))
+			}
+			return files, nil
+		}
+
+		// encoded size is roughly proportional to the number of pixels
+		scale := math.Sqrt(float64(options.MaxBytes)/float64(largest)) * imageDownscaleMargin
+		b := converted[0].Bounds()
+		width, height := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+		if width < 1 || height < 1 {
+			return nil, fmt.Errorf("cannot downscale image under %d bytes", options.MaxBytes)
+		}
+		for i, img := range converted {
+			converted[i] = downscaleImage(img, width, height)
+		}
+	}
+}
+
+// converts `img` into NRGBA with its bounds starting at (0, 0)
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	converted := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(converted, converted.Bounds(), img, b.Min, draw.Src)
+	return converted
+}
+
+// makes `img` square with given `mode`
+func squareImage(img *image.NRGBA, mode ImageSquareMode) *image.NRGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width == height {
+		return img
+	}
+
+	switch mode {
+	case ImageSquarePad:
+		size := width
+		if height > size {
+			size = height
+		}
+		padded := image.NewNRGBA(image.Rect(0, 0, size, size)) // transparent
+		offset := image.Pt((size-width)/2, (size-height)/2)
+		draw.Draw(padded, img.Bounds().Add(offset), img, image.Point{}, draw.Src)
+		return padded
+	case ImageSquareCrop:
+		size := minInt(width, height)
+		cropped := image.NewNRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(cropped, cropped.Bounds(), img, image.Pt((width-size)/2, (height-size)/2), draw.Src)
+		return cropped
+	}
+	return img
+}
+
+// downscales `img` to `width` x `height` by averaging source pixels in each destination pixel
+func downscaleImage(img *image.NRGBA, width, height int) *image.NRGBA {
+	sw, sh := img.Bounds().Dx(), img.Bounds().Dy()
+	scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, (y+1)*sh/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, (x+1)*sw/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			// average with premultiplied alpha, for not bleeding colors of transparent pixels
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := img.NRGBAAt(sx, sy)
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			if a > 0 {
+				scaled.SetNRGBA(x, y, color.NRGBA{
+					R: uint8(r / a),
+					G: uint8(g / a),
+					B: uint8(b / a),
+					A: uint8(a / n),
+				})
+			}
+		}
+	}
+	return scaled
+}
+
+// encodes `img` as a PNG of color type RGBA
+//
+// (`image/png` encodes opaque images as RGB, which are rejected by image edits and variations)
+func encodeRGBAPNG(img *image.NRGBA) ([]byte, error) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+
+	writeChunk := func(typ string, data []byte) {
+		binary.Write(buf, binary.BigEndian, uint32(len(data)))
+		crc := crc32.NewIEEE()
+		io.WriteString(crc, typ)
+		crc.Write(data)
+		buf.WriteString(typ)
+		buf.Write(data)
+		binary.Write(buf, binary.BigEndian, crc.Sum32())
+	}
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(height))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // color type: RGBA
+	writeChunk("IHDR", ihdr)
+
+	idat := &bytes.Buffer{}
+	zw, err := zlib.NewWriterLevel(idat, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	stride := width * 4
+	previous := make([]byte, stride)
+	filtered := make([]byte, 1+stride)
+	best := make([]byte, 1+stride)
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+stride]
+
+		// choose the filter with the smallest sum of absolute differences, as `image/png` does
+		bestSum := -1
+		for filter := byte(0); filter <= 4; filter++ {
+			filtered[0] = filter
+			sum := 0
+			for i := 0; i < stride; i++ {
+				var left, up, upLeft byte
+				if i >= 4 {
+					left, upLeft = row[i-4], previous[i-4]
+				}
+				up = previous[i]
+
+				var predicted byte
+				switch filter {
+				case 1:
+					predicted = left
+				case 2:
+					predicted = up
+				case 3:
+					predicted = byte((int(left) + int(up)) / 2)
+				case 4:
+					predicted = paethPredictor(left, up, upLeft)
+				}
+				filtered[1+i] = row[i] - predicted
+				sum += absInt(int(int8(filtered[1+i])))
+			}
+			if bestSum < 0 || sum < bestSum {
+				bestSum = sum
+				copy(best, filtered)
+			}
+		}
+		if _, err = zw.Write(best); err != nil {
+			return nil, err
+		}
+		copy(previous, row)
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	writeChunk("IDAT", idat.Bytes())
+	writeChunk("IEND", nil)
+
+	return buf.Bytes(), nil
+}
+
+// returns the paeth predictor of PNG filter
+func paethPredictor(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := absInt(p-int(a)), absInt(p-int(b)), absInt(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	} else if pb <= pc {
+		return b
+	}
+	return c
+}
+
+// returns the absolute value of `n`
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
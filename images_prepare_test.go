@@ -0,0 +1,159 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// returns an opaque image of given dimensions, filled with noise for not being compressed well
+func newTestImage(width, height int) *image.RGBA {
+	random := rand.New(rand.NewSource(42))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(random.Intn(256)), G: uint8(x), B: uint8(y), A: 0xFF})
+		}
+	}
+	return img
+}
+
+// decodes a prepared file param, and checks if it is an RGBA PNG
+func decodePreparedImage(t *testing.T, file FileParam) image.Image {
+	t.Helper()
+
+	if len(file.bs) < 26 || file.bs[25] != 6 {
+		t.Fatalf("Expected a png of color type RGBA")
+	}
+	img, err := png.Decode(bytes.NewReader(file.bs))
+	if err != nil {
+		t.Fatalf("Failed to decode prepared image: %v", err)
+	}
+	return img
+}
+
+func TestPrepareImage(t *testing.T) {
+	src := newTestImage(300, 200)
+
+	// pad
+	file, err := PrepareImage(src, PrepareImageOptions{Square: ImageSquarePad})
+	if err != nil {
+		t.Fatalf("Failed to prepare padded image: %v", err)
+	}
+	padded := decodePreparedImage(t, file)
+	if b := padded.Bounds(); b.Dx() != 300 || b.Dy() != 300 {
+		t.Errorf("Expected 300x300 padded image, got %v", b)
+	}
+	if _, _, _, a := padded.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("Expected padded area to be transparent, got alpha %d", a)
+	}
+	if c := color.NRGBAModel.Convert(padded.At(10, 60)).(color.NRGBA); c != color.NRGBAModel.Convert(src.At(10, 10)) {
+		t.Errorf("Expected pixels to be centered, got %v", c)
+	}
+
+	// crop
+	if file, err = PrepareImage(src, PrepareImageOptions{Square: ImageSquareCrop}); err != nil {
+		t.Fatalf("Failed to prepare cropped image: %v", err)
+	}
+	if b := decodePreparedImage(t, file).Bounds(); b.Dx() != 200 || b.Dy() != 200 {
+		t.Errorf("Expected 200x200 cropped image, got %v", b)
+	}
+
+	// downscale
+	if file, err = PrepareImage(src, PrepareImageOptions{Square: ImageSquareCrop, MaxBytes: 30 * 1024}); err != nil {
+		t.Fatalf("Failed to prepare downscaled image: %v", err)
+	}
+	if len(file.bs) > 30*1024 {
+		t.Errorf("Expected image under 30KB, got %d bytes", len(file.bs))
+	}
+	if b := decodePreparedImage(t, file).Bounds(); b.Dx() != b.Dy() || b.Dx() >= 200 {
+		t.Errorf("Expected downscaled square image, got %v", b)
+	}
+
+	// invalid bytes
+	if _, err := PrepareImageFromBytes([]byte("not an image"), PrepareImageOptions{}); err == nil {
+		t.Errorf("Expected an error for invalid image bytes")
+	}
+}
+
+func TestPrepareImageAndMask(t *testing.T) {
+	src := newTestImage(120, 80)
+
+	mask := image.NewNRGBA(src.Bounds())
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 120; x++ {
+			if x >= 60 {
+				mask.SetNRGBA(x, y, color.NRGBA{A: 0xFF})
+			}
+		}
+	}
+
+	imageFile, maskFile, err := PrepareImageAndMask(src, mask, PrepareImageOptions{Square: ImageSquarePad})
+	if err != nil {
+		t.Fatalf("Failed to prepare image and mask: %v", err)
+	}
+	preparedImage, preparedMask := decodePreparedImage(t, imageFile), decodePreparedImage(t, maskFile)
+	if preparedImage.Bounds() != preparedMask.Bounds() || preparedMask.Bounds().Dx() != 120 {
+		t.Errorf("Expected 120x120 image and mask, got %v and %v", preparedImage.Bounds(), preparedMask.Bounds())
+	}
+
+	if err := ValidateImageMask(src, image.NewNRGBA(image.Rect(0, 0, 100, 80))); err == nil {
+		t.Errorf("Expected an error for mismatched dimensions")
+	}
+	if err := ValidateImageMask(src, newTestImage(120, 80)); err == nil {
+		t.Errorf("Expected an error for mask without transparent pixels")
+	}
+}
+
+func TestGeneratedImagesSave(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, newTestImage(4, 4)); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(encoded.Bytes())
+	}))
+	defer server.Close()
+
+	b64 := base64.StdEncoding.EncodeToString(encoded.Bytes())
+	url := server.URL + "/image.png"
+	generated := GeneratedImages{Data: []GeneratedImage{{Base64JSON: &b64}, {URL: &url}}}
+
+	img, format, err := generated.Data[1].Image(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to decode downloaded image: %v", err)
+	}
+	if format != "png" || img.Bounds().Dx() != 4 {
+		t.Errorf("Unexpected image: %s, %v", format, img.Bounds())
+	}
+
+	dir := t.TempDir()
+	paths, err := generated.Save(context.Background(), dir, "otter")
+	if err != nil {
+		t.Fatalf("Failed to save images: %v", err)
+	}
+	if len(paths) != 2 || paths[1] != filepath.Join(dir, "otter-2.png") {
+		t.Errorf("Unexpected paths: %v", paths)
+	}
+	for _, path := range paths {
+		if bs, err := os.ReadFile(path); err != nil || !bytes.Equal(bs, encoded.Bytes()) {
+			t.Errorf("Unexpected saved file %s: %v", path, err)
+		}
+	}
+
+	if _, err := (GeneratedImage{}).Bytes(context.Background()); err == nil {
+		t.Errorf("Expected an error for empty generated image")
+	}
+}
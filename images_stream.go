@@ -0,0 +1,114 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+)
+
+// streaming image generation and edits (gpt-image-1)
+
+// ImageStreamEventType type for constants
+type ImageStreamEventType string
+
+const (
+	ImageStreamEventGenerationPartialImage ImageStreamEventType = "image_generation.partial_image"
+	ImageStreamEventGenerationCompleted    ImageStreamEventType = "image_generation.completed"
+	ImageStreamEventEditPartialImage       ImageStreamEventType = "image_edit.partial_image"
+	ImageStreamEventEditCompleted          ImageStreamEventType = "image_edit.completed"
+	ImageStreamEventError                  ImageStreamEventType = "error"
+)
+
+// ImageStreamEvent struct for server-sent events of image stream
+//
+// https://platform.openai.com/docs/api-reference/images-streaming
+type ImageStreamEvent struct {
+	Type ImageStreamEventType `json:"type"`
+
+	Base64JSON   string            `json:"b64_json,omitempty"`
+	CreatedAt    int64             `json:"created_at,omitempty"`
+	Size         ImageSize         `json:"size,omitempty"`
+	Quality      ImageQuality      `json:"quality,omitempty"`
+	Background   ImageBackground   `json:"background,omitempty"`
+	OutputFormat ImageOutputFormat `json:"output_format,omitempty"`
+
+	PartialImageIndex *int `json:"partial_image_index,omitempty"` // for `*.partial_image`
+
+	Usage *ImageUsage `json:"usage,omitempty"` // for `*.completed`
+	Error *Error      `json:"error,omitempty"`
+}
+
+// ImageBytes decodes the base64-encoded image of the event.
+func (e ImageStreamEvent) ImageBytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.Base64JSON)
+}
+
+// Completed returns if the event has the final image.
+func (e ImageStreamEvent) Completed() bool {
+	return e.Type == ImageStreamEventGenerationCompleted || e.Type == ImageStreamEventEditCompleted
+}
+
+func (e ImageStreamEvent) withName(name string) ImageStreamEvent {
+	if e.Type == "" {
+		e.Type = ImageStreamEventType(name)
+	}
+	return e
+}
+
+func (e ImageStreamEvent) eventError() *Error {
+	return e.Error
+}
+
+func (e ImageStreamEvent) isLast() bool {
+	return e.Completed()
+}
+
+// callback function for image stream events
+type imageStreamCallback func(event ImageStreamEvent, done bool, err error)
+
+// CreateImageStream creates an image with given prompt, and streams its partial images to `cb`.
+//
+// `stream` is set to true, and `cb` is called with `done` == true on the last event (`image_generation.completed`).
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/create#images-create-stream
+func (c *Client) CreateImageStream(ctx context.Context, prompt string, options ImageOptions, cb imageStreamCallback) (err error) {
+	if options == nil {
+		options = ImageOptions{}
+	}
+	options["prompt"] = prompt
+	options["stream"] = true
+
+	return c.streamImageEvents(ctx, "images/generations", options, cb)
+}
+
+// CreateImageEditStream creates an edited image with given files and prompt, and streams its partial images to `cb`.
+//
+// `stream` is set to true, and `cb` is called with `done` == true on the last event (`image_edit.completed`).
+//
+// NOTE: supported only for model: `gpt-image-1`
+//
+// https://platform.openai.com/docs/api-reference/images/createEdit#images-createedit-stream
+func (c *Client) CreateImageEditStream(ctx context.Context, images []FileParam, prompt string, options ImageEditOptions, cb imageStreamCallback) (err error) {
+	if options == nil {
+		options = ImageEditOptions{}
+	}
+	options["image[]"] = images
+	options["prompt"] = prompt
+	options["stream"] = true
+
+	return c.streamImageEvents(ctx, "images/edits", options, cb)
+}
+
+// opens an image stream, and reads its events in a goroutine
+func (c *Client) streamImageEvents(ctx context.Context, endpoint string, params map[string]any, cb imageStreamCallback) (err error) {
+	var resp *http.Response
+	if resp, err = c.openStreamWithContext(ctx, endpoint, params); err != nil {
+		return err
+	}
+
+	streamTypedEvents(ctx, resp, cb)
+
+	return nil
+}
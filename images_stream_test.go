@@ -0,0 +1,120 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateImageStreamMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if body["stream"] != true || body["partial_images"] != float64(2) || body["moderation"] != "low" {
+			t.Errorf("Unexpected request body: %v", body)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: image_generation.partial_image\ndata: {\"type\":\"image_generation.partial_image\",\"b64_json\":\"cGFydGlhbC0w\",\"partial_image_index\":0}\n\n")
+		fmt.Fprint(w, "event: image_generation.partial_image\ndata: {\"type\":\"image_generation.partial_image\",\"b64_json\":\"cGFydGlhbC0x\",\"partial_image_index\":1}\n\n")
+		fmt.Fprint(w, "event: image_generation.completed\ndata: {\"type\":\"image_generation.completed\",\"b64_json\":\"ZmluYWw=\",\"output_format\":\"png\",\"usage\":{\"input_tokens\":10,\"output_tokens\":100,\"total_tokens\":110}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	partials := []string{}
+	var final []byte
+	var usage *ImageUsage
+	finished := make(chan error, 1)
+	if err := client.CreateImageStream(context.Background(), "A cute baby sea otter", ImageOptions{}.
+		SetModel("gpt-image-1").
+		SetModeration(ImageModerationLow).
+		SetPartialImages(2), func(event ImageStreamEvent, done bool, err error) {
+		if err == nil {
+			image, _ := event.ImageBytes()
+			switch event.Type {
+			case ImageStreamEventGenerationPartialImage:
+				if event.PartialImageIndex == nil || *event.PartialImageIndex != len(partials) {
+					t.Errorf("Unexpected partial image index: %v", event.PartialImageIndex)
+				}
+				partials = append(partials, string(image))
+			case ImageStreamEventGenerationCompleted:
+				final, usage = image, event.Usage
+			}
+		}
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateImageStream failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+	if strings.Join(partials, ",") != "partial-0,partial-1" {
+		t.Errorf("Unexpected partial images: %v", partials)
+	}
+	if string(final) != "final" || usage == nil || usage.TotalTokens != 110 {
+		t.Errorf("Unexpected final image: %q, usage: %+v", final, usage)
+	}
+}
+
+func TestCreateImageEditStreamMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+			return
+		}
+		if r.FormValue("stream") != "true" || len(r.MultipartForm.File["image[]"]) != 1 {
+			t.Errorf("Unexpected multipart form: %v, %v", r.MultipartForm.Value, r.MultipartForm.File)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"image_edit.partial_image\",\"b64_json\":\"cGFydGlhbA==\",\"partial_image_index\":0}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"image_edit.completed\",\"b64_json\":\"ZmluYWw=\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	events := []ImageStreamEventType{}
+	finished := make(chan error, 1)
+	if err := client.CreateImageEditStream(context.Background(), []FileParam{NewFileParamFromBytes([]byte("\x89PNG\r\n\x1a\n...."))}, "Add a hat", nil, func(event ImageStreamEvent, done bool, err error) {
+		if err == nil {
+			events = append(events, event.Type)
+		}
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateImageEditStream failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+	if len(events) != 2 || events[1] != ImageStreamEventEditCompleted {
+		t.Errorf("Unexpected events: %v", events)
+	}
+}
@@ -1,6 +1,8 @@
 package openai
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -64,3 +66,47 @@ func TestImages(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateImageEditWithImagesMock(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n....")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+			return
+		}
+		if images := r.MultipartForm.File["image[]"]; len(images) != 2 || images[1].Filename != "image-2.png" {
+			t.Errorf("Expected 2 `image[]` parts, got %+v", images)
+		}
+		if r.FormValue("background") != "transparent" || r.FormValue("output_format") != "webp" || r.FormValue("output_compression") != "80" || r.FormValue("input_fidelity") != "high" {
+			t.Errorf("Unexpected form values: %v", r.MultipartForm.Value)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"created":1713833628,"data":[{"b64_json":"aW1hZ2U=","revised_prompt":"A gift basket."}],"background":"transparent","output_format":"webp","quality":"high","size":"1024x1024","usage":{"input_tokens":50,"output_tokens":4160,"total_tokens":4210,"input_tokens_details":{"text_tokens":10,"image_tokens":40}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	edited, err := client.CreateImageEditWithImages([]FileParam{NewFileParamFromBytes(png), NewFileParamFromBytes(png)}, "A gift basket with these items",
+		ImageEditOptions{}.
+			SetModel("gpt-image-1").
+			SetBackground(ImageBackgroundTransparent).
+			SetOutputFormat(ImageOutputFormatWebP).
+			SetOutputCompression(80).
+			SetInputFidelity(ImageInputFidelityHigh))
+	if err != nil {
+		t.Fatalf("CreateImageEditWithImages failed: %v", err)
+	}
+	if len(edited.Data) != 1 || edited.Data[0].RevisedPrompt == nil || *edited.Data[0].RevisedPrompt != "A gift basket." {
+		t.Errorf("Unexpected data: %+v", edited.Data)
+	}
+	if edited.Usage == nil || edited.Usage.TotalTokens != 4210 || edited.Usage.InputTokensDetails == nil || edited.Usage.InputTokensDetails.ImageTokens != 40 {
+		t.Errorf("Unexpected usage: %+v", edited.Usage)
+	}
+	if edited.Quality == nil || *edited.Quality != ImageQualityHigh {
+		t.Errorf("Unexpected quality: %v", edited.Quality)
+	}
+}
@@ -0,0 +1,264 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+)
+
+// preparing images for vision inputs, for controlling their token costs
+
+const (
+	defaultVisionImageModel   = "gpt-4o"
+	defaultVisionImageQuality = 85
+
+	visionImageLowDetailDimension = 512 // low detail images are processed as 512 x 512
+)
+
+// PrepareVisionImageOptions struct for preparing images for vision inputs
+type PrepareVisionImageOptions struct {
+	// model for estimating token costs (default: gpt-4o)
+	Model string
+
+	// detail of image input (default: auto)
+	Detail ImageDetail
+
+	// format of the re-encoded image: `jpeg` (default) or `png`
+	//
+	// NOTE: `webp` is not supported, as the standard library has no WebP encoder.
+	Format ImageOutputFormat
+
+	// quality (1-100) of JPEG (default: 85)
+	Quality int
+
+	// maximum number of 512px tiles for high detail (default: 0, no limit)
+	//
+	// The image is downscaled further until it fits in these tiles.
+	MaxTiles int
+}
+
+// VisionImage struct for a prepared image of vision input
+type VisionImage struct {
+	Bytes  []byte
+	Width  int
+	Height int
+	Detail ImageDetail
+
+	// estimated token cost
+	Tokens int
+}
+
+// ChatMessageContent returns an `image_url` content of the prepared image for chat completions.
+func (i VisionImage) ChatMessageContent() ChatMessageContent {
+	return NewChatMessageContentWithBytes(i.Bytes).WithDetail(i.Detail)
+}
+
+// ResponseInputImage returns an `input_image` content of the prepared image for responses API.
+func (i VisionImage) ResponseInputImage() ChatMessageContent {
+	return NewResponseInputImageWithBytes(i.Bytes).WithDetail(i.Detail)
+}
+
+// PrepareVisionImage decodes `bs` (PNG, JPEG, or GIF) and prepares it with `PrepareVisionImageFromImage`.
+//
+// JPEG images are rotated as their EXIF orientation, as EXIF is stripped when re-encoded.
+func PrepareVisionImage(bs []byte, options PrepareVisionImageOptions) (prepared VisionImage, err error) {
+	var img image.Image
+	var format string
+	if img, format, err = image.Decode(bytes.NewReader(bs)); err != nil {
+		return VisionImage{}, fmt.Errorf("failed to decode image: %s", err)
+	}
+
+	converted := toNRGBA(img)
+	if format == "jpeg" {
+		converted = orientImage(converted, jpegOrientation(bs))
+	}
+	return PrepareVisionImageFromImage(converted, options)
+}
+
+// PrepareVisionImageFromImage resizes `img` to the dimensions which the model processes for its detail,
+// re-encodes it without metadata, and estimates its token cost.
+func PrepareVisionImageFromImage(img image.Image, options PrepareVisionImageOptions) (prepared VisionImage, err error) {
+	if options.Model == "" {
+		options.Model = defaultVisionImageModel
+	}
+	if options.Detail == "" {
+		options.Detail = ImageDetailAuto
+	}
+	if options.Format == "" {
+		options.Format = ImageOutputFormatJPEG
+	}
+	if options.Quality <= 0 || options.Quality > 100 {
+		options.Quality = defaultVisionImageQuality
+	}
+	if options.Format != ImageOutputFormatJPEG && options.Format != ImageOutputFormatPNG {
+		return VisionImage{}, fmt.Errorf("unsupported format for vision image: %s", options.Format)
+	}
+	if img.Bounds().Empty() {
+		return VisionImage{}, fmt.Errorf("image has no pixels")
+	}
+
+	converted := toNRGBA(img)
+	width, height := visionImageSize(converted.Bounds().Dx(), converted.Bounds().Dy(), options.Detail, options.MaxTiles)
+	if width != converted.Bounds().Dx() || height != converted.Bounds().Dy() {
+		converted = downscaleImage(converted, width, height)
+	}
+
+	buf := &bytes.Buffer{}
+	switch options.Format {
+	case ImageOutputFormatJPEG:
+		// JPEG has no alpha channel, so put transparent pixels on white
+		flattened := image.NewRGBA(converted.Bounds())
+		draw.Draw(flattened, flattened.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flattened, flattened.Bounds(), converted, image.Point{}, draw.Over)
+		err = jpeg.Encode(buf, flattened, &jpeg.Options{Quality: options.Quality})
+	case ImageOutputFormatPNG:
+		err = png.Encode(buf, converted)
+	}
+	if err != nil {
+		return VisionImage{}, fmt.Errorf("failed to encode %s: %s", options.Format, err)
+	}
+
+	return VisionImage{
+		Bytes:  buf.Bytes(),
+		Width:  width,
+		Height: height,
+		Detail: options.Detail,
+		Tokens: ImageTokens(options.Model, width, height, string(options.Detail)),
+	}, nil
+}
+
+// returns the dimensions of an image which the model processes for `detail`, fit in `maxTiles` (0 for no limit)
+func visionImageSize(width, height int, detail ImageDetail, maxTiles int) (int, int) {
+	if detail == ImageDetailLow {
+		if width <= visionImageLowDetailDimension && height <= visionImageLowDetailDimension {
+			return width, height
+		}
+		scale := visionImageLowDetailDimension / math.Max(float64(width), float64(height))
+		return maxInt(1, int(float64(width)*scale)), maxInt(1, int(float64(height)*scale))
+	}
+
+	w, h := highDetailImageSize(width, height)
+	if maxTiles > 0 {
+		for imageTiles(w, h) > maxTiles {
+			// shrink the longer side to one less tile
+			longest := maxInt(w, h)
+			target := (longest+imageTileDimension-1)/imageTileDimension - 1
+			if target < 1 {
+				break
+			}
+			scale := float64(target*imageTileDimension) / float64(longest)
+			w, h = maxInt(1, int(float64(w)*scale)), maxInt(1, int(float64(h)*scale))
+		}
+	}
+	return maxInt(1, w), maxInt(1, h)
+}
+
+// returns the EXIF orientation (1-8) of a JPEG image, or 1 if there is none
+func jpegOrientation(bs []byte) int {
+	if len(bs) < 4 || bs[0] != 0xFF || bs[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(bs) && bs[offset] == 0xFF {
+		marker := bs[offset+1]
+		if marker == 0xDA { // start of scan
+			break
+		}
+		size := int(binary.BigEndian.Uint16(bs[offset+2 : offset+4]))
+		if size < 2 || offset+2+size > len(bs) {
+			break
+		}
+		segment := bs[offset+4 : offset+2+size]
+
+		if marker == 0xE1 && len(segment) >= 14 && bytes.Equal(segment[0:6], []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset += 2 + size
+	}
+	return 1
+}
+
+// returns the orientation tag in IFD0 of EXIF (TIFF) data, or 1 if there is none
+func exifOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 { // orientation
+			if orientation := int(order.Uint16(tiff[entry+8 : entry+10])); orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// rotates and/or flips `img` as given EXIF `orientation`
+func orientImage(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // rotated by 90 or 270 degrees
+		dw, dh = h, w
+	}
+
+	oriented := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // flipped horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated by 180 degrees
+				dx, dy = w-1-x, h-1-y
+			case 4: // flipped vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated by 90 degrees clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated by 90 degrees counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			oriented.SetNRGBA(dx, dy, img.NRGBAAt(x, y))
+		}
+	}
+	return oriented
+}
+
+// returns the larger one of given integers
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
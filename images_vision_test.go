@@ -0,0 +1,116 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// inserts an EXIF segment with given orientation right after SOI of `jpg`
+func withEXIFOrientation(jpg []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // header
+		0x00, 0x01, // 1 entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00, // orientation (SHORT)
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	size := len(payload) + 2
+
+	segment := append([]byte{0xFF, 0xE1, byte(size >> 8), byte(size)}, payload...)
+	return append(append(append([]byte{}, jpg[:2]...), segment...), jpg[2:]...)
+}
+
+func TestPrepareVisionImage(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, newTestImage(1600, 800)); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	for _, tc := range []struct {
+		detail        ImageDetail
+		maxTiles      int
+		width, height int
+		tokens        int
+	}{
+		{ImageDetailHigh, 0, 1536, 768, 1105},
+		{ImageDetailAuto, 3, 1024, 512, 425},
+		{ImageDetailLow, 0, 512, 256, 85},
+	} {
+		prepared, err := PrepareVisionImage(encoded.Bytes(), PrepareVisionImageOptions{Detail: tc.detail, MaxTiles: tc.maxTiles})
+		if err != nil {
+			t.Fatalf("Failed to prepare vision image (%s): %v", tc.detail, err)
+		}
+		if prepared.Width != tc.width || prepared.Height != tc.height || prepared.Tokens != tc.tokens {
+			t.Errorf("Expected %dx%d (%d tokens) for %s, got %dx%d (%d tokens)", tc.width, tc.height, tc.tokens, tc.detail, prepared.Width, prepared.Height, prepared.Tokens)
+		}
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(prepared.Bytes))
+		if err != nil || format != "jpeg" || config.Width != tc.width || config.Height != tc.height {
+			t.Errorf("Unexpected encoded image: %s %dx%d, %v", format, config.Width, config.Height, err)
+		}
+	}
+
+	if _, err := PrepareVisionImage(encoded.Bytes(), PrepareVisionImageOptions{Format: ImageOutputFormatWebP}); err == nil {
+		t.Errorf("Expected an error for unsupported format")
+	}
+}
+
+func TestPrepareVisionImageEXIF(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, newTestImage(40, 20), nil); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	rotated := withEXIFOrientation(encoded.Bytes(), 6)
+
+	if orientation := jpegOrientation(rotated); orientation != 6 {
+		t.Fatalf("Expected orientation 6, got %d", orientation)
+	}
+
+	prepared, err := PrepareVisionImage(rotated, PrepareVisionImageOptions{Format: ImageOutputFormatPNG})
+	if err != nil {
+		t.Fatalf("Failed to prepare vision image: %v", err)
+	}
+	if prepared.Width != 20 || prepared.Height != 40 {
+		t.Errorf("Expected image to be rotated to 20x40, got %dx%d", prepared.Width, prepared.Height)
+	}
+	if bytes.Contains(prepared.Bytes, []byte("Exif")) {
+		t.Errorf("Expected EXIF to be stripped")
+	}
+}
+
+func TestVisionImageContents(t *testing.T) {
+	prepared, err := PrepareVisionImageFromImage(newTestImage(1024, 1024), PrepareVisionImageOptions{Detail: ImageDetailHigh})
+	if err != nil {
+		t.Fatalf("Failed to prepare vision image: %v", err)
+	}
+
+	// chat completions
+	chat := prepared.ChatMessageContent()
+	serialized, _ := json.Marshal(chat)
+	if !strings.Contains(string(serialized), `"detail":"high"`) || !strings.Contains(string(serialized), `"url":"data:image/jpeg;base64,`) {
+		t.Errorf("Unexpected chat content: %s", serialized)
+	}
+	if tokens := chat.ImageTokens("gpt-4o"); tokens != prepared.Tokens {
+		t.Errorf("Expected %d tokens for chat content, got %d", prepared.Tokens, tokens)
+	}
+
+	// responses API
+	input := prepared.ResponseInputImage()
+	serialized, _ = json.Marshal(input)
+	if !strings.Contains(string(serialized), `"type":"input_image","image_url":"data:image/jpeg;base64,`) || !strings.HasSuffix(string(serialized), `"detail":"high"}`) {
+		t.Errorf("Unexpected input image: %s", serialized)
+	}
+	if tokens := input.WithDetail(ImageDetailLow).ImageTokens("gpt-4o"); tokens != 85 {
+		t.Errorf("Expected 85 tokens for low detail input image, got %d", tokens)
+	}
+
+	// url without detail
+	if content := NewChatMessageContentWithImageURL("https://example.com/image.png").WithDetail(ImageDetailLow); content.ImageTokens("gpt-4o") != 85 {
+		t.Errorf("Expected 85 tokens for low detail url, got %d", content.ImageTokens("gpt-4o"))
+	}
+}
@@ -0,0 +1,421 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSON schema generation from Go structs
+//
+// Fields are named after their `json` tags, and can be described with `jsonschema` tags like:
+//
+//	type Args struct {
+//		Location string   `json:"location" jsonschema:"description=City name\, e.g. Seoul"`
+//		Unit     string   `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit"`
+//		Days     int      `json:"days" jsonschema:"minimum=1,maximum=7"`
+//		Tags     []string `json:"tags" jsonschema:"minItems=1"`
+//	}
+//
+// Supported keys of `jsonschema` tags are: description, title, enum (separated with '|'), format, pattern,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength, minItems, maxItems,
+// required, and optional. (commas in values should be escaped with '\')
+//
+// Pointer fields and fields with `omitempty` are treated as optional, unless `required` is given.
+
+var (
+	typeOfTime            = reflect.TypeOf(time.Time{})
+	typeOfJSONRawMessage  = reflect.TypeOf(json.RawMessage{})
+	typeOfByteSlice       = reflect.TypeOf([]byte{})
+	invalidDefNameChars   = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	numericJSONSchemaKeys = map[string]bool{
+		"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true, "multipleOf": true,
+	}
+	integerJSONSchemaKeys = map[string]bool{
+		"minLength": true, "maxLength": true, "minItems": true, "maxItems": true,
+	}
+)
+
+// ToolFunctionParametersFromStruct generates ToolFunctionParameters from the fields of struct `T`,
+// following the rules of strict mode: all properties are required (optional ones are nullable instead),
+// and `additionalProperties` is always false.
+//
+// Types which cannot be expressed in strict mode (maps and interfaces) will return an error;
+// use `ToolFunctionParametersFromStructNonStrict` for them.
+func ToolFunctionParametersFromStruct[T any]() (ToolFunctionParameters, error) {
+	return jsonSchemaFromType(reflect.TypeOf((*T)(nil)).Elem(), true)
+}
+
+// ToolFunctionParametersFromStructNonStrict generates ToolFunctionParameters from the fields of struct `T`,
+// with only non-optional properties required.
+func ToolFunctionParametersFromStructNonStrict[T any]() (ToolFunctionParameters, error) {
+	return jsonSchemaFromType(reflect.TypeOf((*T)(nil)).Elem(), false)
+}
+
+// NewChatCompletionToolFromStruct returns a strict ChatCompletionTool with parameters generated from struct `T`.
+func NewChatCompletionToolFromStruct[T any](name, description string) (tool ChatCompletionTool, err error) {
+	var parameters ToolFunctionParameters
+	if parameters, err = ToolFunctionParametersFromStruct[T](); err != nil {
+		return ChatCompletionTool{}, err
+	}
+
+	strict := true
+	tool = NewChatCompletionTool(name, description, parameters)
+	tool.Function.Strict = &strict
+
+	return tool, nil
+}
+
+// NewResponseToolFromStruct returns a strict ResponseTool with parameters generated from struct `T`.
+func NewResponseToolFromStruct[T any](name, description string) (tool ResponseTool, err error) {
+	var parameters ToolFunctionParameters
+	if parameters, err = ToolFunctionParametersFromStruct[T](); err != nil {
+		return ResponseTool{}, err
+	}
+
+	strict := true
+	tool = NewResponseTool(name, description, parameters)
+	tool.Strict = &strict
+
+	return tool, nil
+}
+
+// generates JSON schema of a struct type
+func jsonSchemaFromType(typ3 reflect.Type, strict bool) (schema map[string]any, err error) {
+	for typ3.Kind() == reflect.Pointer {
+		typ3 = typ3.Elem()
+	}
+	if typ3.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type `%s` is not a struct", typ3)
+	}
+
+	g := &jsonSchemaGenerator{
+		strict:    strict,
+		root:      typ3,
+		visiting:  map[reflect.Type]bool{},
+		recursive: map[reflect.Type]bool{},
+		defs:      map[string]any{},
+		defNames:  map[reflect.Type]string{},
+	}
+	if schema, err = g.schemaOf(typ3); err != nil {
+		return nil, err
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+
+	return schema, nil
+}
+
+// generates JSON schemas of types
+type jsonSchemaGenerator struct {
+	strict bool
+	root   reflect.Type
+
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]any
+	defNames  map[reflect.Type]string
+}
+
+// returns the name of a type in `$defs`
+func (g *jsonSchemaGenerator) defName(typ3 reflect.Type) string {
+	if name, exists := g.defNames[typ3]; exists {
+		return name
+	}
+
+	taken := map[string]bool{}
+	for _, n := range g.defNames {
+		taken[n] = true
+	}
+
+	name := invalidDefNameChars.ReplaceAllString(typ3.Name(), "_")
+	if name == "" {
+		name = "Type"
+	}
+	for i, base := 2, name; taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.defNames[typ3] = name
+
+	return name
+}
+
+// generates JSON schema of a type
+func (g *jsonSchemaGenerator) schemaOf(typ3 reflect.Type) (schema map[string]any, err error) {
+	for typ3.Kind() == reflect.Pointer {
+		typ3 = typ3.Elem()
+	}
+
+	switch typ3 {
+	case typeOfTime:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case typeOfJSONRawMessage:
+		if g.strict {
+			return nil, fmt.Errorf("type `%s` is not supported in strict mode", typ3)
+		}
+		return map[string]any{}, nil
+	case typeOfByteSlice:
+		return map[string]any{"type": "string", "description": "base64-encoded bytes"}, nil
+	}
+
+	switch typ3.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Slice, reflect.Array:
+		var items map[string]any
+		if items, err = g.schemaOf(typ3.Elem()); err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if typ3.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map type `%s` should have string keys", typ3)
+		}
+		if g.strict {
+			return nil, fmt.Errorf("map type `%s` is not supported in strict mode", typ3)
+		}
+		var values map[string]any
+		if values, err = g.schemaOf(typ3.Elem()); err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Interface:
+		if g.strict {
+			return nil, fmt.Errorf("interface type `%s` is not supported in strict mode", typ3)
+		}
+		return map[string]any{}, nil
+	case reflect.Struct:
+		return g.structSchema(typ3)
+	}
+
+	return nil, fmt.Errorf("type `%s` is not supported", typ3)
+}
+
+// generates JSON schema of a struct type, with `$ref` for recursive ones
+func (g *jsonSchemaGenerator) structSchema(typ3 reflect.Type) (schema map[string]any, err error) {
+	if g.visiting[typ3] {
+		g.recursive[typ3] = true
+		if typ3 == g.root {
+			return map[string]any{"$ref": "#"}, nil
+		}
+		return map[string]any{"$ref": "#/$defs/" + g.defName(typ3)}, nil
+	}
+	if name, exists := g.defNames[typ3]; exists {
+		if _, generated := g.defs[name]; generated {
+			return map[string]any{"$ref": "#/$defs/" + name}, nil
+		}
+	}
+
+	g.visiting[typ3] = true
+	defer delete(g.visiting, typ3)
+
+	properties := map[string]any{}
+	required := []string{}
+	if err = g.addFields(typ3, properties, &required); err != nil {
+		return nil, err
+	}
+
+	schema = map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+
+	if g.recursive[typ3] && typ3 != g.root {
+		name := g.defName(typ3)
+		g.defs[name] = schema
+		return map[string]any{"$ref": "#/$defs/" + name}, nil
+	}
+
+	return schema, nil
+}
+
+// adds properties of struct fields, flattening embedded structs like `encoding/json` does
+func (g *jsonSchemaGenerator) addFields(typ3 reflect.Type, properties map[string]any, required *[]string) (err error) {
+	for i := 0; i < typ3.NumField(); i++ {
+		field := typ3.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err = g.addFields(embedded, properties, required); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var schema map[string]any
+		if schema, err = g.schemaOf(field.Type); err != nil {
+			return fmt.Errorf("field `%s`: %s", field.Name, err)
+		}
+
+		optional := field.Type.Kind() == reflect.Pointer || strings.Contains(","+opts+",", ",omitempty,")
+		tags := parseJSONSchemaTag(field.Tag.Get("jsonschema"))
+		if _, exists := tags["required"]; exists {
+			optional = false
+		} else if _, exists := tags["optional"]; exists {
+			optional = true
+		}
+		if schema, err = applyJSONSchemaTags(schema, field.Type, tags); err != nil {
+			return fmt.Errorf("field `%s`: %s", field.Name, err)
+		}
+
+		if g.strict {
+			if optional {
+				schema = nullableJSONSchema(schema)
+			}
+			*required = append(*required, name)
+		} else if !optional {
+			*required = append(*required, name)
+		}
+
+		properties[name] = schema
+	}
+
+	return nil
+}
+
+// makes given schema nullable
+func nullableJSONSchema(schema map[string]any) map[string]any {
+	if typ3, ok := schema["type"].(string); ok {
+		if enum, exists := schema["enum"].([]any); exists {
+			schema["enum"] = append(enum, nil)
+		}
+		schema["type"] = []string{typ3, "null"}
+		return schema
+	}
+
+	// $ref or empty schema
+	nullable := map[string]any{
+		"anyOf": []any{schema, map[string]any{"type": "null"}},
+	}
+	if description, exists := schema["description"]; exists {
+		nullable["description"] = description
+		delete(schema, "description")
+	}
+	return nullable
+}
+
+// parses a `jsonschema` tag into key-value pairs
+func parseJSONSchemaTag(tag string) (tags map[string]string) {
+	tags = map[string]string{}
+
+	var current strings.Builder
+	parts := []string{}
+	escaped := false
+	for _, r := range tag {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	for _, part := range parts {
+		key, value, _ := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		tags[key] = value
+	}
+
+	return tags
+}
+
+// applies `jsonschema` tags to a schema of given type
+func applyJSONSchemaTags(schema map[string]any, typ3 reflect.Type, tags map[string]string) (map[string]any, error) {
+	for typ3.Kind() == reflect.Pointer {
+		typ3 = typ3.Elem()
+	}
+
+	for key, value := range tags {
+		switch {
+		case key == "required" || key == "optional":
+			continue
+		case key == "description" || key == "title" || key == "format" || key == "pattern":
+			if _, isRef := schema["$ref"]; isRef && key == "description" {
+				// $ref cannot have siblings in strict mode
+				continue
+			}
+			schema[key] = value
+		case key == "enum":
+			enum := []any{}
+			for _, v := range strings.Split(value, "|") {
+				switch typ3.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					n, err := strconv.ParseInt(v, 10, 64)
+					if err != nil {
+						return nil, fmt.Errorf("invalid integer enum value '%s': %s", v, err)
+					}
+					enum = append(enum, n)
+				case reflect.Float32, reflect.Float64:
+					f, err := strconv.ParseFloat(v, 64)
+					if err != nil {
+						return nil, fmt.Errorf("invalid number enum value '%s': %s", v, err)
+					}
+					enum = append(enum, f)
+				default:
+					enum = append(enum, v)
+				}
+			}
+			schema["enum"] = enum
+		case numericJSONSchemaKeys[key]:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of `%s`: '%s'", key, value)
+			}
+			schema[key] = f
+		case integerJSONSchemaKeys[key]:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of `%s`: '%s'", key, value)
+			}
+			schema[key] = n
+		default:
+			return nil, fmt.Errorf("unsupported key in jsonschema tag: `%s`", key)
+		}
+	}
+
+	return schema, nil
+}
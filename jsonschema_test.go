@@ -0,0 +1,158 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaTestAddress struct {
+	City    string `json:"city" jsonschema:"description=City name\\, e.g. Seoul"`
+	ZipCode string `json:"zip_code,omitempty" jsonschema:"pattern=^[0-9]{5}$"`
+}
+
+type schemaTestCategory struct {
+	Name     string               `json:"name"`
+	Children []schemaTestCategory `json:"children"`
+}
+
+type schemaTestEmbedded struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type schemaTestArgs struct {
+	schemaTestEmbedded
+
+	Unit      string             `json:"unit" jsonschema:"enum=celsius|fahrenheit"`
+	Days      int                `json:"days" jsonschema:"minimum=1,maximum=7"`
+	Priority  *int               `json:"priority" jsonschema:"enum=1|2|3"`
+	Tags      []string           `json:"tags" jsonschema:"minItems=1"`
+	Address   *schemaTestAddress `json:"address"`
+	Category  schemaTestCategory `json:"category"`
+	Ignored   string             `json:"-"`
+	unexposed string
+}
+
+type schemaTestTree struct {
+	Value    string            `json:"value"`
+	Children []*schemaTestTree `json:"children,omitempty"`
+}
+
+type schemaTestWithMap struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Any    any               `json:"any"`
+}
+
+func TestToolFunctionParametersFromStruct(t *testing.T) {
+	parameters, err := ToolFunctionParametersFromStruct[schemaTestArgs]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+
+	// round-trip through JSON for easier comparison
+	var schema map[string]any
+	bytes, _ := json.Marshal(parameters)
+	if err := json.Unmarshal(bytes, &schema); err != nil {
+		t.Fatalf("failed to decode generated schema: %v", err)
+	}
+
+	if schema["additionalProperties"] != false {
+		t.Errorf("Expected additionalProperties to be false")
+	}
+
+	properties := schema["properties"].(map[string]any)
+	if len(properties) != 7 {
+		t.Errorf("Expected 7 properties, got %d: %v", len(properties), properties)
+	}
+	if required := schema["required"].([]any); len(required) != 7 {
+		t.Errorf("Expected all 7 properties to be required in strict mode, got %v", required)
+	}
+
+	if createdAt := properties["created_at"].(map[string]any); createdAt["format"] != "date-time" {
+		t.Errorf("Expected date-time format for embedded time field, got %v", createdAt)
+	}
+	if unit := properties["unit"].(map[string]any); !reflect.DeepEqual(unit["enum"], []any{"celsius", "fahrenheit"}) {
+		t.Errorf("Unexpected enum: %v", unit["enum"])
+	}
+	if days := properties["days"].(map[string]any); days["type"] != "integer" || days["minimum"] != float64(1) || days["maximum"] != float64(7) {
+		t.Errorf("Unexpected integer schema: %v", days)
+	}
+	if priority := properties["priority"].(map[string]any); !reflect.DeepEqual(priority["type"], []any{"integer", "null"}) ||
+		!reflect.DeepEqual(priority["enum"], []any{float64(1), float64(2), float64(3), nil}) {
+		t.Errorf("Expected nullable integer enum, got %v", priority)
+	}
+	if tags := properties["tags"].(map[string]any); tags["type"] != "array" || tags["minItems"] != float64(1) {
+		t.Errorf("Unexpected array schema: %v", tags)
+	}
+
+	address := properties["address"].(map[string]any)
+	if !reflect.DeepEqual(address["type"], []any{"object", "null"}) {
+		t.Errorf("Expected nullable object, got %v", address["type"])
+	}
+	city := address["properties"].(map[string]any)["city"].(map[string]any)
+	if city["description"] != "City name, e.g. Seoul" {
+		t.Errorf("Expected escaped comma in description, got '%v'", city["description"])
+	}
+	zipCode := address["properties"].(map[string]any)["zip_code"].(map[string]any)
+	if !reflect.DeepEqual(zipCode["type"], []any{"string", "null"}) || zipCode["pattern"] != "^[0-9]{5}$" {
+		t.Errorf("Unexpected zip code schema: %v", zipCode)
+	}
+
+	// recursive type should be referenced from $defs
+	if category := properties["category"].(map[string]any); category["$ref"] != "#/$defs/schemaTestCategory" {
+		t.Errorf("Expected $ref for recursive type, got %v", category)
+	}
+	defs := schema["$defs"].(map[string]any)
+	children := defs["schemaTestCategory"].(map[string]any)["properties"].(map[string]any)["children"].(map[string]any)
+	if children["items"].(map[string]any)["$ref"] != "#/$defs/schemaTestCategory" {
+		t.Errorf("Expected recursive $ref in items, got %v", children)
+	}
+}
+
+func TestToolFunctionParametersFromStructRecursiveRoot(t *testing.T) {
+	parameters, err := ToolFunctionParametersFromStruct[schemaTestTree]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+
+	children := parameters["properties"].(map[string]any)["children"].(map[string]any)
+	if !reflect.DeepEqual(children["type"], []string{"array", "null"}) {
+		t.Errorf("Expected nullable array, got %v", children["type"])
+	}
+	if ref := children["items"].(map[string]any)["$ref"]; ref != "#" {
+		t.Errorf("Expected root $ref, got %v", ref)
+	}
+	if _, exists := parameters["$defs"]; exists {
+		t.Errorf("Expected no $defs for recursive root")
+	}
+}
+
+func TestToolFunctionParametersFromStructNonStrict(t *testing.T) {
+	if _, err := ToolFunctionParametersFromStruct[schemaTestWithMap](); err == nil {
+		t.Errorf("Expected error for map in strict mode")
+	}
+	if _, err := ToolFunctionParametersFromStruct[string](); err == nil {
+		t.Errorf("Expected error for non-struct type")
+	}
+
+	parameters, err := ToolFunctionParametersFromStructNonStrict[schemaTestWithMap]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+	if required := parameters["required"].([]string); !reflect.DeepEqual(required, []string{"any"}) {
+		t.Errorf("Expected only non-optional property to be required, got %v", required)
+	}
+	labels := parameters["properties"].(map[string]any)["labels"].(map[string]any)
+	if labels["type"] != "object" || labels["additionalProperties"].(map[string]any)["type"] != "string" {
+		t.Errorf("Unexpected map schema: %v", labels)
+	}
+
+	tool, err := NewChatCompletionToolFromStruct[schemaTestAddress]("set_address", "Set an address")
+	if err != nil {
+		t.Fatalf("failed to generate tool: %v", err)
+	}
+	if tool.Function.Strict == nil || !*tool.Function.Strict {
+		t.Errorf("Expected strict tool")
+	}
+}
@@ -0,0 +1,414 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// local validation of JSON values against the subset of JSON schema accepted by OpenAI
+//
+// https://platform.openai.com/docs/guides/structured-outputs#supported-schemas
+
+// SchemaViolation represents a violation of JSON schema at a JSON pointer path.
+type SchemaViolation struct {
+	Path    string // JSON pointer (RFC 6901), empty for the root
+	Message string
+}
+
+// String returns a human-readable description of the violation.
+func (v SchemaViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// SchemaValidationError is returned when a JSON value does not conform to its schema.
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+// Error returns the error message with all violations.
+func (e *SchemaValidationError) Error() string {
+	messages := []string{}
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return fmt.Sprintf("schema validation failed: %s", strings.Join(messages, "; "))
+}
+
+// ValidateJSONSchema validates a decoded JSON `value` against `schema`.
+//
+// Returns *SchemaValidationError if `value` does not conform to `schema`.
+func ValidateJSONSchema(schema map[string]any, value any) error {
+	// normalize schemas built with typed values (eg. map[string]string, []string)
+	var normalized map[string]any
+	if bytes, err := json.Marshal(schema); err != nil {
+		return fmt.Errorf("failed to serialize schema: %s", err)
+	} else if err := json.Unmarshal(bytes, &normalized); err != nil {
+		return fmt.Errorf("failed to normalize schema: %s", err)
+	}
+
+	v := &jsonSchemaValidator{root: normalized}
+	v.validate(normalized, value, "", 0)
+	if len(v.violations) > 0 {
+		sort.SliceStable(v.violations, func(i, j int) bool {
+			return v.violations[i].Path < v.violations[j].Path
+		})
+		return &SchemaValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+// ValidateArguments validates JSON `arguments` against the parameters.
+func (p ToolFunctionParameters) ValidateArguments(arguments string) error {
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(arguments), &value); err != nil {
+		return &SchemaValidationError{Violations: []SchemaViolation{{Message: fmt.Sprintf("invalid JSON: %s", err)}}}
+	}
+	return ValidateJSONSchema(p, value)
+}
+
+// ValidateArguments validates the arguments of a tool call against the matching one in `tools`.
+func (c ToolCall) ValidateArguments(tools []ChatCompletionTool) error {
+	for _, tool := range tools {
+		if tool.Function.Name == c.Function.Name {
+			return tool.Function.Parameters.ValidateArguments(c.Function.Arguments)
+		}
+	}
+	return fmt.Errorf("no such tool: `%s`", c.Function.Name)
+}
+
+// ValidateArguments validates the arguments of a function call output against the matching one in `tools`.
+func (r ResponseOutput) ValidateArguments(tools []ResponseTool) error {
+	if r.Type != "function_call" {
+		return fmt.Errorf("not a function call output")
+	}
+	for _, tool := range tools {
+		if tool.Name == r.Name {
+			return tool.Parameters.ValidateArguments(r.Arguments)
+		}
+	}
+	return fmt.Errorf("no such tool: `%s`", r.Name)
+}
+
+const maxJSONSchemaRefDepth = 64
+
+// validates values and collects violations
+type jsonSchemaValidator struct {
+	root       map[string]any
+	violations []SchemaViolation
+}
+
+// adds a violation
+func (v *jsonSchemaValidator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolves a local `$ref`
+func (v *jsonSchemaValidator) resolve(ref string) (map[string]any, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref: %s", ref)
+	}
+
+	var current any = v.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref: %s", ref)
+		}
+		if current, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref: %s", ref)
+		}
+	}
+	if schema, ok := current.(map[string]any); ok {
+		return schema, nil
+	}
+	return nil, fmt.Errorf("unresolvable $ref: %s", ref)
+}
+
+// validates `value` at `path` against `schema`
+func (v *jsonSchemaValidator) validate(schema map[string]any, value any, path string, depth int) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if depth >= maxJSONSchemaRefDepth {
+			v.fail(path, "too deeply nested $ref")
+			return
+		}
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			v.fail(path, "%s", err)
+			return
+		}
+		v.validate(resolved, value, path, depth+1)
+		return
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		matched := false
+		for _, s := range anyOf {
+			if sub, ok := s.(map[string]any); ok {
+				tmp := &jsonSchemaValidator{root: v.root}
+				tmp.validate(sub, value, path, depth+1)
+				if len(tmp.violations) == 0 {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			v.fail(path, "value does not match any of the schemas in anyOf")
+			return
+		}
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if jsonValueIsType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "expected %s, got %s", strings.Join(types, " or "), jsonValueTypeName(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonValuesEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "value %s is not one of %s", jsonString(value), jsonString(enum))
+		}
+	}
+	if c, exists := schema["const"]; exists && !jsonValuesEqual(c, value) {
+		v.fail(path, "value %s is not %s", jsonString(value), jsonString(c))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(schema, val, path, depth)
+	case []any:
+		v.validateArray(schema, val, path, depth)
+	case string:
+		v.validateString(schema, val, path)
+	case float64:
+		v.validateNumber(schema, val, path)
+	}
+}
+
+// validates an object
+func (v *jsonSchemaValidator) validateObject(schema map[string]any, object map[string]any, path string, depth int) {
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, exists := object[name]; !exists {
+					v.fail(path, "missing required property `%s`", name)
+				}
+			}
+		}
+	}
+
+	for name, value := range object {
+		childPath := path + "/" + escapeJSONPointer(name)
+		if property, exists := properties[name]; exists {
+			if sub, ok := property.(map[string]any); ok {
+				v.validate(sub, value, childPath, depth+1)
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(childPath, "unexpected property `%s`", name)
+			}
+		case map[string]any:
+			v.validate(additional, value, childPath, depth+1)
+		}
+	}
+}
+
+// validates an array
+func (v *jsonSchemaValidator) validateArray(schema map[string]any, array []any, path string, depth int) {
+	if limit, ok := schema["minItems"].(float64); ok && float64(len(array)) < limit {
+		v.fail(path, "expected at least %v items, got %d", limit, len(array))
+	}
+	if limit, ok := schema["maxItems"].(float64); ok && float64(len(array)) > limit {
+		v.fail(path, "expected at most %v items, got %d", limit, len(array))
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range array {
+			v.validate(items, item, fmt.Sprintf("%s/%d", path, i), depth+1)
+		}
+	}
+}
+
+// validates a string
+func (v *jsonSchemaValidator) validateString(schema map[string]any, str string, path string) {
+	length := len([]rune(str))
+	if limit, ok := schema["minLength"].(float64); ok && float64(length) < limit {
+		v.fail(path, "expected at least %v characters, got %d", limit, length)
+	}
+	if limit, ok := schema["maxLength"].(float64); ok && float64(length) > limit {
+		v.fail(path, "expected at most %v characters, got %d", limit, length)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err != nil {
+			v.fail(path, "invalid pattern in schema: %s", pattern)
+		} else if !re.MatchString(str) {
+			v.fail(path, "value %s does not match pattern %s", jsonString(str), pattern)
+		}
+	}
+	if format, ok := schema["format"].(string); ok {
+		if checker, exists := jsonSchemaFormats[format]; exists && !checker(str) {
+			v.fail(path, "value %s is not a valid %s", jsonString(str), format)
+		}
+	}
+}
+
+// validates a number
+func (v *jsonSchemaValidator) validateNumber(schema map[string]any, number float64, path string) {
+	if limit, ok := schema["minimum"].(float64); ok && number < limit {
+		v.fail(path, "value %v is less than minimum %v", number, limit)
+	}
+	if limit, ok := schema["maximum"].(float64); ok && number > limit {
+		v.fail(path, "value %v is greater than maximum %v", number, limit)
+	}
+	if limit, ok := schema["exclusiveMinimum"].(float64); ok && number <= limit {
+		v.fail(path, "value %v is not greater than %v", number, limit)
+	}
+	if limit, ok := schema["exclusiveMaximum"].(float64); ok && number >= limit {
+		v.fail(path, "value %v is not less than %v", number, limit)
+	}
+	if multipleOf, ok := schema["multipleOf"].(float64); ok && multipleOf > 0 {
+		if q := number / multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "value %v is not a multiple of %v", number, multipleOf)
+		}
+	}
+}
+
+// checkers of string formats
+var jsonSchemaFormats = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"time": func(s string) bool {
+		_, err := time.Parse("15:04:05Z07:00", s)
+		if err != nil {
+			_, err = time.Parse("15:04:05", s)
+		}
+		return err == nil
+	},
+	"email": regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`).MatchString,
+	"uuid":  regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`).MatchString,
+}
+
+// returns type names in `type` of a schema
+func schemaTypes(t any) (types []string) {
+	switch typ3 := t.(type) {
+	case string:
+		return []string{typ3}
+	case []any:
+		for _, e := range typ3 {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	return types
+}
+
+// checks if a decoded JSON value is of given schema type
+func jsonValueIsType(value any, typ3 string) bool {
+	switch typ3 {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	}
+	return false
+}
+
+// returns the schema type name of a decoded JSON value
+func jsonValueTypeName(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// compares two decoded JSON values
+func jsonValuesEqual(a, b any) bool {
+	return jsonString(a) == jsonString(b)
+}
+
+// returns a JSON string of a value
+func jsonString(value any) string {
+	if bytes, err := json.Marshal(value); err == nil {
+		return string(bytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// escapes a JSON pointer token
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,129 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateArguments(t *testing.T) {
+	parameters, err := ToolFunctionParametersFromStruct[schemaTestArgs]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+
+	valid := `{
+		"created_at": "2025-01-02T03:04:05Z",
+		"unit": "celsius",
+		"days": 3,
+		"priority": null,
+		"tags": ["a"],
+		"address": {"city": "Seoul", "zip_code": null},
+		"category": {"name": "root", "children": [{"name": "child", "children": []}]}
+	}`
+	if err := parameters.ValidateArguments(valid); err != nil {
+		t.Errorf("Expected valid arguments, got %v", err)
+	}
+
+	invalid := `{
+		"created_at": "yesterday",
+		"unit": "kelvin",
+		"days": 2.5,
+		"priority": 4,
+		"tags": [],
+		"address": {"city": "Seoul", "zip_code": "123", "country": "KR"},
+		"category": {"name": "root", "children": [{"children": []}]}
+	}`
+	err = parameters.ValidateArguments(invalid)
+	var validationErr *SchemaValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected SchemaValidationError, got %v", err)
+	}
+
+	paths := map[string]bool{}
+	for _, violation := range validationErr.Violations {
+		paths[violation.Path] = true
+	}
+	for _, expected := range []string{
+		"/created_at",
+		"/unit",
+		"/days",
+		"/priority",
+		"/tags",
+		"/address/zip_code",
+		"/address/country",
+		"/category/children/0",
+	} {
+		if !paths[expected] {
+			t.Errorf("Expected violation at '%s', got %v", expected, validationErr.Violations)
+		}
+	}
+
+	// hand-built parameters with typed values
+	handBuilt := NewToolFunctionParameters().
+		AddPropertyWithDescription("location", "string", "City name").
+		AddPropertyWithEnums("unit", "string", "Unit", []string{"celsius", "fahrenheit"}).
+		SetRequiredParameters([]string{"location"})
+	if err := handBuilt.ValidateArguments(`{"unit":"celsius"}`); err == nil || !strings.Contains(err.Error(), "missing required property `location`") {
+		t.Errorf("Expected missing property error, got %v", err)
+	}
+	if err := handBuilt.ValidateArguments(`{"location":`); err == nil {
+		t.Errorf("Expected error for invalid JSON")
+	}
+
+	call := ToolCall{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "unknown", Arguments: "{}"}}
+	if err := call.ValidateArguments([]ChatCompletionTool{NewChatCompletionTool("get_weather", "", handBuilt)}); err == nil {
+		t.Errorf("Expected error for unknown tool")
+	}
+}
+
+func TestToolRunnerValidation(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","tool_calls":[
+				{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Seoul\"}"}}
+			]},"finish_reason":"tool_calls"}]}`))
+		default:
+			var requestBody struct {
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			output := requestBody.Messages[len(requestBody.Messages)-1].Content
+			if !strings.Contains(output, "invalid arguments") || !strings.Contains(output, "/city") {
+				t.Errorf("Expected corrective error, got: %s", output)
+			}
+
+			w.Write([]byte(`{"id":"chatcmpl-2","choices":[{"index":0,"message":{"role":"assistant","content":"Sorry."},"finish_reason":"stop"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	tool, _ := NewChatCompletionToolFromStruct[struct {
+		Location string `json:"location"`
+	}]("get_weather", "Get current weather of a location")
+
+	runner := NewToolRunner(client, "gpt-4o").
+		Register(tool, func(ctx context.Context, call ToolCall) (string, error) {
+			t.Errorf("Expected handler not to be called with invalid arguments")
+			return "", nil
+		})
+	if _, err := runner.Run(context.Background(), []ChatMessage{NewChatUserMessage("Weather?")}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+}
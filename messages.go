@@ -13,15 +13,38 @@ import (
 type Message struct {
 	CommonResponse
 
-	ID          string            `json:"id"`
-	CreatedAt   int               `json:"created_at"`
-	ThreadID    string            `json:"thread_id"`
-	Role        string            `json:"role"` // 'user' | 'assistant'
-	Content     []MessageContent  `json:"content"`
-	AssistantID *string           `json:"assistant_id,omitempty"`
-	RunID       *string           `json:"run_id,omitempty"`
-	FileIDs     []string          `json:"file_ids"`
-	Metadata    map[string]string `json:"metadata"`
+	ID          string              `json:"id"`
+	CreatedAt   int                 `json:"created_at"`
+	ThreadID    string              `json:"thread_id"`
+	Status      *string             `json:"status,omitempty"` // 'in_progress' | 'incomplete' | 'completed'
+	Role        string              `json:"role"`             // 'user' | 'assistant'
+	Content     []MessageContent    `json:"content"`
+	AssistantID *string             `json:"assistant_id,omitempty"`
+	RunID       *string             `json:"run_id,omitempty"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Metadata    map[string]string   `json:"metadata"`
+
+	// Deprecated: `file_ids` was removed in v2, use `Attachments` instead.
+	FileIDs []string `json:"file_ids,omitempty"`
+}
+
+// MessageAttachment struct for files attached to a message, and the tools they should be added to
+//
+// https://platform.openai.com/docs/api-reference/messages/object#messages/object-attachments
+type MessageAttachment struct {
+	FileID string `json:"file_id"`
+	Tools  []Tool `json:"tools"` // 'code_interpreter' | 'file_search'
+}
+
+// NewMessageAttachment returns a new MessageAttachment with given `fileID` and `tools`.
+func NewMessageAttachment(fileID string, tools ...Tool) MessageAttachment {
+	if tools == nil {
+		tools = []Tool{}
+	}
+	return MessageAttachment{
+		FileID: fileID,
+		Tools:  tools,
+	}
 }
 
 // MessageContentType type for constants
@@ -30,7 +53,9 @@ type MessageContentType string
 // MessageContentType constants
 const (
 	MessageContentTypeImageFile MessageContentType = "image_file"
+	MessageContentTypeImageURL  MessageContentType = "image_url"
 	MessageContentTypeText      MessageContentType = "text"
+	MessageContentTypeRefusal   MessageContentType = "refusal"
 )
 
 // MessageContent struct for Message
@@ -38,12 +63,70 @@ type MessageContent struct {
 	Type MessageContentType `json:"type"`
 
 	ImageFile *MessageContentImageFile `json:"image_file,omitempty"` // Type == 'image_file'
+	ImageURL  *MessageContentImageURL  `json:"image_url,omitempty"`  // Type == 'image_url'
 	Text      *MessageContentText      `json:"text,omitempty"`       // Type == 'text'
+	Refusal   *string                  `json:"refusal,omitempty"`    // Type == 'refusal'
 }
 
 // MessageContentImageFile struct for MessageContent
 type MessageContentImageFile struct {
-	FileID string `json:"file_id"`
+	FileID string  `json:"file_id"`
+	Detail *string `json:"detail,omitempty"` // 'auto' | 'low' | 'high'
+}
+
+// MessageContentImageURL struct for MessageContent
+type MessageContentImageURL struct {
+	URL    string  `json:"url"`
+	Detail *string `json:"detail,omitempty"` // 'auto' | 'low' | 'high'
+}
+
+// MessageContentPart struct for the content of a message to be created
+//
+// https://platform.openai.com/docs/api-reference/messages/createMessage#messages-createmessage-content
+type MessageContentPart struct {
+	Type MessageContentType `json:"type"`
+
+	ImageFile *MessageContentImageFile `json:"image_file,omitempty"` // Type == 'image_file'
+	ImageURL  *MessageContentImageURL  `json:"image_url,omitempty"`  // Type == 'image_url'
+	Text      *string                  `json:"text,omitempty"`       // Type == 'text'
+}
+
+// NewMessageContentPartWithText returns a MessageContentPart with given `text`.
+func NewMessageContentPartWithText(text string) MessageContentPart {
+	return MessageContentPart{
+		Type: MessageContentTypeText,
+		Text: &text,
+	}
+}
+
+// NewMessageContentPartWithImageFile returns a MessageContentPart with given image `fileID`.
+//
+// `detail` can be one of 'auto', 'low', or 'high', and is omitted if empty.
+func NewMessageContentPartWithImageFile(fileID, detail string) MessageContentPart {
+	image := MessageContentImageFile{FileID: fileID}
+	if detail != "" {
+		image.Detail = &detail
+	}
+
+	return MessageContentPart{
+		Type:      MessageContentTypeImageFile,
+		ImageFile: &image,
+	}
+}
+
+// NewMessageContentPartWithImageURL returns a MessageContentPart with given image `url`.
+//
+// `detail` can be one of 'auto', 'low', or 'high', and is omitted if empty.
+func NewMessageContentPartWithImageURL(url, detail string) MessageContentPart {
+	image := MessageContentImageURL{URL: url}
+	if detail != "" {
+		image.Detail = &detail
+	}
+
+	return MessageContentPart{
+		Type:     MessageContentTypeImageURL,
+		ImageURL: &image,
+	}
 }
 
 // MessageContentText struct for MessageContent
@@ -87,8 +170,28 @@ type MessageContentTextAnnotationFilePath struct {
 // CreateMessageOptions for creating message
 type CreateMessageOptions map[string]any
 
+// SetContentParts sets the `content` parameter of CreateMessageOptions with multiple parts (text and images).
+//
+// It is used instead of the `content` string of CreateMessage when the string is empty.
+//
+// https://platform.openai.com/docs/api-reference/messages/createMessage#messages-createmessage-content
+func (o CreateMessageOptions) SetContentParts(parts []MessageContentPart) CreateMessageOptions {
+	o["content"] = parts
+	return o
+}
+
+// SetAttachments sets the `attachments` parameter of CreateMessageOptions.
+//
+// https://platform.openai.com/docs/api-reference/messages/createMessage#messages-createmessage-attachments
+func (o CreateMessageOptions) SetAttachments(attachments []MessageAttachment) CreateMessageOptions {
+	o["attachments"] = attachments
+	return o
+}
+
 // SetFileIDs sets the `file_ids` parameter of CreateMessageOptions.
 //
+// Deprecated: `file_ids` was removed in v2, use `SetAttachments` instead.
+//
 // https://platform.openai.com/docs/api-reference/messages/createMessage#messages-createmessage-file_ids
 func (o CreateMessageOptions) SetFileIDs(fileIDs []string) CreateMessageOptions {
 	o["file_ids"] = fileIDs
@@ -105,13 +208,17 @@ func (o CreateMessageOptions) SetMetadata(metadata map[string]string) CreateMess
 
 // CreateMessage creates a message with given `threadID`, `role`, `content`, and `options`.
 //
+// If `content` is empty, the content parts set with `CreateMessageOptions.SetContentParts` are used.
+//
 // https://platform.openai.com/docs/api-reference/messages/createMessage
 func (c *Client) CreateMessage(threadID, role, content string, options CreateMessageOptions) (response Message, err error) {
 	if options == nil {
 		options = CreateMessageOptions{}
 	}
 	options["role"] = role
-	options["content"] = content
+	if _, exists := options["content"]; !exists || content != "" {
+		options["content"] = content
+	}
 
 	var bytes []byte
 	if bytes, err = c.post(fmt.Sprintf("threads/%s/messages", threadID), options); err == nil {
@@ -206,6 +313,14 @@ type Messages struct {
 // ListMessagesOptions for listing messages
 type ListMessagesOptions map[string]any
 
+// SetRunID sets the `run_id` parameter of messages' listing request.
+//
+// https://platform.openai.com/docs/api-reference/messages/listMessages#messages-listmessages-run_id
+func (o ListMessagesOptions) SetRunID(runID string) ListMessagesOptions {
+	o["run_id"] = runID
+	return o
+}
+
 // SetLimit sets the `limit` parameter of messages' listing request.
 //
 // https://platform.openai.com/docs/api-reference/messages/listMessages#messages-listmessages-limit
@@ -267,6 +382,10 @@ func (c *Client) ListMessages(threadID string, options ListMessagesOptions) (res
 	return Messages{}, err
 }
 
+// MessageFile struct for attached files of messages
+//
+// Deprecated: message files were removed in v2, use `Message.Attachments` instead.
+//
 // https://platform.openai.com/docs/api-reference/messages/file-object
 type MessageFile struct {
 	CommonResponse
@@ -278,6 +397,8 @@ type MessageFile struct {
 
 // RetrieveMessageFile retrieves a message file with given `threadID`, `messageID`, and `fileID`.
 //
+// Deprecated: message files were removed in v2, use `Message.Attachments` instead.
+//
 // https://platform.openai.com/docs/api-reference/messages/getMessageFile
 func (c *Client) RetrieveMessageFile(threadID, messageID, fileID string) (response MessageFile, err error) {
 	var bytes []byte
@@ -348,6 +469,8 @@ func (o ListMessageFilesOptions) SetBefore(before string) ListMessageFilesOption
 
 // ListMessageFiles fetches message files with given `threadID`, `mesageID`, and `options`.
 //
+// Deprecated: message files were removed in v2, use `Message.Attachments` instead.
+//
 // https://platform.openai.com/docs/api-reference/messages/listMessageFiles
 func (c *Client) ListMessageFiles(threadID, messageID string, options ListMessageFilesOptions) (response MessageFiles, err error) {
 	if options == nil {
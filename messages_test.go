@@ -17,8 +17,6 @@ func TestMessages(t *testing.T) {
 		t.Errorf("environment variables `OPENAI_API_KEY` and `OPENAI_ORGANIZATION` are needed")
 	}
 
-	client.SetBetaHeader(`assistants=v1`)
-
 	if thread, err := client.CreateThread(nil); err != nil {
 		t.Errorf("failed to create a thread for testing messages: %s", err)
 	} else {
@@ -32,8 +30,8 @@ func TestMessages(t *testing.T) {
 			} else {
 				// === CreateMessage ===
 				if created, err := client.CreateMessage(threadID, "user", "What is the weather like in Seoul, Korea?", CreateMessageOptions{}.
-					SetFileIDs([]string{
-						uploaded.ID,
+					SetAttachments([]MessageAttachment{
+						NewMessageAttachment(uploaded.ID, NewCodeInterpreterTool()),
 					})); err != nil {
 					t.Errorf("failed to create thread message: %s", err)
 				} else {
@@ -64,16 +62,8 @@ func TestMessages(t *testing.T) {
 								t.Errorf("modified message id: %s does not match the requsted one: %s", modified.ID, messageID)
 							}
 
-							// === ListMessageFiles ===
-							if files, err := client.ListMessageFiles(threadID, messageID, nil); err != nil {
-								t.Errorf("failed to list message files: %s", err)
-							} else {
-								file := files.Data[0]
-
-								// === RetrieveMessageFile ===
-								if _, err := client.RetrieveMessageFile(threadID, messageID, file.ID); err != nil {
-									t.Errorf("failed to retrieve message file: %s", err)
-								}
+							if len(modified.Attachments) != 1 || modified.Attachments[0].FileID != uploaded.ID {
+								t.Errorf("attachments of modified message differ from expectation: %+v", modified.Attachments)
 							}
 						}
 					}
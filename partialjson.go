@@ -0,0 +1,517 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// tolerant parser of incomplete JSON texts, for rendering streamed arguments and structured outputs
+
+// PartialJSONResult struct for the result of parsing a (possibly incomplete) JSON text
+type PartialJSONResult struct {
+	// best-effort value: map[string]any, []any, string, float64, bool, or nil
+	//
+	// Incomplete strings and numbers are included as parsed so far, and incomplete literals are omitted.
+	Value any
+
+	// JSON pointer paths of values which are parsed completely, in the order of completion
+	CompletedPaths []string
+
+	// whether the whole JSON text is complete
+	Complete bool
+}
+
+// Map returns the value as a map, or nil if it is not an object.
+func (r PartialJSONResult) Map() map[string]any {
+	if m, ok := r.Value.(map[string]any); ok {
+		return m
+	}
+	return nil
+}
+
+// IsCompleted checks if the value at given JSON pointer `path` is parsed completely.
+func (r PartialJSONResult) IsCompleted(path string) bool {
+	for _, p := range r.CompletedPaths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletedFields returns the names of top-level object fields which are parsed completely.
+func (r PartialJSONResult) CompletedFields() (fields []string) {
+	for _, p := range r.CompletedPaths {
+		if strings.Count(p, "/") == 1 {
+			fields = append(fields, unescapeJSONPointer(p[1:]))
+		}
+	}
+	return fields
+}
+
+// Into decodes the best-effort value into `out`, leaving missing fields untouched.
+func (r PartialJSONResult) Into(out any) error {
+	bytes, err := json.Marshal(r.Value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, out)
+}
+
+// ParsePartialJSON parses a (possibly incomplete) JSON text.
+//
+// Returns an error only when `text` can never be a valid JSON text.
+func ParsePartialJSON(text string) (result PartialJSONResult, err error) {
+	return NewPartialJSONParser().Append(text)
+}
+
+// ParsePartialJSONInto parses a (possibly incomplete) JSON text into `T` as much as possible.
+func ParsePartialJSONInto[T any](text string) (parsed T, result PartialJSONResult, err error) {
+	if result, err = ParsePartialJSON(text); err != nil {
+		return parsed, result, err
+	}
+	err = result.Into(&parsed)
+	return parsed, result, err
+}
+
+// ArgumentsPartial parses the (possibly incomplete) arguments of a streamed tool call.
+func (c ToolCall) ArgumentsPartial() (PartialJSONResult, error) {
+	return ParsePartialJSON(c.Function.Arguments)
+}
+
+// PartialJSONParser accumulates streamed JSON fragments (eg. tool call arguments or structured outputs)
+// and parses them incrementally, so each delta is parsed only once.
+type PartialJSONParser struct {
+	buffer strings.Builder
+	last   PartialJSONResult
+	err    error // once the text turns out to be invalid, it stays invalid
+
+	offset    int                 // offset of the next byte
+	stack     []*partialJSONFrame // objects and arrays which are not closed yet
+	scalar    *partialJSONScalar  // string, number, or literal which is being parsed
+	root      any                 // root value, when it is complete
+	done      bool                // whether the root value is complete
+	completed []string            // paths of complete values
+}
+
+// NewPartialJSONParser returns a new PartialJSONParser.
+func NewPartialJSONParser() *PartialJSONParser {
+	return &PartialJSONParser{}
+}
+
+// Append appends `delta` to the accumulated text, and returns the parsed result of it.
+//
+// On error, the last successful result is kept.
+func (p *PartialJSONParser) Append(delta string) (result PartialJSONResult, err error) {
+	p.buffer.WriteString(delta)
+	if p.err != nil {
+		return p.last, p.err
+	}
+
+	for i := 0; i < len(delta); i++ {
+		if err = p.consume(delta[i]); err != nil {
+			p.err = err
+			return p.last, err
+		}
+		p.offset++
+	}
+	p.last = p.snapshot()
+
+	return p.last, nil
+}
+
+// Result returns the last parsed result.
+func (p *PartialJSONParser) Result() PartialJSONResult {
+	return p.last
+}
+
+// Text returns the accumulated text.
+func (p *PartialJSONParser) Text() string {
+	return p.buffer.String()
+}
+
+// Reset clears the accumulated text and result.
+func (p *PartialJSONParser) Reset() {
+	*p = PartialJSONParser{}
+}
+
+// state of an object or an array which is not closed yet
+type partialJSONFrame struct {
+	path   string
+	object map[string]any // nil for arrays
+	array  []any
+	key    string // key of the current value of an object
+	state  partialJSONState
+}
+
+// partialJSONState type for what is expected next in an object or an array
+type partialJSONState int
+
+const (
+	partialJSONExpectFirst partialJSONState = iota // after '{' or '['
+	partialJSONExpectKey                           // after ',' in an object
+	partialJSONExpectColon                         // after a key
+	partialJSONExpectValue                         // after ':', or ',' in an array
+	partialJSONExpectNext                          // after a value
+)
+
+// string, number, or literal which is being parsed
+type partialJSONScalar struct {
+	kind      byte   // '"' for strings, '0' for numbers, and 't' for literals
+	key       bool   // whether it is a key of an object
+	bytes     []byte // decoded bytes of a string, or bytes of a number or a literal
+	escape    []byte // escape sequence of a string which is not complete yet
+	surrogate rune   // high surrogate of a string which waits for its low surrogate
+}
+
+// escaped characters of JSON strings, except `\uXXXX`
+var partialJSONEscapes = map[byte]byte{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+// literals of JSON
+var partialJSONLiterals = []struct {
+	text  string
+	value any
+}{
+	{"true", true},
+	{"false", false},
+	{"null", nil},
+}
+
+func (p *PartialJSONParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid JSON at offset %d: %s", p.offset, fmt.Sprintf(format, args...))
+}
+
+// returns the innermost object or array, or nil if there is none
+func (p *PartialJSONParser) top() *partialJSONFrame {
+	if len(p.stack) <= 0 {
+		return nil
+	}
+	return p.stack[len(p.stack)-1]
+}
+
+// returns the JSON pointer path of the value which comes next
+func (p *PartialJSONParser) valuePath() string {
+	frame := p.top()
+	if frame == nil {
+		return ""
+	}
+	if frame.object != nil {
+		return frame.path + "/" + escapeJSONPointer(frame.key)
+	}
+	return fmt.Sprintf("%s/%d", frame.path, len(frame.array))
+}
+
+// consumes a byte of the text
+func (p *PartialJSONParser) consume(ch byte) error {
+	if p.scalar != nil {
+		consumed, err := p.consumeScalar(ch)
+		if err != nil || consumed {
+			return err
+		}
+		// a number was terminated by `ch`, so consume it again below
+	}
+
+	switch ch {
+	case ' ', '\t', '\n', '\r':
+		return nil
+	}
+
+	frame := p.top()
+	if frame == nil {
+		if p.done {
+			return p.errorf("unexpected trailing character")
+		}
+		return p.startValue(ch)
+	}
+
+	closer := byte(']')
+	if frame.object != nil {
+		closer = '}'
+	}
+
+	switch frame.state {
+	case partialJSONExpectFirst:
+		if ch == closer {
+			return p.closeFrame()
+		}
+		if frame.object == nil {
+			return p.startValue(ch)
+		}
+		fallthrough
+	case partialJSONExpectKey:
+		if ch != '"' {
+			return p.errorf("expected a string key")
+		}
+		p.scalar = &partialJSONScalar{kind: '"', key: true}
+	case partialJSONExpectColon:
+		if ch != ':' {
+			return p.errorf("expected ':'")
+		}
+		frame.state = partialJSONExpectValue
+	case partialJSONExpectValue:
+		return p.startValue(ch)
+	case partialJSONExpectNext:
+		switch ch {
+		case ',':
+			if frame.object != nil {
+				frame.state = partialJSONExpectKey
+			} else {
+				frame.state = partialJSONExpectValue
+			}
+		case closer:
+			return p.closeFrame()
+		default:
+			return p.errorf("expected ',' or '%c'", closer)
+		}
+	}
+
+	return nil
+}
+
+// starts a value with its first byte
+func (p *PartialJSONParser) startValue(ch byte) error {
+	switch {
+	case ch == '{':
+		p.stack = append(p.stack, &partialJSONFrame{path: p.valuePath(), object: map[string]any{}})
+	case ch == '[':
+		p.stack = append(p.stack, &partialJSONFrame{path: p.valuePath(), array: []any{}})
+	case ch == '"':
+		p.scalar = &partialJSONScalar{kind: '"'}
+	case ch == '-' || (ch >= '0' && ch <= '9'):
+		p.scalar = &partialJSONScalar{kind: '0', bytes: []byte{ch}}
+	case ch == 't' || ch == 'f' || ch == 'n':
+		p.scalar = &partialJSONScalar{kind: 't', bytes: []byte{ch}}
+	default:
+		return p.errorf("unexpected character '%c'", ch)
+	}
+	return nil
+}
+
+// closes the innermost object or array
+func (p *PartialJSONParser) closeFrame() error {
+	frame := p.top()
+	p.stack = p.stack[:len(p.stack)-1]
+
+	if frame.object != nil {
+		p.completeValue(frame.object)
+	} else {
+		p.completeValue(frame.array)
+	}
+	return nil
+}
+
+// puts a complete value into its parent
+func (p *PartialJSONParser) completeValue(value any) {
+	p.completed = append(p.completed, p.valuePath())
+
+	frame := p.top()
+	if frame == nil {
+		p.root, p.done = value, true
+		return
+	}
+	if frame.object != nil {
+		frame.object[frame.key] = value
+	} else {
+		frame.array = append(frame.array, value)
+	}
+	frame.state = partialJSONExpectNext
+}
+
+// consumes a byte of the current scalar, returns `consumed` == false if the scalar was terminated before `ch`
+func (p *PartialJSONParser) consumeScalar(ch byte) (consumed bool, err error) {
+	s := p.scalar
+
+	switch s.kind {
+	case '"':
+		return true, p.consumeString(ch)
+	case '0':
+		if strings.IndexByte("+-0123456789.eE", ch) >= 0 {
+			s.bytes = append(s.bytes, ch)
+			return true, nil
+		}
+		if !json.Valid(s.bytes) {
+			return false, p.errorf("invalid number '%s'", s.bytes)
+		}
+		p.scalar = nil
+		f, _ := strconv.ParseFloat(string(s.bytes), 64)
+		p.completeValue(f)
+		return false, nil
+	default:
+		s.bytes = append(s.bytes, ch)
+		for _, literal := range partialJSONLiterals {
+			if string(s.bytes) == literal.text {
+				p.scalar = nil
+				p.completeValue(literal.value)
+				return true, nil
+			}
+			if strings.HasPrefix(literal.text, string(s.bytes)) {
+				return true, nil
+			}
+		}
+		return false, p.errorf("invalid literal")
+	}
+}
+
+// consumes a byte of the current string
+func (p *PartialJSONParser) consumeString(ch byte) error {
+	s := p.scalar
+
+	if len(s.escape) > 0 {
+		s.escape = append(s.escape, ch)
+		return p.consumeEscape()
+	}
+	if s.surrogate != 0 && ch != '\\' {
+		// high surrogate without a low surrogate
+		s.bytes = utf8.AppendRune(s.bytes, utf8.RuneError)
+		s.surrogate = 0
+	}
+
+	switch {
+	case ch == '"':
+		p.scalar = nil
+		str := decodePartialJSONString(s.bytes, false)
+		if s.key {
+			frame := p.top()
+			frame.key = str
+			frame.state = partialJSONExpectColon
+		} else {
+			p.completeValue(str)
+		}
+	case ch == '\\':
+		s.escape = []byte{ch}
+	case ch < 0x20:
+		return p.errorf("control character in string")
+	default:
+		s.bytes = append(s.bytes, ch)
+	}
+	return nil
+}
+
+// consumes the escape sequence of the current string, decoding it when it is complete
+func (p *PartialJSONParser) consumeEscape() error {
+	s := p.scalar
+
+	if len(s.escape) == 2 {
+		escaped := s.escape[1]
+		if escaped == 'u' {
+			return nil
+		}
+		if s.surrogate != 0 {
+			// high surrogate without a low surrogate
+			s.bytes = utf8.AppendRune(s.bytes, utf8.RuneError)
+			s.surrogate = 0
+		}
+		decoded, ok := partialJSONEscapes[escaped]
+		if !ok {
+			return p.errorf("invalid escape character '%c'", escaped)
+		}
+		s.bytes = append(s.bytes, decoded)
+		s.escape = nil
+		return nil
+	}
+
+	// `\uXXXX`
+	if strings.IndexByte("0123456789abcdefABCDEF", s.escape[len(s.escape)-1]) < 0 {
+		return p.errorf("invalid unicode escape")
+	}
+	if len(s.escape) < 6 {
+		return nil
+	}
+	n, _ := strconv.ParseUint(string(s.escape[2:]), 16, 16)
+	s.escape = nil
+
+	r := rune(n)
+	if s.surrogate != 0 {
+		s.bytes = utf8.AppendRune(s.bytes, utf16.DecodeRune(s.surrogate, r))
+		s.surrogate = 0
+	} else if utf16.IsSurrogate(r) {
+		s.surrogate = r
+	} else {
+		s.bytes = utf8.AppendRune(s.bytes, r)
+	}
+	return nil
+}
+
+// returns the best-effort value parsed so far
+//
+// Complete values are shared between results, as they are not modified anymore.
+func (p *PartialJSONParser) snapshot() (result PartialJSONResult) {
+	result.CompletedPaths = p.completed[:len(p.completed):len(p.completed)]
+	result.Complete = p.done
+	if p.done {
+		result.Value = p.root
+		return result
+	}
+
+	var value any
+	if s := p.scalar; s != nil && !s.key {
+		switch s.kind {
+		case '"':
+			value = decodePartialJSONString(s.bytes, true)
+		case '0':
+			if json.Valid(s.bytes) {
+				value, _ = strconv.ParseFloat(string(s.bytes), 64)
+			}
+		}
+	}
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		frame := p.stack[i]
+		if frame.object != nil {
+			object := make(map[string]any, len(frame.object)+1)
+			for k, v := range frame.object {
+				object[k] = v
+			}
+			if value != nil {
+				object[frame.key] = value
+			}
+			value = object
+		} else {
+			array := make([]any, len(frame.array), len(frame.array)+1)
+			copy(array, frame.array)
+			if value != nil {
+				array = append(array, value)
+			}
+			value = array
+		}
+	}
+	result.Value = value
+
+	return result
+}
+
+// decodes bytes of a JSON string, replacing invalid UTF-8 bytes,
+// and dropping a truncated multi-byte character at the end if `partial`
+func decodePartialJSONString(bs []byte, partial bool) string {
+	if partial {
+		for i := len(bs) - 1; i >= 0 && i >= len(bs)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(bs[i]) {
+				if !utf8.FullRune(bs[i:]) {
+					bs = bs[:i]
+				}
+				break
+			}
+		}
+	}
+	if utf8.Valid(bs) {
+		return string(bs)
+	}
+	return string([]rune(string(bs)))
+}
+
+// unescapes a JSON pointer token
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
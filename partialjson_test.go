@@ -0,0 +1,134 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParsePartialJSON(t *testing.T) {
+	for _, test := range []struct {
+		text      string
+		expected  any
+		completed []string
+		complete  bool
+	}{
+		{``, nil, nil, false},
+		{`{`, map[string]any{}, nil, false},
+		{`{"loc`, map[string]any{}, nil, false},
+		{`{"location":`, map[string]any{}, nil, false},
+		{`{"location":"Se`, map[string]any{"location": "Se"}, nil, false},
+		{`{"location":"Seoul","days":1`, map[string]any{"location": "Seoul", "days": float64(1)}, []string{"/location"}, false},
+		{`{"location":"Seoul","days":12,"ok":tr`, map[string]any{"location": "Seoul", "days": float64(12)}, []string{"/location", "/days"}, false},
+		{`{"tags":["a","b`, map[string]any{"tags": []any{"a", "b"}}, []string{"/tags/0"}, false},
+		{`{"text":"line\nbreak é\u`, map[string]any{"text": "line\nbreak é"}, nil, false},
+		{`{"emoji":"😀","x":null}`, map[string]any{"emoji": "😀", "x": nil}, []string{"/emoji", "/x", ""}, true},
+		{`{"a":{"b":[1,{"c":false}]}}`, map[string]any{"a": map[string]any{"b": []any{float64(1), map[string]any{"c": false}}}},
+			[]string{"/a/b/0", "/a/b/1/c", "/a/b/1", "/a/b", "/a", ""}, true},
+	} {
+		result, err := ParsePartialJSON(test.text)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", test.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(result.Value, test.expected) {
+			t.Errorf("Expected %#v for '%s', got %#v", test.expected, test.text, result.Value)
+		}
+		if !reflect.DeepEqual(result.CompletedPaths, test.completed) {
+			t.Errorf("Expected completed paths %v for '%s', got %v", test.completed, test.text, result.CompletedPaths)
+		}
+		if result.Complete != test.complete {
+			t.Errorf("Expected complete: %v for '%s'", test.complete, test.text)
+		}
+	}
+
+	for _, invalid := range []string{`{"a" 1`, `{"a":1 "b"`, `[1,,`, `{"a":x`, `{"a":1}}`, `{"a":"\x"}`} {
+		if _, err := ParsePartialJSON(invalid); err == nil {
+			t.Errorf("Expected error for '%s'", invalid)
+		}
+	}
+}
+
+func TestPartialJSONParser(t *testing.T) {
+	type args struct {
+		Location string   `json:"location"`
+		Days     int      `json:"days"`
+		Tags     []string `json:"tags"`
+	}
+
+	parser := NewPartialJSONParser()
+	var parsed args
+	for _, delta := range []string{`{"locat`, `ion": "Seo`, `ul", "da`, `ys": 3, "tags": ["sun`, `ny"]`, `}`} {
+		result, err := parser.Append(delta)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := result.Into(&parsed); err != nil {
+			t.Fatalf("Failed to decode partial result: %v", err)
+		}
+
+		switch delta {
+		case `ion": "Seo`:
+			if parsed.Location != "Seo" || result.IsCompleted("/location") {
+				t.Errorf("Expected incomplete location 'Seo', got '%s'", parsed.Location)
+			}
+		case `ul", "da`:
+			if fields := result.CompletedFields(); !reflect.DeepEqual(fields, []string{"location"}) {
+				t.Errorf("Expected completed fields [location], got %v", fields)
+			}
+		case `}`:
+			if !result.Complete {
+				t.Errorf("Expected complete result")
+			}
+		}
+	}
+	if parsed.Location != "Seoul" || parsed.Days != 3 || !reflect.DeepEqual(parsed.Tags, []string{"sunny"}) {
+		t.Errorf("Unexpected parsed result: %+v", parsed)
+	}
+
+	// error keeps the last result
+	if _, err := parser.Append(`,`); err == nil {
+		t.Errorf("Expected error for trailing character")
+	}
+	if !parser.Result().Complete {
+		t.Errorf("Expected the last result to be kept")
+	}
+
+	// byte by byte, with escapes and multi-byte characters split
+	for _, text := range []string{
+		`{"a":[1,-2.5e3,{"b":true,"c":null}],"d":"x\"\u00e9\ud83d\ude00 😀","e":{}}`,
+		`[["nested",[]],false,"\/\b\f\n\r\t",0]`,
+		`"top-level string"`,
+	} {
+		var expected any
+		if err := json.Unmarshal([]byte(text), &expected); err != nil {
+			t.Fatalf("Invalid test text '%s': %v", text, err)
+		}
+
+		parser := NewPartialJSONParser()
+		var previous []PartialJSONResult
+		var serialized []string
+		for i := 0; i < len(text); i++ {
+			result, err := parser.Append(text[i : i+1])
+			if err != nil {
+				t.Fatalf("Unexpected error at %d of '%s': %v", i, text, err)
+			}
+			bytes, _ := json.Marshal(result.Value)
+			previous = append(previous, result)
+			serialized = append(serialized, string(bytes))
+		}
+		if result := parser.Result(); !result.Complete || !reflect.DeepEqual(result.Value, expected) {
+			t.Errorf("Expected %#v for '%s', got %#v", expected, text, result.Value)
+		}
+		for i, result := range previous {
+			if bytes, _ := json.Marshal(result.Value); string(bytes) != serialized[i] {
+				t.Errorf("Expected result %d not to be modified by later deltas: %s => %s", i, serialized[i], bytes)
+			}
+		}
+	}
+
+	typed, _, err := ParsePartialJSONInto[args](`{"location":"Tokyo","days":`)
+	if err != nil || typed.Location != "Tokyo" || typed.Days != 0 {
+		t.Errorf("Unexpected typed result: %+v, %v", typed, err)
+	}
+}
@@ -0,0 +1,885 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NOTE: In Beta
+
+// https://platform.openai.com/docs/api-reference/realtime
+
+const (
+	realtimeEndpoint   = "realtime"
+	realtimeBetaHeader = "realtime=v1"
+
+	defaultRealtimeMaxReconnects       = 3
+	defaultRealtimeReconnectInterval   = time.Second
+	defaultRealtimeMaxReconnectWait    = 30 * time.Second
+	realtimeItemTypeMessage            = "message"
+	realtimeItemTypeFunctionCallOutput = "function_call_output"
+)
+
+// RealtimeClientEventType type for client events of realtime sessions
+type RealtimeClientEventType string
+
+// RealtimeClientEventType constants
+const (
+	RealtimeClientEventSessionUpdate            RealtimeClientEventType = "session.update"
+	RealtimeClientEventInputAudioBufferAppend   RealtimeClientEventType = "input_audio_buffer.append"
+	RealtimeClientEventInputAudioBufferCommit   RealtimeClientEventType = "input_audio_buffer.commit"
+	RealtimeClientEventInputAudioBufferClear    RealtimeClientEventType = "input_audio_buffer.clear"
+	RealtimeClientEventConversationItemCreate   RealtimeClientEventType = "conversation.item.create"
+	RealtimeClientEventConversationItemDelete   RealtimeClientEventType = "conversation.item.delete"
+	RealtimeClientEventConversationItemTruncate RealtimeClientEventType = "conversation.item.truncate"
+	RealtimeClientEventResponseCreate           RealtimeClientEventType = "response.create"
+	RealtimeClientEventResponseCancel           RealtimeClientEventType = "response.cancel"
+)
+
+// RealtimeServerEventType type for server events of realtime sessions
+type RealtimeServerEventType string
+
+// RealtimeServerEventType constants
+const (
+	RealtimeServerEventError                              RealtimeServerEventType = "error"
+	RealtimeServerEventSessionCreated                     RealtimeServerEventType = "session.created"
+	RealtimeServerEventSessionUpdated                     RealtimeServerEventType = "session.updated"
+	RealtimeServerEventConversationCreated                RealtimeServerEventType = "conversation.created"
+	RealtimeServerEventConversationItemCreated            RealtimeServerEventType = "conversation.item.created"
+	RealtimeServerEventConversationItemDeleted            RealtimeServerEventType = "conversation.item.deleted"
+	RealtimeServerEventConversationItemTruncated          RealtimeServerEventType = "conversation.item.truncated"
+	RealtimeServerEventInputAudioTranscriptionCompleted   RealtimeServerEventType = "conversation.item.input_audio_transcription.completed"
+	RealtimeServerEventInputAudioTranscriptionFailed      RealtimeServerEventType = "conversation.item.input_audio_transcription.failed"
+	RealtimeServerEventInputAudioBufferCommitted          RealtimeServerEventType = "input_audio_buffer.committed"
+	RealtimeServerEventInputAudioBufferCleared            RealtimeServerEventType = "input_audio_buffer.cleared"
+	RealtimeServerEventInputAudioBufferSpeechStarted      RealtimeServerEventType = "input_audio_buffer.speech_started"
+	RealtimeServerEventInputAudioBufferSpeechStopped      RealtimeServerEventType = "input_audio_buffer.speech_stopped"
+	RealtimeServerEventResponseCreated                    RealtimeServerEventType = "response.created"
+	RealtimeServerEventResponseDone                       RealtimeServerEventType = "response.done"
+	RealtimeServerEventResponseOutputItemAdded            RealtimeServerEventType = "response.output_item.added"
+	RealtimeServerEventResponseOutputItemDone             RealtimeServerEventType = "response.output_item.done"
+	RealtimeServerEventResponseContentPartAdded           RealtimeServerEventType = "response.content_part.added"
+	RealtimeServerEventResponseContentPartDone            RealtimeServerEventType = "response.content_part.done"
+	RealtimeServerEventResponseTextDelta                  RealtimeServerEventType = "response.text.delta"
+	RealtimeServerEventResponseTextDone                   RealtimeServerEventType = "response.text.done"
+	RealtimeServerEventResponseAudioTranscriptDelta       RealtimeServerEventType = "response.audio_transcript.delta"
+	RealtimeServerEventResponseAudioTranscriptDone        RealtimeServerEventType = "response.audio_transcript.done"
+	RealtimeServerEventResponseAudioDelta                 RealtimeServerEventType = "response.audio.delta"
+	RealtimeServerEventResponseAudioDone                  RealtimeServerEventType = "response.audio.done"
+	RealtimeServerEventResponseFunctionCallArgumentsDelta RealtimeServerEventType = "response.function_call_arguments.delta"
+	RealtimeServerEventResponseFunctionCallArgumentsDone  RealtimeServerEventType = "response.function_call_arguments.done"
+	RealtimeServerEventRateLimitsUpdated                  RealtimeServerEventType = "rate_limits.updated"
+
+	// not sent by the server: delivered to callbacks after a session is reconnected
+	RealtimeServerEventSessionReconnected RealtimeServerEventType = "session.reconnected"
+)
+
+// RealtimeAudioFormat type for audio formats of realtime sessions
+type RealtimeAudioFormat string
+
+// RealtimeAudioFormat constants
+const (
+	RealtimeAudioFormatPCM16    RealtimeAudioFormat = "pcm16"
+	RealtimeAudioFormatG711ULaw RealtimeAudioFormat = "g711_ulaw"
+	RealtimeAudioFormatG711ALaw RealtimeAudioFormat = "g711_alaw"
+)
+
+// RealtimeModality type for modalities of realtime sessions
+type RealtimeModality string
+
+// RealtimeModality constants
+const (
+	RealtimeModalityText  RealtimeModality = "text"
+	RealtimeModalityAudio RealtimeModality = "audio"
+)
+
+// RealtimeSessionConfig struct for the configuration of a realtime session
+//
+// Only non-nil (non-empty) fields are sent, so the server keeps the others as they are.
+//
+// https://platform.openai.com/docs/api-reference/realtime-client-events/session/update
+type RealtimeSessionConfig struct {
+	ID                      *string                          `json:"id,omitempty"`
+	Model                   *string                          `json:"model,omitempty"`
+	Modalities              []RealtimeModality               `json:"modalities,omitempty"`
+	Instructions            *string                          `json:"instructions,omitempty"`
+	Voice                   *string                          `json:"voice,omitempty"`
+	InputAudioFormat        *RealtimeAudioFormat             `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       *RealtimeAudioFormat             `json:"output_audio_format,omitempty"`
+	InputAudioTranscription *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	Tools                   []RealtimeTool                   `json:"tools,omitempty"`
+	ToolChoice              any                              `json:"tool_choice,omitempty"` // string ('auto', 'none', 'required') or a function
+	Temperature             *float64                         `json:"temperature,omitempty"`
+	MaxResponseOutputTokens any                              `json:"max_response_output_tokens,omitempty"` // int or 'inf'
+}
+
+// RealtimeInputAudioTranscription struct for transcribing input audio of realtime sessions
+type RealtimeInputAudioTranscription struct {
+	Model string `json:"model"`
+}
+
+// RealtimeTurnDetection struct for turn detection of realtime sessions
+type RealtimeTurnDetection struct {
+	Type              string   `json:"type"` // == 'server_vad'
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   *int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs *int     `json:"silence_duration_ms,omitempty"`
+	CreateResponse    *bool    `json:"create_response,omitempty"`
+}
+
+// NewRealtimeServerVAD returns a new RealtimeTurnDetection with server-side voice activity detection.
+func NewRealtimeServerVAD() *RealtimeTurnDetection {
+	return &RealtimeTurnDetection{
+		Type: "server_vad",
+	}
+}
+
+// RealtimeTool struct for function tools of realtime sessions
+type RealtimeTool struct {
+	Type        string                 `json:"type"` // == 'function'
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  ToolFunctionParameters `json:"parameters"`
+}
+
+// NewRealtimeFunctionTool returns a new RealtimeTool for a function.
+func NewRealtimeFunctionTool(name, description string, parameters ToolFunctionParameters) RealtimeTool {
+	return RealtimeTool{
+		Type:        "function",
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+	}
+}
+
+// RealtimeConversationItem struct for items of realtime conversations
+//
+// https://platform.openai.com/docs/api-reference/realtime-client-events/conversation/item/create
+type RealtimeConversationItem struct {
+	ID        *string               `json:"id,omitempty"`
+	Object    *string               `json:"object,omitempty"`
+	Type      string                `json:"type"` // 'message', 'function_call', or 'function_call_output'
+	Status    *string               `json:"status,omitempty"`
+	Role      *string               `json:"role,omitempty"` // 'user', 'assistant', or 'system'
+	Content   []RealtimeContentPart `json:"content,omitempty"`
+	CallID    *string               `json:"call_id,omitempty"`
+	Name      *string               `json:"name,omitempty"`
+	Arguments *string               `json:"arguments,omitempty"`
+	Output    *string               `json:"output,omitempty"`
+}
+
+// RealtimeContentPart struct for contents of realtime conversation items
+type RealtimeContentPart struct {
+	Type       string  `json:"type"` // 'input_text', 'input_audio', 'text', or 'audio'
+	Text       *string `json:"text,omitempty"`
+	Audio      *string `json:"audio,omitempty"` // base64-encoded
+	Transcript *string `json:"transcript,omitempty"`
+}
+
+// NewRealtimeTextMessage returns a new message item with given `role` and `text`.
+func NewRealtimeTextMessage(role ChatMessageRole, text string) RealtimeConversationItem {
+	r := string(role)
+	contentType := "input_text"
+	if role == ChatMessageRoleAssistant {
+		contentType = "text"
+	}
+
+	return RealtimeConversationItem{
+		Type: realtimeItemTypeMessage,
+		Role: &r,
+		Content: []RealtimeContentPart{
+			{Type: contentType, Text: &text},
+		},
+	}
+}
+
+// NewRealtimeAudioMessage returns a new user message item with given (PCM16) `audio`.
+func NewRealtimeAudioMessage(audio []byte) RealtimeConversationItem {
+	role := string(ChatMessageRoleUser)
+	encoded := base64.StdEncoding.EncodeToString(audio)
+
+	return RealtimeConversationItem{
+		Type: realtimeItemTypeMessage,
+		Role: &role,
+		Content: []RealtimeContentPart{
+			{Type: "input_audio", Audio: &encoded},
+		},
+	}
+}
+
+// NewRealtimeFunctionCallOutput returns a new item with the `output` of a function call.
+func NewRealtimeFunctionCallOutput(callID, output string) RealtimeConversationItem {
+	return RealtimeConversationItem{
+		Type:   realtimeItemTypeFunctionCallOutput,
+		CallID: &callID,
+		Output: &output,
+	}
+}
+
+// RealtimeResponseConfig struct for creating responses of realtime sessions
+//
+// https://platform.openai.com/docs/api-reference/realtime-client-events/response/create
+type RealtimeResponseConfig struct {
+	Modalities              []RealtimeModality   `json:"modalities,omitempty"`
+	Instructions            *string              `json:"instructions,omitempty"`
+	Voice                   *string              `json:"voice,omitempty"`
+	OutputAudioFormat       *RealtimeAudioFormat `json:"output_audio_format,omitempty"`
+	Tools                   []RealtimeTool       `json:"tools,omitempty"`
+	ToolChoice              any                  `json:"tool_choice,omitempty"`
+	Temperature             *float64             `json:"temperature,omitempty"`
+	MaxResponseOutputTokens any                  `json:"max_response_output_tokens,omitempty"`
+	Conversation            *string              `json:"conversation,omitempty"` // 'auto' or 'none'
+	Metadata                map[string]string    `json:"metadata,omitempty"`
+}
+
+// RealtimeResponse struct for responses of realtime sessions
+type RealtimeResponse struct {
+	ID            string                     `json:"id"`
+	Object        string                     `json:"object"` // == 'realtime.response'
+	Status        string                     `json:"status"` // 'completed', 'cancelled', 'failed', 'incomplete', or 'in_progress'
+	StatusDetails any                        `json:"status_details,omitempty"`
+	Output        []RealtimeConversationItem `json:"output"`
+	Usage         *RealtimeUsage             `json:"usage,omitempty"`
+}
+
+// RealtimeUsage struct for usages of realtime responses
+type RealtimeUsage struct {
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// RealtimeRateLimit struct for rate limits of realtime sessions
+type RealtimeRateLimit struct {
+	Name         string  `json:"name"` // 'requests' or 'tokens'
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
+
+// RealtimeClientEvent struct for events sent to realtime sessions
+type RealtimeClientEvent struct {
+	EventID *string                 `json:"event_id,omitempty"`
+	Type    RealtimeClientEventType `json:"type"`
+
+	Session        *RealtimeSessionConfig    `json:"session,omitempty"`
+	Audio          *string                   `json:"audio,omitempty"`
+	PreviousItemID *string                   `json:"previous_item_id,omitempty"`
+	Item           *RealtimeConversationItem `json:"item,omitempty"`
+	ItemID         *string                   `json:"item_id,omitempty"`
+	ContentIndex   *int                      `json:"content_index,omitempty"`
+	AudioEndMs     *int                      `json:"audio_end_ms,omitempty"`
+	Response       *RealtimeResponseConfig   `json:"response,omitempty"`
+}
+
+// RealtimeServerEvent struct for events received from realtime sessions
+//
+// Only the fields for its `Type` are set; the original payload is kept in `Raw`.
+//
+// https://platform.openai.com/docs/api-reference/realtime-server-events
+type RealtimeServerEvent struct {
+	EventID string                  `json:"event_id"`
+	Type    RealtimeServerEventType `json:"type"`
+
+	Error          *Error                    `json:"error,omitempty"`
+	Session        *RealtimeSessionConfig    `json:"session,omitempty"`
+	Item           *RealtimeConversationItem `json:"item,omitempty"`
+	Response       *RealtimeResponse         `json:"response,omitempty"`
+	Part           *RealtimeContentPart      `json:"part,omitempty"`
+	RateLimits     []RealtimeRateLimit       `json:"rate_limits,omitempty"`
+	PreviousItemID *string                   `json:"previous_item_id,omitempty"`
+	ItemID         string                    `json:"item_id,omitempty"`
+	ResponseID     string                    `json:"response_id,omitempty"`
+	OutputIndex    int                       `json:"output_index,omitempty"`
+	ContentIndex   int                       `json:"content_index,omitempty"`
+	CallID         string                    `json:"call_id,omitempty"`
+	Name           string                    `json:"name,omitempty"`
+	Delta          string                    `json:"delta,omitempty"`
+	Text           string                    `json:"text,omitempty"`
+	Transcript     string                    `json:"transcript,omitempty"`
+	Arguments      string                    `json:"arguments,omitempty"`
+	AudioStartMs   int                       `json:"audio_start_ms,omitempty"`
+	AudioEndMs     int                       `json:"audio_end_ms,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// AudioDelta decodes the base64-encoded audio of a `response.audio.delta` event.
+func (e RealtimeServerEvent) AudioDelta() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.Delta)
+}
+
+// RealtimeSessionOptions struct for connecting to realtime sessions
+type RealtimeSessionOptions struct {
+	// initial session configuration, sent right after (re)connected
+	Session *RealtimeSessionConfig
+
+	// maximum number of reconnection attempts in a row (default: 3, negative value: never reconnect)
+	MaxReconnects int
+
+	// wait interval before the first reconnection attempt, doubles after each failure (default: 1 second)
+	ReconnectInterval time.Duration
+
+	// maximum number of function calls running in parallel (default: 4)
+	MaxConcurrency int
+
+	// do not create a new response automatically after function call outputs are sent
+	//
+	// By default, one is created after the response with function calls is done and all of their outputs are sent.
+	DisableAutoResponse bool
+}
+
+// callback function for realtime server events
+//
+// `done` is true when the session is closed for good, with a non-nil `err` if it was closed abnormally.
+type realtimeCallback func(event RealtimeServerEvent, done bool, err error)
+
+// RealtimeSession is a realtime session connected over WebSocket.
+//
+// Function calls of registered tools are run automatically, and their outputs are sent back to the session.
+//
+// When the connection is lost unexpectedly, it reconnects and sends the session configuration again.
+// Conversation items of the lost connection are not restored by the server.
+type RealtimeSession struct {
+	client  *Client
+	model   string
+	options RealtimeSessionOptions
+	cb      realtimeCallback
+
+	dispatcher toolDispatcher
+	tools      []RealtimeTool
+	semaphore  chan struct{} // for limiting concurrent function calls
+
+	mutex     sync.Mutex
+	conn      *websocketConn
+	session   RealtimeSessionConfig
+	closed    bool
+	done      chan struct{}
+	responses map[string]*realtimeResponseCalls // key: response id
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// ConnectRealtime connects to a realtime session of given `model` with `options`,
+// and delivers its server events to `cb`.
+//
+// `cb` is called in the order of received events.
+// Errors of automatic function calls may be delivered to it from other goroutines.
+//
+// https://platform.openai.com/docs/guides/realtime
+func (c *Client) ConnectRealtime(ctx context.Context, model string, options RealtimeSessionOptions, cb realtimeCallback) (session *RealtimeSession, err error) {
+	if options.MaxReconnects == 0 {
+		options.MaxReconnects = defaultRealtimeMaxReconnects
+	}
+	if options.ReconnectInterval <= 0 {
+		options.ReconnectInterval = defaultRealtimeReconnectInterval
+	}
+
+	session = &RealtimeSession{
+		client:     c,
+		model:      model,
+		options:    options,
+		cb:         cb,
+		dispatcher: newToolDispatcher(),
+		done:       make(chan struct{}),
+		responses:  map[string]*realtimeResponseCalls{},
+	}
+	if options.MaxConcurrency > 0 {
+		session.dispatcher.maxConcurrency = options.MaxConcurrency
+	}
+	session.semaphore = session.dispatcher.semaphore()
+	if options.Session != nil {
+		session.session = *options.Session
+	}
+	session.ctx, session.cancel = context.WithCancel(context.Background())
+
+	if session.conn, err = session.dial(ctx); err != nil {
+		session.cancel()
+		return nil, err
+	}
+
+	go session.readLoop()
+
+	return session, nil
+}
+
+// returns the url of realtime endpoint for the model
+func (c *Client) realtimeURL(model string) string {
+	u := baseURL
+	if c.baseURL != nil {
+		u = *c.baseURL
+	}
+	switch {
+	case strings.HasPrefix(u, "https://"):
+		u = "wss://" + strings.TrimPrefix(u, "https://")
+	case strings.HasPrefix(u, "http://"):
+		u = "ws://" + strings.TrimPrefix(u, "http://")
+	}
+
+	return fmt.Sprintf("%s/%s?model=%s", u, realtimeEndpoint, url.QueryEscape(model))
+}
+
+// dials the realtime endpoint, and sends the session configuration if any
+func (s *RealtimeSession) dial(ctx context.Context) (conn *websocketConn, err error) {
+	header := http.Header{}
+	header.Set(kAuthorization, fmt.Sprintf("Bearer %s", s.client.APIKey))
+	header.Set(kOrganization, s.client.OrganizationID)
+	if beta := s.client.betaHeader(realtimeEndpoint); beta != "" {
+		header.Set(kBeta, beta)
+	}
+
+	if conn, err = dialWebSocket(ctx, s.client.realtimeURL(s.model), header); err != nil {
+		return nil, fmt.Errorf("failed to connect to realtime session: %s", err)
+	}
+
+	s.mutex.Lock()
+	config := s.sessionConfig()
+	s.mutex.Unlock()
+	if config != nil {
+		if err = s.sendTo(conn, RealtimeClientEvent{Type: RealtimeClientEventSessionUpdate, Session: config}); err != nil {
+			conn.close(websocketCloseNormal, "")
+			return nil, fmt.Errorf("failed to update realtime session: %s", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// returns the accumulated session configuration with registered tools, or nil if there is nothing to send
+//
+// NOTE: should be called with `s.mutex` locked
+func (s *RealtimeSession) sessionConfig() *RealtimeSessionConfig {
+	config := s.session
+	if len(s.tools) > 0 {
+		config.Tools = append(append([]RealtimeTool{}, s.session.Tools...), s.tools...)
+	}
+	if bytes, err := json.Marshal(config); err == nil && string(bytes) == "{}" {
+		return nil
+	}
+	return &config
+}
+
+// RegisterFunction registers a function `tool` with its `handler`, and updates the session's tools.
+//
+// A function registered with the same name again replaces the previous one.
+func (s *RealtimeSession) RegisterFunction(tool RealtimeTool, handler ToolHandler) error {
+	s.mutex.Lock()
+	if _, exists := s.dispatcher.handlers[tool.Name]; exists {
+		for i := range s.tools {
+			if s.tools[i].Name == tool.Name {
+				s.tools[i] = tool
+			}
+		}
+	} else {
+		s.tools = append(s.tools, tool)
+	}
+
+	// copy on write, as running function calls may be reading them
+	handlers := map[string]ToolHandler{tool.Name: handler}
+	for name, h := range s.dispatcher.handlers {
+		if name != tool.Name {
+			handlers[name] = h
+		}
+	}
+	parameters := map[string]ToolFunctionParameters{tool.Name: tool.Parameters}
+	for name, p := range s.dispatcher.parameters {
+		if name != tool.Name {
+			parameters[name] = p
+		}
+	}
+	s.dispatcher.handlers, s.dispatcher.parameters = handlers, parameters
+	config := s.sessionConfig()
+	s.mutex.Unlock()
+
+	return s.Send(RealtimeClientEvent{
+		Type:    RealtimeClientEventSessionUpdate,
+		Session: &RealtimeSessionConfig{Tools: config.Tools},
+	})
+}
+
+// UpdateSession updates the session with given `config`.
+//
+// The configuration is remembered, and sent again after reconnection.
+func (s *RealtimeSession) UpdateSession(config RealtimeSessionConfig) error {
+	s.mutex.Lock()
+	mergeRealtimeSessionConfig(&s.session, config)
+	if len(s.tools) > 0 && config.Tools != nil {
+		config.Tools = append(config.Tools, s.tools...)
+	}
+	s.mutex.Unlock()
+
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventSessionUpdate, Session: &config})
+}
+
+// overwrites fields of `dst` with non-nil fields of `src`
+func mergeRealtimeSessionConfig(dst *RealtimeSessionConfig, src RealtimeSessionConfig) {
+	if src.Model != nil {
+		dst.Model = src.Model
+	}
+	if src.Modalities != nil {
+		dst.Modalities = src.Modalities
+	}
+	if src.Instructions != nil {
+		dst.Instructions = src.Instructions
+	}
+	if src.Voice != nil {
+		dst.Voice = src.Voice
+	}
+	if src.InputAudioFormat != nil {
+		dst.InputAudioFormat = src.InputAudioFormat
+	}
+	if src.OutputAudioFormat != nil {
+		dst.OutputAudioFormat = src.OutputAudioFormat
+	}
+	if src.InputAudioTranscription != nil {
+		dst.InputAudioTranscription = src.InputAudioTranscription
+	}
+	if src.TurnDetection != nil {
+		dst.TurnDetection = src.TurnDetection
+	}
+	if src.Tools != nil {
+		dst.Tools = src.Tools
+	}
+	if src.ToolChoice != nil {
+		dst.ToolChoice = src.ToolChoice
+	}
+	if src.Temperature != nil {
+		dst.Temperature = src.Temperature
+	}
+	if src.MaxResponseOutputTokens != nil {
+		dst.MaxResponseOutputTokens = src.MaxResponseOutputTokens
+	}
+}
+
+// AppendInputAudio appends `audio` (in the session's input audio format, eg. 16-bit PCM at 24kHz, mono, little-endian) to the input audio buffer.
+func (s *RealtimeSession) AppendInputAudio(audio []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(audio)
+
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventInputAudioBufferAppend, Audio: &encoded})
+}
+
+// CommitInputAudio commits the input audio buffer as a new user message.
+func (s *RealtimeSession) CommitInputAudio() error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventInputAudioBufferCommit})
+}
+
+// ClearInputAudio clears the input audio buffer.
+func (s *RealtimeSession) ClearInputAudio() error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventInputAudioBufferClear})
+}
+
+// CreateConversationItem adds an `item` to the conversation.
+func (s *RealtimeSession) CreateConversationItem(item RealtimeConversationItem) error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventConversationItemCreate, Item: &item})
+}
+
+// DeleteConversationItem deletes an item with given `itemID` from the conversation.
+func (s *RealtimeSession) DeleteConversationItem(itemID string) error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventConversationItemDelete, ItemID: &itemID})
+}
+
+// SendText adds a user message with given `text` to the conversation, and creates a response.
+func (s *RealtimeSession) SendText(text string) error {
+	if err := s.CreateConversationItem(NewRealtimeTextMessage(ChatMessageRoleUser, text)); err != nil {
+		return err
+	}
+	return s.CreateResponse(nil)
+}
+
+// CreateResponse asks the model to create a response, with optional `config`.
+func (s *RealtimeSession) CreateResponse(config *RealtimeResponseConfig) error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventResponseCreate, Response: config})
+}
+
+// CancelResponse cancels the response in progress.
+func (s *RealtimeSession) CancelResponse() error {
+	return s.Send(RealtimeClientEvent{Type: RealtimeClientEventResponseCancel})
+}
+
+// Send sends a client `event` to the session.
+func (s *RealtimeSession) Send(event RealtimeClientEvent) error {
+	s.mutex.Lock()
+	conn, closed := s.conn, s.closed
+	s.mutex.Unlock()
+
+	if closed {
+		return fmt.Errorf("realtime session is closed")
+	}
+	if conn == nil {
+		return fmt.Errorf("realtime session is reconnecting")
+	}
+
+	return s.sendTo(conn, event)
+}
+
+// sends a client `event` through `conn`
+func (s *RealtimeSession) sendTo(conn *websocketConn, event RealtimeClientEvent) error {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal realtime event: %s", err)
+	}
+
+	if s.client.Verbose {
+		log.Printf("dump realtime event:\n\n%s", string(bytes))
+	}
+
+	return conn.writeText(bytes)
+}
+
+// Close closes the session.
+func (s *RealtimeSession) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	conn := s.conn
+	s.mutex.Unlock()
+
+	s.cancel()
+
+	var err error
+	select {
+	case <-s.done: // already disconnected
+		return nil
+	default:
+	}
+	if conn != nil {
+		err = conn.close(websocketCloseNormal, "")
+	}
+	<-s.done
+
+	return err
+}
+
+// Done returns a channel which is closed when the session is closed for good.
+func (s *RealtimeSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// reads server events until the session is closed, reconnecting on unexpected disconnections
+func (s *RealtimeSession) readLoop() {
+	var err error
+	defer func() {
+		s.cancel()
+		if s.cb != nil {
+			s.cb(RealtimeServerEvent{}, true, err)
+		}
+		close(s.done)
+	}()
+
+	s.mutex.Lock()
+	conn := s.conn
+	s.mutex.Unlock()
+
+	for {
+		var opcode byte
+		var payload []byte
+		if opcode, payload, err = conn.readMessage(); err != nil {
+			if s.isClosed() {
+				err = nil
+				return
+			}
+
+			if conn, err = s.reconnect(err); err != nil || conn == nil {
+				return
+			}
+			if s.cb != nil {
+				s.cb(RealtimeServerEvent{Type: RealtimeServerEventSessionReconnected}, false, nil)
+			}
+			continue
+		}
+		if opcode != websocketOpText {
+			continue
+		}
+
+		if s.client.Verbose {
+			log.Printf("API response for %s: '%s'", realtimeEndpoint, string(payload))
+		}
+
+		var event RealtimeServerEvent
+		if e := json.Unmarshal(payload, &event); e != nil {
+			if s.cb != nil {
+				s.cb(event, false, fmt.Errorf("failed to parse realtime event: %s", e))
+			}
+			continue
+		}
+		event.Raw = payload
+
+		switch event.Type {
+		case RealtimeServerEventResponseFunctionCallArgumentsDone:
+			s.handleFunctionCall(event)
+		case RealtimeServerEventResponseDone:
+			if event.Response != nil {
+				s.finishResponseCalls(event.Response.ID, true, false)
+			}
+		}
+
+		if s.cb != nil {
+			if event.Type == RealtimeServerEventError && event.Error != nil {
+				s.cb(event, false, event.Error.err())
+			} else {
+				s.cb(event, false, nil)
+			}
+		}
+	}
+}
+
+// returns if the session was closed with `Close`
+func (s *RealtimeSession) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.closed
+}
+
+// reconnects the session after the connection was lost with `cause`
+func (s *RealtimeSession) reconnect(cause error) (conn *websocketConn, err error) {
+	s.mutex.Lock()
+	if s.conn != nil {
+		s.conn.conn.Close()
+	}
+	s.conn = nil
+	s.mutex.Unlock()
+
+	var closeErr *WebSocketCloseError
+	if s.options.MaxReconnects < 0 || (errors.As(cause, &closeErr) && closeErr.Code == websocketCloseNormal) {
+		return nil, cause
+	}
+
+	interval := s.options.ReconnectInterval
+	for attempt := 0; attempt < s.options.MaxReconnects; attempt++ {
+		select {
+		case <-s.ctx.Done():
+			return nil, nil
+		case <-time.After(interval):
+		}
+
+		if conn, err = s.dial(s.ctx); err == nil {
+			s.mutex.Lock()
+			if s.closed {
+				s.mutex.Unlock()
+				conn.close(websocketCloseNormal, "")
+				return nil, nil
+			}
+			s.conn = conn
+			s.responses = map[string]*realtimeResponseCalls{} // responses of the lost connection will not be done
+			s.mutex.Unlock()
+
+			return conn, nil
+		}
+
+		interval *= 2
+		if interval > defaultRealtimeMaxReconnectWait {
+			interval = defaultRealtimeMaxReconnectWait
+		}
+	}
+
+	return nil, fmt.Errorf("connection lost (%s), and failed to reconnect: %s", cause, err)
+}
+
+// function calls of a response, for creating a new response after all of their outputs are sent
+type realtimeResponseCalls struct {
+	running int  // number of function calls which are not finished yet
+	outputs int  // number of function call outputs which were sent
+	done    bool // whether `response.done` of the response has arrived
+}
+
+// runs a function call of registered tools, and sends its output back to the session
+//
+// Unless `DisableAutoResponse` is set, a new response is created once after `response.done` of the response
+// has arrived and all of its function call outputs are sent, as only one response can be active at a time.
+func (s *RealtimeSession) handleFunctionCall(event RealtimeServerEvent) {
+	s.mutex.Lock()
+	dispatcher := s.dispatcher
+	if _, exists := dispatcher.handlers[event.Name]; !exists {
+		s.mutex.Unlock()
+		return // not registered, so leave it to the callback
+	}
+	calls, exists := s.responses[event.ResponseID]
+	if !exists {
+		calls = &realtimeResponseCalls{}
+		s.responses[event.ResponseID] = calls
+	}
+	calls.running++
+	s.mutex.Unlock()
+
+	go func() {
+		sent := false
+		defer func() {
+			s.finishResponseCalls(event.ResponseID, false, sent)
+		}()
+
+		select {
+		case s.semaphore <- struct{}{}:
+			defer func() { <-s.semaphore }()
+		case <-s.ctx.Done():
+			return
+		}
+
+		call := ToolCall{
+			ID:   event.CallID,
+			Type: "function",
+			Function: ToolCallFunction{
+				Name:      event.Name,
+				Arguments: event.Arguments,
+			},
+		}
+
+		output := dispatcher.dispatch(s.ctx, call)
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if err := s.CreateConversationItem(NewRealtimeFunctionCallOutput(event.CallID, output)); err != nil {
+			if s.cb != nil {
+				s.cb(RealtimeServerEvent{}, false, fmt.Errorf("failed to send output of function `%s`: %s", event.Name, err))
+			}
+			return
+		}
+		sent = true
+	}()
+}
+
+// marks `response.done` of a response (`done`), or a finished function call of it (with its output `sent`),
+// and creates a new response when the response is done and all of its outputs are sent
+func (s *RealtimeSession) finishResponseCalls(responseID string, done, sent bool) {
+	s.mutex.Lock()
+	calls, exists := s.responses[responseID]
+	if !exists {
+		s.mutex.Unlock()
+		return // response without function calls of registered tools
+	}
+	if done {
+		calls.done = true
+	} else {
+		calls.running--
+		if sent {
+			calls.outputs++
+		}
+	}
+	if !calls.done || calls.running > 0 {
+		s.mutex.Unlock()
+		return
+	}
+	delete(s.responses, responseID)
+	s.mutex.Unlock()
+
+	if calls.outputs > 0 && !s.options.DisableAutoResponse && s.ctx.Err() == nil {
+		if err := s.CreateResponse(nil); err != nil && s.cb != nil {
+			s.cb(RealtimeServerEvent{}, false, fmt.Errorf("failed to create response after function calls: %s", err))
+		}
+	}
+}
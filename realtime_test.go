@@ -0,0 +1,584 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a connection of the local WebSocket stand-in server
+type realtimeStandInConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// reads a client event
+func (c realtimeStandInConn) read() (event map[string]any, err error) {
+	var payload []byte
+	if _, _, payload, err = readWebSocketFrame(c.reader); err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(payload, &event)
+	return event, err
+}
+
+// writes a server event
+func (c realtimeStandInConn) write(event string) error {
+	return writeWebSocketFrame(c.conn, websocketOpText, []byte(event), false)
+}
+
+// starts a local WebSocket stand-in server for realtime sessions,
+// which calls `handle` with each accepted connection and its (0-based) index
+func newRealtimeStandInServer(t *testing.T, handle func(conn realtimeStandInConn, index int)) *httptest.Server {
+	var mutex sync.Mutex
+	index := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/realtime" || r.URL.Query().Get("model") != "gpt-4o-realtime-preview" {
+			t.Errorf("Unexpected request: %s", r.URL.String())
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected authorization header, got '%s'", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("OpenAI-Beta") != "realtime=v1" {
+			t.Errorf("Expected beta header 'realtime=v1', got '%s'", r.Header.Get("OpenAI-Beta"))
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Failed to hijack connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+			websocketAcceptKey(r.Header.Get("Sec-WebSocket-Key")))
+		rw.Flush()
+
+		mutex.Lock()
+		i := index
+		index++
+		mutex.Unlock()
+
+		handle(realtimeStandInConn{conn: conn, reader: rw.Reader}, i)
+	}))
+}
+
+func TestRealtimeSessionMock(t *testing.T) {
+	pcm := []byte{0x00, 0x01, 0x02, 0x03, 0xFE, 0xFF}
+
+	var mutex sync.Mutex
+	var received []string
+	var appendedAudio []byte
+	var functionOutput string
+
+	server := newRealtimeStandInServer(t, func(conn realtimeStandInConn, index int) {
+		responses := 0
+		for {
+			event, err := conn.read()
+			if err != nil {
+				return
+			}
+
+			mutex.Lock()
+			received = append(received, event["type"].(string))
+			mutex.Unlock()
+
+			switch event["type"] {
+			case "session.update":
+				session, _ := json.Marshal(event["session"])
+				conn.write(`{"event_id":"evt_1","type":"session.updated","session":` + string(session) + `}`)
+			case "input_audio_buffer.append":
+				audio, _ := base64.StdEncoding.DecodeString(event["audio"].(string))
+				mutex.Lock()
+				appendedAudio = append(appendedAudio, audio...)
+				mutex.Unlock()
+			case "input_audio_buffer.commit":
+				conn.write(`{"event_id":"evt_2","type":"input_audio_buffer.committed","item_id":"item_1"}`)
+			case "conversation.item.create":
+				item := event["item"].(map[string]any)
+				if item["type"] == "function_call_output" {
+					mutex.Lock()
+					functionOutput = item["output"].(string)
+					mutex.Unlock()
+				}
+			case "response.create":
+				responses++
+				if responses == 1 {
+					conn.write(`{"event_id":"evt_3","type":"response.function_call_arguments.done","response_id":"resp_1","item_id":"item_2","call_id":"call_1","name":"get_weather","arguments":"{\"city\":\"Seoul\"}"}`)
+					conn.write(`{"event_id":"evt_4","type":"response.done","response":{"id":"resp_1","object":"realtime.response","status":"completed","output":[]}}`)
+				} else {
+					conn.write(`{"event_id":"evt_4","type":"response.audio.delta","response_id":"resp_2","delta":"` + base64.StdEncoding.EncodeToString(pcm) + `"}`)
+					conn.write(`{"event_id":"evt_5","type":"response.text.delta","response_id":"resp_2","delta":"It is "}`)
+					conn.write(`{"event_id":"evt_6","type":"response.text.delta","response_id":"resp_2","delta":"sunny."}`)
+					conn.write(`{"event_id":"evt_7","type":"response.done","response":{"id":"resp_2","object":"realtime.response","status":"completed","output":[],"usage":{"total_tokens":30,"input_tokens":20,"output_tokens":10}}}`)
+				}
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+	client.SetBetaHeader("assistants=v2") // should not affect the realtime endpoint
+
+	instructions := "Answer briefly."
+	var text strings.Builder
+	var audio bytes.Buffer
+	var usage *RealtimeUsage
+	responseDone := make(chan struct{}, 1)
+	finished := make(chan error, 1)
+
+	session, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{
+		Session: &RealtimeSessionConfig{
+			Modalities:   []RealtimeModality{RealtimeModalityText, RealtimeModalityAudio},
+			Instructions: &instructions,
+		},
+	}, func(event RealtimeServerEvent, done bool, err error) {
+		if done {
+			finished <- err
+			return
+		}
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+
+		switch event.Type {
+		case RealtimeServerEventResponseTextDelta:
+			text.WriteString(event.Delta)
+		case RealtimeServerEventResponseAudioDelta:
+			decoded, err := event.AudioDelta()
+			if err != nil {
+				t.Errorf("Failed to decode audio delta: %v", err)
+			}
+			audio.Write(decoded)
+		case RealtimeServerEventResponseDone:
+			if event.Response.ID == "resp_2" {
+				usage = event.Response.Usage
+				responseDone <- struct{}{}
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("ConnectRealtime failed: %v", err)
+	}
+
+	if err := session.RegisterFunction(NewRealtimeFunctionTool("get_weather", "Get the weather of a city.", NewToolFunctionParameters().
+		AddPropertyWithDescription("city", "string", "name of the city").
+		SetRequiredParameters([]string{"city"})),
+		func(ctx context.Context, call ToolCall) (string, error) {
+			return "sunny", nil
+		}); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	if err := session.AppendInputAudio(pcm); err != nil {
+		t.Fatalf("AppendInputAudio failed: %v", err)
+	}
+	if err := session.CommitInputAudio(); err != nil {
+		t.Fatalf("CommitInputAudio failed: %v", err)
+	}
+	if err := session.SendText("How is the weather in Seoul?"); err != nil {
+		t.Fatalf("SendText failed: %v", err)
+	}
+
+	select {
+	case <-responseDone:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Response was not done")
+	}
+
+	if err := session.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Errorf("Expected no error on close, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Session was not finished")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if text.String() != "It is sunny." {
+		t.Errorf("Expected 'It is sunny.', got '%s'", text.String())
+	}
+	if !bytes.Equal(audio.Bytes(), pcm) {
+		t.Errorf("Expected audio %v, got %v", pcm, audio.Bytes())
+	}
+	if !bytes.Equal(appendedAudio, pcm) {
+		t.Errorf("Expected appended audio %v, got %v", pcm, appendedAudio)
+	}
+	if functionOutput != "sunny" {
+		t.Errorf("Expected function output 'sunny', got '%s'", functionOutput)
+	}
+	if usage == nil || usage.TotalTokens != 30 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	expected := []string{
+		"session.update",
+		"session.update",
+		"input_audio_buffer.append",
+		"input_audio_buffer.commit",
+		"conversation.item.create",
+		"response.create",
+		"conversation.item.create",
+		"response.create",
+	}
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected client events %v, got %v", expected, received)
+	}
+}
+
+func TestRealtimeSessionReconnect(t *testing.T) {
+	var mutex sync.Mutex
+	var instructions []string
+
+	server := newRealtimeStandInServer(t, func(conn realtimeStandInConn, index int) {
+		event, err := conn.read()
+		if err != nil || event["type"] != "session.update" {
+			t.Errorf("Expected session.update, got %v (%v)", event, err)
+			return
+		}
+
+		session := event["session"].(map[string]any)
+		mutex.Lock()
+		instructions = append(instructions, session["instructions"].(string))
+		mutex.Unlock()
+
+		if index == 0 {
+			return // drop the first connection abruptly
+		}
+
+		conn.write(`{"event_id":"evt_1","type":"session.updated","session":{"instructions":"` + session["instructions"].(string) + `"}}`)
+		for {
+			if _, err := conn.read(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	initial := "Be nice."
+	updated := make(chan RealtimeServerEvent, 1)
+	var eventTypes []RealtimeServerEventType
+
+	session, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{
+		Session:           &RealtimeSessionConfig{Instructions: &initial},
+		ReconnectInterval: time.Millisecond,
+	}, func(event RealtimeServerEvent, done bool, err error) {
+		if done {
+			return
+		}
+		eventTypes = append(eventTypes, event.Type)
+		if event.Type == RealtimeServerEventSessionUpdated {
+			updated <- event
+		}
+	})
+	if err != nil {
+		t.Fatalf("ConnectRealtime failed: %v", err)
+	}
+	defer session.Close()
+
+	select {
+	case event := <-updated:
+		if event.Session == nil || event.Session.Instructions == nil || *event.Session.Instructions != initial {
+			t.Errorf("Unexpected session: %+v", event.Session)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Session was not reconnected")
+	}
+
+	if len(eventTypes) != 2 || eventTypes[0] != RealtimeServerEventSessionReconnected {
+		t.Errorf("Expected reconnected and updated events, got %v", eventTypes)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(instructions) != 2 || instructions[1] != initial {
+		t.Errorf("Expected session configuration to be sent again, got %v", instructions)
+	}
+}
+
+func TestRealtimeSessionHandshakeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"Incorrect API key provided.","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	_, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "Incorrect API key provided.") {
+		t.Errorf("Expected handshake error, got %v", err)
+	}
+}
+
+func TestBetaHeader(t *testing.T) {
+	client := NewClient("test-key", "test-org")
+	for endpoint, expected := range map[string]string{
+		"realtime":              "realtime=v1",
+		"assistants":            "assistants=v2",
+		"threads/thread_1/runs": "assistants=v2",
+		"vector_stores/vs_1":    "assistants=v2",
+		"chat/completions":      "",
+	} {
+		if beta := client.betaHeader(endpoint); beta != expected {
+			t.Errorf("Expected beta header '%s' for %s, got '%s'", expected, endpoint, beta)
+		}
+	}
+
+	// set for all endpoints except realtime
+	client.SetBetaHeader("assistants=v1")
+	for endpoint, expected := range map[string]string{
+		"realtime":              "realtime=v1",
+		"threads/thread_1/runs": "assistants=v1",
+		"chat/completions":      "assistants=v1",
+	} {
+		if beta := client.betaHeader(endpoint); beta != expected {
+			t.Errorf("Expected beta header '%s' for %s with SetBetaHeader, got '%s'", expected, endpoint, beta)
+		}
+	}
+}
+
+func TestRealtimeSessionFunctionCallConcurrency(t *testing.T) {
+	const calls, maxConcurrency = 6, 2
+
+	outputs := make(chan string, calls)
+	server := newRealtimeStandInServer(t, func(conn realtimeStandInConn, index int) {
+		for {
+			event, err := conn.read()
+			if err != nil {
+				return
+			}
+
+			switch event["type"] {
+			case "response.create":
+				for i := 0; i < calls; i++ {
+					conn.write(fmt.Sprintf(`{"type":"response.function_call_arguments.done","call_id":"call_%d","name":"slow","arguments":"{}"}`, i))
+				}
+			case "conversation.item.create":
+				item := event["item"].(map[string]any)
+				if item["type"] == "function_call_output" {
+					outputs <- item["output"].(string)
+				}
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	session, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{
+		MaxConcurrency:      maxConcurrency,
+		DisableAutoResponse: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("ConnectRealtime failed: %v", err)
+	}
+	defer session.Close()
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	if err := session.RegisterFunction(NewRealtimeFunctionTool("slow", "A slow function.", NewToolFunctionParameters()),
+		func(ctx context.Context, call ToolCall) (string, error) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(30 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return "done", nil
+		}); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	if err := session.CreateResponse(nil); err != nil {
+		t.Fatalf("CreateResponse failed: %v", err)
+	}
+
+	for i := 0; i < calls; i++ {
+		select {
+		case <-outputs:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d function outputs, got %d", calls, i)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if maxRunning > maxConcurrency {
+		t.Errorf("Expected at most %d concurrent function calls, got %d", maxConcurrency, maxRunning)
+	}
+}
+
+func TestRealtimeSessionAutoResponse(t *testing.T) {
+	const calls = 3
+
+	var mutex sync.Mutex
+	responses, outputs := 0, 0
+	finished := make(chan struct{}, 1)
+	server := newRealtimeStandInServer(t, func(conn realtimeStandInConn, index int) {
+		active := false
+		for {
+			event, err := conn.read()
+			if err != nil {
+				return
+			}
+
+			switch event["type"] {
+			case "response.create":
+				if active {
+					t.Errorf("Unexpected response.create while a response is active")
+					conn.write(`{"type":"error","error":{"message":"Conversation already has an active response","type":"invalid_request_error"}}`)
+					continue
+				}
+				active = true
+
+				mutex.Lock()
+				responses++
+				n := responses
+				mutex.Unlock()
+
+				if n == 1 {
+					for i := 0; i < calls; i++ {
+						conn.write(fmt.Sprintf(`{"type":"response.function_call_arguments.done","response_id":"resp_1","call_id":"call_%d","name":"lookup","arguments":"{\"index\":%d}"}`, i, i))
+					}
+				}
+				conn.write(fmt.Sprintf(`{"type":"response.done","response":{"id":"resp_%d","object":"realtime.response","status":"completed","output":[]}}`, n))
+				active = false
+
+				if n == 2 {
+					finished <- struct{}{}
+				}
+			case "conversation.item.create":
+				item := event["item"].(map[string]any)
+				if item["type"] == "function_call_output" {
+					mutex.Lock()
+					outputs++
+					mutex.Unlock()
+				}
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	session, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{}, func(event RealtimeServerEvent, done bool, err error) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("ConnectRealtime failed: %v", err)
+	}
+	defer session.Close()
+
+	type lookupArgs struct {
+		Index int `json:"index"`
+	}
+	if err := session.RegisterFunction(NewRealtimeFunctionTool("lookup", "Look up something.", NewToolFunctionParameters().
+		AddPropertyWithDescription("index", "integer", "index to look up")),
+		NewToolHandler(func(ctx context.Context, args lookupArgs) (any, error) {
+			// one of them finishes after `response.done`
+			if args.Index == 0 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return args.Index, nil
+		})); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	if err := session.CreateResponse(nil); err != nil {
+		t.Fatalf("CreateResponse failed: %v", err)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No response was created after function calls")
+	}
+	time.Sleep(100 * time.Millisecond) // for catching more response.create events, if any
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if responses != 2 || outputs != calls {
+		t.Errorf("Expected 2 responses after %d function outputs, got %d responses and %d outputs", calls, responses, outputs)
+	}
+}
+
+func TestRealtimeSessionRegisterFunctionAgain(t *testing.T) {
+	updates := make(chan []any, 2)
+	server := newRealtimeStandInServer(t, func(conn realtimeStandInConn, index int) {
+		for {
+			event, err := conn.read()
+			if err != nil {
+				return
+			}
+			if event["type"] != "session.update" {
+				t.Errorf("Expected session.update, got %v", event)
+				return
+			}
+			tools, _ := event["session"].(map[string]any)["tools"].([]any)
+			updates <- tools
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	session, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview", RealtimeSessionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("ConnectRealtime failed: %v", err)
+	}
+	defer session.Close()
+
+	for _, description := range []string{"Look up something.", "Look up something again."} {
+		if err := session.RegisterFunction(NewRealtimeFunctionTool("lookup", description, NewToolFunctionParameters()),
+			func(ctx context.Context, call ToolCall) (string, error) {
+				return description, nil
+			}); err != nil {
+			t.Fatalf("RegisterFunction failed: %v", err)
+		}
+	}
+
+	var tools []any
+	for i := 0; i < 2; i++ {
+		select {
+		case tools = <-updates:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Session was not updated")
+		}
+	}
+	if len(tools) != 1 || tools[0].(map[string]any)["description"] != "Look up something again." {
+		t.Errorf("Expected the registered function to be replaced, got %v", tools)
+	}
+}
@@ -49,10 +49,15 @@ type ResponseOutput struct {
 	CallID    string `json:"call_id,omitempty"`
 	Name      string `json:"name,omitempty"`
 	Arguments string `json:"arguments,omitempty"`
+
+	// Reasoning fields (when Type == "reasoning")
+	Summary          []OutputContent `json:"summary,omitempty"`
+	EncryptedContent string          `json:"encrypted_content,omitempty"` // when `reasoning.encrypted_content` is included
 } // OutputContent represents content within a response output
 type OutputContent struct {
 	Type        string       `json:"type"`
 	Text        string       `json:"text,omitempty"`
+	Refusal     string       `json:"refusal,omitempty"` // when type == 'refusal'
 	Annotations []Annotation `json:"annotations,omitempty"`
 }
 
@@ -101,6 +106,24 @@ type ResponseTool struct {
 	Name        string                 `json:"name,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Parameters  ToolFunctionParameters `json:"parameters,omitempty"`
+	Strict      *bool                  `json:"strict,omitempty"`
+
+	// when Type == "file_search"
+	VectorStoreIDs []string                      `json:"vector_store_ids,omitempty"`
+	MaxNumResults  *int                          `json:"max_num_results,omitempty"`
+	RankingOptions *ToolFileSearchRankingOptions `json:"ranking_options,omitempty"`
+	Filters        any                           `json:"filters,omitempty"` // VectorStoreComparisonFilter | VectorStoreCompoundFilter
+}
+
+// ResponseTextFormat represents the format of text outputs
+type ResponseTextFormat struct {
+	Type string `json:"type"` // "text" | "json_object" | "json_schema"
+
+	// when Type == "json_schema"
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Strict      *bool          `json:"strict,omitempty"`
 }
 
 // Tool choice constants
@@ -213,6 +236,17 @@ func (o ResponseOptions) SetParallelToolCalls(parallel bool) ResponseOptions {
 	return o
 }
 
+// SetTextFormat sets the format of the text parameter
+func (o ResponseOptions) SetTextFormat(format ResponseTextFormat) ResponseOptions {
+	text, ok := o["text"].(map[string]any)
+	if !ok {
+		text = map[string]any{}
+	}
+	text["format"] = format
+	o["text"] = text
+	return o
+}
+
 // responseCallback defines the callback function for streaming responses
 type responseCallback func(response ResponseStreamEvent, done bool, err error)
 
@@ -370,6 +404,14 @@ func NewResponseTool(name, description string, parameters ToolFunctionParameters
 	}
 }
 
+// NewResponseFileSearchTool creates a file search tool for responses API, searching vector stores with given `vectorStoreIDs`
+func NewResponseFileSearchTool(vectorStoreIDs ...string) ResponseTool {
+	return ResponseTool{
+		Type:           "file_search",
+		VectorStoreIDs: vectorStoreIDs,
+	}
+}
+
 // ArgumentsParsed returns the parsed arguments from a function call ResponseOutput
 func (r ResponseOutput) ArgumentsParsed() (result map[string]any, err error) {
 	if r.Type == "function_call" && r.Arguments != "" {
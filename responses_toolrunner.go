@@ -0,0 +1,466 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// automatic function-call loop for the responses API
+
+// ResponseToolRunResult struct for the result of ResponseToolRunner
+type ResponseToolRunResult struct {
+	// the last response
+	Response Response
+
+	// all input items, including the given ones, outputs of responses, and function call outputs
+	Input []any
+
+	// number of response requests
+	Iterations int
+}
+
+// ResponseToolRunner runs function calls of responses automatically with registered handlers,
+// and feeds their results back to the model until it stops calling functions.
+//
+// Turns are chained with `previous_response_id`, or with replaying all input items (including encrypted reasoning items) when `store` is false.
+type ResponseToolRunner struct {
+	toolDispatcher
+
+	client  *Client
+	model   string
+	options ResponseOptions
+
+	tools []ResponseTool
+
+	maxIterations int
+}
+
+// NewResponseToolRunner returns a new ResponseToolRunner for `model`.
+func NewResponseToolRunner(client *Client, model string) *ResponseToolRunner {
+	return &ResponseToolRunner{
+		toolDispatcher: newToolDispatcher(),
+		client:         client,
+		model:          model,
+		maxIterations:  defaultToolRunnerMaxIterations,
+	}
+}
+
+// Register registers a function `tool` definition with its `handler`.
+//
+// Handlers receive function calls converted to ToolCall, so the same handlers can be shared with ToolRunner.
+func (r *ResponseToolRunner) Register(tool ResponseTool, handler ToolHandler) *ResponseToolRunner {
+	if _, exists := r.handlers[tool.Name]; exists {
+		for i := range r.tools {
+			if r.tools[i].Name == tool.Name {
+				r.tools[i] = tool
+			}
+		}
+	} else {
+		r.tools = append(r.tools, tool)
+	}
+	r.handlers[tool.Name] = handler
+	r.parameters[tool.Name] = tool.Parameters
+
+	return r
+}
+
+// Tools returns the registered tool definitions.
+func (r *ResponseToolRunner) Tools() []ResponseTool {
+	return r.tools
+}
+
+// SetOptions sets response options used for each request.
+//
+// Registered tools are appended to `tools`, and `previous_response_id` is set on following requests.
+func (r *ResponseToolRunner) SetOptions(options ResponseOptions) *ResponseToolRunner {
+	r.options = options
+	return r
+}
+
+// SetMaxIterations sets the maximum number of response requests. (default: 10)
+func (r *ResponseToolRunner) SetMaxIterations(maxIterations int) *ResponseToolRunner {
+	r.maxIterations = maxIterations
+	return r
+}
+
+// SetMaxConcurrency sets the maximum number of function calls running in parallel. (default: 4)
+func (r *ResponseToolRunner) SetMaxConcurrency(maxConcurrency int) *ResponseToolRunner {
+	r.maxConcurrency = maxConcurrency
+	return r
+}
+
+// SetValidateArguments sets whether to validate arguments against the parameters of tools before running them. (default: true)
+//
+// Invalid arguments are not passed to handlers, and an error describing them is sent back to the model instead.
+func (r *ResponseToolRunner) SetValidateArguments(validate bool) *ResponseToolRunner {
+	r.validateArguments = validate
+	return r
+}
+
+// SetApprover sets a function which approves or denies each function call before it runs.
+func (r *ResponseToolRunner) SetApprover(approver ToolApprover) *ResponseToolRunner {
+	r.approver = approver
+	return r
+}
+
+// SetPanicHandler sets a function which handles panics recovered from tool handlers.
+func (r *ResponseToolRunner) SetPanicHandler(panicHandler ToolPanicHandler) *ResponseToolRunner {
+	r.panicHandler = panicHandler
+	return r
+}
+
+// Run creates responses with given `input`, running requested function calls,
+// until the model stops calling functions or the maximum number of iterations is reached.
+func (r *ResponseToolRunner) Run(ctx context.Context, input any) (result ResponseToolRunResult, err error) {
+	return r.run(ctx, input, nil)
+}
+
+// RunStream does the same as `Run`, but with streaming.
+//
+// Each function call runs as soon as its arguments are streamed completely.
+// `cb` receives all events, and the completion event of the final response with `done` == true.
+func (r *ResponseToolRunner) RunStream(ctx context.Context, input any, cb responseCallback) (result ResponseToolRunResult, err error) {
+	return r.run(ctx, input, cb)
+}
+
+// checks if responses are not stored, so input items should be replayed
+func (r *ResponseToolRunner) isStateless() bool {
+	store, ok := r.options["store"].(bool)
+	return ok && !store
+}
+
+// builds options for a request
+func (r *ResponseToolRunner) buildOptions(previousResponseID string) ResponseOptions {
+	options := ResponseOptions{}
+	for k, v := range r.options {
+		options[k] = v
+	}
+	delete(options, "stream")
+
+	if len(r.tools) > 0 {
+		tools := []any{}
+		if existing, ok := options["tools"].([]any); ok {
+			tools = append(tools, existing...)
+		}
+		for _, tool := range r.tools {
+			tools = append(tools, tool)
+		}
+		options.SetTools(tools)
+	}
+	if previousResponseID != "" {
+		options["previous_response_id"] = previousResponseID
+	}
+	if r.isStateless() {
+		options["include"] = includeEncryptedReasoning(options["include"])
+	}
+
+	return options
+}
+
+// appends `reasoning.encrypted_content` to given `include` option, so that reasoning items can be replayed
+func includeEncryptedReasoning(include any) []any {
+	const encryptedReasoning = "reasoning.encrypted_content"
+
+	included := []any{}
+	switch existing := include.(type) {
+	case []string:
+		for _, item := range existing {
+			included = append(included, item)
+		}
+	case []any:
+		included = append(included, existing...)
+	}
+	for _, item := range included {
+		if item == encryptedReasoning {
+			return included
+		}
+	}
+
+	return append(included, encryptedReasoning)
+}
+
+// runs the loop, with streaming if `cb` is not nil
+func (r *ResponseToolRunner) run(ctx context.Context, input any, cb responseCallback) (result ResponseToolRunResult, err error) {
+	result.Input = responseInputItems(input)
+
+	stateless := r.isStateless()
+	next := result.Input
+	previousResponseID := ""
+
+	fail := func(err error) (ResponseToolRunResult, error) {
+		if cb != nil {
+			cb(ResponseStreamEvent{}, true, err)
+		}
+		return result, err
+	}
+
+	for result.Iterations < r.maxIterations {
+		options := r.buildOptions(previousResponseID)
+
+		result.Iterations++
+		var outputs []ResponseFunctionCallOutput
+		if cb != nil {
+			result.Response, outputs, err = r.streamResponse(ctx, next, options, cb)
+		} else {
+			if result.Response, err = r.client.CreateResponseWithContext(ctx, r.model, next, options); err == nil {
+				outputs = r.runFunctionCalls(ctx, result.Response.Output)
+			}
+		}
+		if err != nil {
+			return fail(err)
+		}
+
+		for _, output := range result.Response.Output {
+			if isReplayableResponseOutput(output) {
+				result.Input = append(result.Input, responseInputItemFromOutput(output))
+			}
+		}
+
+		if len(outputs) <= 0 {
+			if cb != nil {
+				cb(ResponseStreamEvent{Type: responseTerminalEventType(result.Response), Response: &result.Response}, true, nil)
+			}
+			return result, nil
+		}
+
+		for _, output := range outputs {
+			result.Input = append(result.Input, output)
+		}
+		if cb != nil {
+			cb(ResponseStreamEvent{Type: responseTerminalEventType(result.Response), Response: &result.Response}, false, nil)
+		}
+
+		if err = ctx.Err(); err != nil {
+			return fail(err)
+		}
+
+		if stateless {
+			next = result.Input
+		} else {
+			next = []any{}
+			for _, output := range outputs {
+				next = append(next, output)
+			}
+			previousResponseID = result.Response.ID
+		}
+	}
+
+	return fail(ErrMaxIterationsReached)
+}
+
+// returns the type of the terminal stream event for the status of `response`
+func responseTerminalEventType(response Response) string {
+	switch response.Status {
+	case "failed", "cancelled", "incomplete":
+		return "response." + response.Status
+	}
+	return "response.completed"
+}
+
+// runs function calls in given outputs, and returns their outputs in the same order
+func (r *ResponseToolRunner) runFunctionCalls(ctx context.Context, outputs []ResponseOutput) (results []ResponseFunctionCallOutput) {
+	toolCalls := []ToolCall{}
+	for _, output := range outputs {
+		if output.Type == "function_call" {
+			toolCalls = append(toolCalls, toolCallFromResponseOutput(output))
+		}
+	}
+
+	for i, output := range r.dispatchAll(ctx, toolCalls) {
+		results = append(results, NewResponseFunctionCallOutput(toolCalls[i].ID, output))
+	}
+
+	return results
+}
+
+// a function call which is started while streaming
+type streamedFunctionCall struct {
+	item    ResponseOutput
+	started bool
+	output  string
+}
+
+// creates a streaming response, forwarding events to `cb`, and running function calls as soon as their arguments are done
+func (r *ResponseToolRunner) streamResponse(ctx context.Context, input any, options ResponseOptions, cb responseCallback) (response Response, outputs []ResponseFunctionCallOutput, err error) {
+	var mutex sync.Mutex
+	calls := map[string]*streamedFunctionCall{} // key: item id
+	order := []string{}
+	semaphore := r.semaphore()
+	var wg sync.WaitGroup
+
+	var completed *Response
+	var streamErr error
+	done := make(chan struct{})
+	var once sync.Once
+
+	// starts a function call in background, if not started yet
+	start := func(call *streamedFunctionCall) {
+		if call.started {
+			return
+		}
+		call.started = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			output := r.dispatch(ctx, toolCallFromResponseOutput(call.item))
+
+			mutex.Lock()
+			call.output = output
+			mutex.Unlock()
+		}()
+	}
+
+	if err = r.client.CreateResponseStreamWithContext(ctx, r.model, input, options, func(event ResponseStreamEvent, isDone bool, err error) {
+		if err != nil {
+			streamErr = err
+			once.Do(func() { close(done) })
+			return
+		}
+
+		mutex.Lock()
+		switch event.Type {
+		case "response.output_item.added":
+			if event.Item != nil && event.Item.Type == "function_call" {
+				if _, exists := calls[event.Item.ID]; !exists {
+					calls[event.Item.ID] = &streamedFunctionCall{item: *event.Item}
+					order = append(order, event.Item.ID)
+				}
+			}
+		case "response.function_call_arguments.done":
+			if event.ItemID != nil {
+				if call, exists := calls[*event.ItemID]; exists {
+					if event.Arguments != nil {
+						call.item.Arguments = *event.Arguments
+					}
+					start(call)
+				}
+			}
+		case "response.output_item.done":
+			if event.Item != nil && event.Item.Type == "function_call" {
+				call, exists := calls[event.Item.ID]
+				if !exists {
+					call = &streamedFunctionCall{item: *event.Item}
+					calls[event.Item.ID] = call
+					order = append(order, event.Item.ID)
+				} else if !call.started {
+					call.item = *event.Item
+				}
+				start(call)
+			}
+		}
+		mutex.Unlock()
+
+		if isDone {
+			completed = event.Response
+			once.Do(func() { close(done) })
+			return
+		}
+
+		cb(event, false, nil)
+	}); err != nil {
+		return Response{}, nil, err
+	}
+
+	// the stream always ends with a done or an error callback, even when `ctx` is cancelled,
+	// so no more function calls are started after this
+	<-done
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return Response{}, nil, err
+	}
+	if streamErr != nil {
+		return Response{}, nil, streamErr
+	}
+	if completed == nil {
+		return Response{}, nil, fmt.Errorf("stream was closed before the response was completed")
+	}
+	if completed.Error != nil {
+		return *completed, nil, completed.Error.err()
+	}
+	if completed.Status == "failed" || completed.Status == "cancelled" {
+		return *completed, nil, fmt.Errorf("response '%s' was %s", completed.ID, completed.Status)
+	}
+
+	for _, id := range order {
+		call := calls[id]
+		outputs = append(outputs, NewResponseFunctionCallOutput(call.item.CallID, call.output))
+	}
+
+	return *completed, outputs, nil
+}
+
+// converts a function call output item to a ToolCall
+func toolCallFromResponseOutput(output ResponseOutput) ToolCall {
+	return ToolCall{
+		ID:   output.CallID,
+		Type: "function",
+		Function: ToolCallFunction{
+			Name:      output.Name,
+			Arguments: output.Arguments,
+		},
+	}
+}
+
+// checks if given output item can be sent back as an input item
+//
+// Reasoning items can be sent back only with their encrypted contents, which are included when `store` is false.
+func isReplayableResponseOutput(output ResponseOutput) bool {
+	switch output.Type {
+	case "message", "function_call":
+		return true
+	case "reasoning":
+		return output.EncryptedContent != ""
+	}
+	return false
+}
+
+// reasoning item for input
+type responseReasoningInput struct {
+	Type             string          `json:"type"` // "reasoning"
+	ID               string          `json:"id"`
+	Summary          []OutputContent `json:"summary"`
+	EncryptedContent string          `json:"encrypted_content"`
+}
+
+// converts given output item to an input item
+func responseInputItemFromOutput(output ResponseOutput) any {
+	if output.Type == "reasoning" {
+		summary := output.Summary
+		if summary == nil {
+			summary = []OutputContent{}
+		}
+		return responseReasoningInput{
+			Type:             output.Type,
+			ID:               output.ID,
+			Summary:          summary,
+			EncryptedContent: output.EncryptedContent,
+		}
+	}
+	return output
+}
+
+// converts given input to a list of input items
+func responseInputItems(input any) (items []any) {
+	switch in := input.(type) {
+	case nil:
+		return []any{}
+	case string:
+		return []any{NewResponseMessage("user", in)}
+	case []ResponseMessage:
+		for _, message := range in {
+			items = append(items, message)
+		}
+		return items
+	case []any:
+		return append([]any{}, in...)
+	}
+
+	return []any{input}
+}
@@ -0,0 +1,275 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func responseToolRunnerFixture(client *Client) *ResponseToolRunner {
+	type weatherArgs struct {
+		Location string `json:"location"`
+	}
+
+	return NewResponseToolRunner(client, "gpt-4o").
+		Register(NewResponseTool("get_weather", "Get current weather of a location",
+			NewToolFunctionParameters().
+				AddPropertyWithDescription("location", "string", "City name").
+				SetRequiredParameters([]string{"location"})),
+			NewToolHandler(func(ctx context.Context, args weatherArgs) (any, error) {
+				return fmt.Sprintf("sunny in %s", args.Location), nil
+			}))
+}
+
+func TestResponseToolRunner(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Input              []map[string]any `json:"input"`
+			PreviousResponseID string           `json:"previous_response_id"`
+			Tools              []ResponseTool   `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(requestBody.Tools) != 1 {
+			t.Errorf("Expected 1 tool, got %d", len(requestBody.Tools))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			if len(requestBody.Input) != 1 || requestBody.PreviousResponseID != "" {
+				t.Errorf("Unexpected first request: %+v", requestBody)
+			}
+			w.Write([]byte(`{"id":"resp_1","object":"response","status":"completed","output":[
+				{"id":"fc_1","type":"function_call","status":"completed","call_id":"call_1","name":"get_weather","arguments":"{\"location\":\"Seoul\"}"},
+				{"id":"fc_2","type":"function_call","status":"completed","call_id":"call_2","name":"get_weather","arguments":"{\"location\":\"Tokyo\"}"}
+			]}`))
+		default:
+			if requestBody.PreviousResponseID != "resp_1" {
+				t.Errorf("Expected previous_response_id 'resp_1', got '%s'", requestBody.PreviousResponseID)
+			}
+			if len(requestBody.Input) != 2 {
+				t.Fatalf("Expected 2 function call outputs, got %d", len(requestBody.Input))
+			}
+			if requestBody.Input[0]["call_id"] != "call_1" || requestBody.Input[0]["output"] != "sunny in Seoul" ||
+				requestBody.Input[1]["call_id"] != "call_2" || requestBody.Input[1]["output"] != "sunny in Tokyo" {
+				t.Errorf("Unexpected function call outputs: %+v", requestBody.Input)
+			}
+			w.Write([]byte(`{"id":"resp_2","object":"response","status":"completed","output":[
+				{"id":"msg_1","type":"message","status":"completed","role":"assistant","content":[{"type":"output_text","text":"Both are sunny."}]}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	result, err := responseToolRunnerFixture(client).Run(context.Background(), "Weather in Seoul and Tokyo?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Expected 2 iterations, got %d", result.Iterations)
+	}
+	if result.Response.ID != "resp_2" || result.Response.Output[0].Content[0].Text != "Both are sunny." {
+		t.Errorf("Unexpected final response: %+v", result.Response)
+	}
+	// user message + 2 function calls + 2 outputs + final message
+	if len(result.Input) != 6 {
+		t.Errorf("Expected 6 input items, got %d", len(result.Input))
+	}
+}
+
+func TestResponseToolRunnerStateless(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Input              []map[string]any `json:"input"`
+			PreviousResponseID string           `json:"previous_response_id"`
+			Store              *bool            `json:"store"`
+			Include            []string         `json:"include"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if requestBody.Store == nil || *requestBody.Store {
+			t.Errorf("Expected store to be false")
+		}
+		if len(requestBody.Include) != 1 || requestBody.Include[0] != "reasoning.encrypted_content" {
+			t.Errorf("Expected encrypted reasoning to be included, got %v", requestBody.Include)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Write([]byte(`{"id":"resp_1","object":"response","status":"completed","output":[
+				{"id":"rs_1","type":"reasoning","summary":[],"encrypted_content":"encrypted_1"},
+				{"id":"fc_1","type":"function_call","status":"completed","call_id":"call_1","name":"get_weather","arguments":"{\"location\":\"Seoul\"}"}
+			]}`))
+		default:
+			if requestBody.PreviousResponseID != "" {
+				t.Errorf("Expected no previous_response_id, got '%s'", requestBody.PreviousResponseID)
+			}
+			// replayed: user message + reasoning + function call + its output
+			if len(requestBody.Input) != 4 {
+				t.Errorf("Expected 4 replayed input items, got %d", len(requestBody.Input))
+				return
+			}
+			if requestBody.Input[1]["type"] != "reasoning" || requestBody.Input[2]["type"] != "function_call" || requestBody.Input[3]["type"] != "function_call_output" {
+				t.Errorf("Unexpected replayed input items: %+v", requestBody.Input)
+			}
+			if requestBody.Input[1]["encrypted_content"] != "encrypted_1" || requestBody.Input[1]["summary"] == nil {
+				t.Errorf("Expected reasoning item with its encrypted content and summary, got %+v", requestBody.Input[1])
+			}
+			w.Write([]byte(`{"id":"resp_2","object":"response","status":"completed","output":[
+				{"id":"msg_1","type":"message","status":"completed","role":"assistant","content":[{"type":"output_text","text":"It is sunny."}]}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	result, err := responseToolRunnerFixture(client).
+		SetOptions(ResponseOptions{}.SetStore(false)).
+		Run(context.Background(), []ResponseMessage{NewResponseMessage("user", "Weather in Seoul?")})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Expected 2 iterations, got %d", result.Iterations)
+	}
+}
+
+func TestResponseToolRunnerStream(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			fmt.Fprint(w, "event: response.output_item.added\n"+`data: {"type":"response.output_item.added","output_index":0,"item":{"id":"fc_1","type":"function_call","status":"in_progress","call_id":"call_1","name":"get_weather","arguments":""}}`+"\n\n")
+			fmt.Fprint(w, `data: {"type":"response.function_call_arguments.delta","item_id":"fc_1","output_index":0,"delta":"{\"location\":\"Seoul\"}"}`+"\n\n")
+			fmt.Fprint(w, `data: {"type":"response.function_call_arguments.done","item_id":"fc_1","output_index":0,"arguments":"{\"location\":\"Seoul\"}"}`+"\n\n")
+			fmt.Fprint(w, `data: {"type":"response.completed","response":{"id":"resp_1","status":"completed","output":[{"id":"fc_1","type":"function_call","status":"completed","call_id":"call_1","name":"get_weather","arguments":"{\"location\":\"Seoul\"}"}]}}`+"\n\n")
+		default:
+			var requestBody struct {
+				Input []map[string]any `json:"input"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			if len(requestBody.Input) != 1 || requestBody.Input[0]["output"] != "sunny in Seoul" {
+				t.Errorf("Unexpected input: %+v", requestBody.Input)
+			}
+
+			fmt.Fprint(w, `data: {"type":"response.output_text.delta","item_id":"msg_1","output_index":0,"delta":"It is sunny."}`+"\n\n")
+			fmt.Fprint(w, `data: {"type":"response.completed","response":{"id":"resp_2","status":"completed","output":[{"id":"msg_1","type":"message","status":"completed","role":"assistant","content":[{"type":"output_text","text":"It is sunny."}]}]}}`+"\n\n")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	streamed := ""
+	var final *Response
+	result, err := responseToolRunnerFixture(client).RunStream(context.Background(), "Weather in Seoul?", func(event ResponseStreamEvent, done bool, err error) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		if done {
+			final = event.Response
+			return
+		}
+		if event.Type == "response.output_text.delta" && event.Delta != nil {
+			streamed += *event.Delta
+		}
+	})
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Expected 2 iterations, got %d", result.Iterations)
+	}
+	if streamed != "It is sunny." {
+		t.Errorf("Expected streamed text 'It is sunny.', got '%s'", streamed)
+	}
+	if final == nil || final.ID != "resp_2" {
+		t.Errorf("Expected final response 'resp_2', got %+v", final)
+	}
+}
+
+func TestResponseToolRunnerStreamTerminalEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if r.URL.Path == "/failed/responses" {
+			fmt.Fprint(w, `data: {"type":"response.failed","response":{"id":"resp_1","status":"failed","output":[]}}`+"\n\n")
+			return
+		}
+		fmt.Fprint(w, `data: {"type":"response.incomplete","response":{"id":"resp_1","status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},"output":[{"id":"msg_1","type":"message","status":"incomplete","role":"assistant","content":[{"type":"output_text","text":"It is"}]}]}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// incomplete: forwarded as it is
+	var final ResponseStreamEvent
+	if _, err := responseToolRunnerFixture(client).RunStream(context.Background(), "Weather in Seoul?", func(event ResponseStreamEvent, done bool, err error) {
+		if done {
+			final = event
+		}
+	}); err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	if final.Type != "response.incomplete" || final.Response == nil || final.Response.Status != "incomplete" {
+		t.Errorf("Expected a 'response.incomplete' event, got %+v", final)
+	}
+
+	// failed: surfaced as an error
+	failedURL := server.URL + "/failed"
+	client.baseURL = &failedURL
+	var finalErr error
+	if _, err := responseToolRunnerFixture(client).RunStream(context.Background(), "Weather in Seoul?", func(event ResponseStreamEvent, done bool, err error) {
+		if done {
+			finalErr = err
+		}
+	}); err == nil || finalErr == nil {
+		t.Errorf("Expected an error for a failed response, got %v", err)
+	}
+}
+
+func TestResponseToolRunnerStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 50; i++ {
+			fmt.Fprintf(w, `data: {"type":"response.output_item.done","output_index":%[1]d,"item":{"id":"fc_%[1]d","type":"function_call","status":"completed","call_id":"call_%[1]d","name":"get_weather","arguments":"{\"location\":\"Seoul\"}"}}`+"\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// cancel while function calls are still being started
+	if _, err := responseToolRunnerFixture(client).RunStream(ctx, "Weather in Seoul?", func(event ResponseStreamEvent, done bool, err error) {
+		cancel()
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -22,29 +22,71 @@ const (
 	RunStatusFailed         RunStatus = "failed"
 	RunStatusCompleted      RunStatus = "completed"
 	RunStatusExpired        RunStatus = "expired"
+	RunStatusIncomplete     RunStatus = "incomplete"
 )
 
 // https://platform.openai.com/docs/api-reference/runs/object
 type Run struct {
 	CommonResponse
 
-	ID             string            `json:"id"`
-	CreatedAt      int               `json:"created_at"`
-	ThreadID       string            `json:"thread_id"`
-	AssistantID    string            `json:"assistant_id"`
-	Status         RunStatus         `json:"status"`
-	RequiredAction *RunAction        `json:"required_action,omitempty"`
-	LastError      *RunError         `json:"last_error,omitempty"`
-	ExpiresAt      int               `json:"expires_at"`
-	StartedAt      *int              `json:"started_at,omitempty"`
-	CancelledAt    *int              `json:"cancelled_at,omitempty"`
-	FailedAt       *int              `json:"failed_at,omitempty"`
-	CompletedAt    *int              `json:"completed_at,omitempty"`
-	Model          string            `json:"model"`
-	Instructions   string            `json:"instructions"`
-	Tools          []Tool            `json:"tools"`
-	FileIDs        []string          `json:"file_ids"`
-	Metadata       map[string]string `json:"metadata"`
+	ID                  string                `json:"id"`
+	CreatedAt           int                   `json:"created_at"`
+	ThreadID            string                `json:"thread_id"`
+	AssistantID         string                `json:"assistant_id"`
+	Status              RunStatus             `json:"status"`
+	RequiredAction      *RunAction            `json:"required_action,omitempty"`
+	LastError           *RunError             `json:"last_error,omitempty"`
+	ExpiresAt           int                   `json:"expires_at"`
+	StartedAt           *int                  `json:"started_at,omitempty"`
+	CancelledAt         *int                  `json:"cancelled_at,omitempty"`
+	FailedAt            *int                  `json:"failed_at,omitempty"`
+	CompletedAt         *int                  `json:"completed_at,omitempty"`
+	IncompleteDetails   *RunIncompleteDetails `json:"incomplete_details,omitempty"`
+	Model               string                `json:"model"`
+	Instructions        string                `json:"instructions"`
+	Tools               []Tool                `json:"tools"`
+	Metadata            map[string]string     `json:"metadata"`
+	Usage               *Usage                `json:"usage,omitempty"`
+	Temperature         *float64              `json:"temperature,omitempty"`
+	TopP                *float64              `json:"top_p,omitempty"`
+	MaxPromptTokens     *int                  `json:"max_prompt_tokens,omitempty"`
+	MaxCompletionTokens *int                  `json:"max_completion_tokens,omitempty"`
+	TruncationStrategy  *TruncationStrategy   `json:"truncation_strategy,omitempty"`
+	ToolChoice          any                   `json:"tool_choice,omitempty"` // 'none' | 'auto' | 'required' | object
+	ParallelToolCalls   *bool                 `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat      any                   `json:"response_format,omitempty"` // 'auto' | ChatCompletionResponseFormat
+
+	// Deprecated: `file_ids` was removed in v2.
+	FileIDs []string `json:"file_ids,omitempty"`
+}
+
+// RunIncompleteDetails struct for Run struct
+type RunIncompleteDetails struct {
+	Reason string `json:"reason"` // 'max_completion_tokens' | 'max_prompt_tokens'
+}
+
+// TruncationStrategy struct for controlling how a thread is truncated before a run
+//
+// https://platform.openai.com/docs/api-reference/runs/object#runs/object-truncation_strategy
+type TruncationStrategy struct {
+	Type         string `json:"type"` // 'auto' | 'last_messages'
+	LastMessages *int   `json:"last_messages,omitempty"`
+}
+
+// NewTruncationStrategyAuto returns a TruncationStrategy with type: 'auto'.
+func NewTruncationStrategyAuto() TruncationStrategy {
+	return TruncationStrategy{
+		Type: "auto",
+	}
+}
+
+// NewTruncationStrategyLastMessages returns a TruncationStrategy with type: 'last_messages',
+// which keeps only the most recent `lastMessages` messages.
+func NewTruncationStrategyLastMessages(lastMessages int) TruncationStrategy {
+	return TruncationStrategy{
+		Type:         "last_messages",
+		LastMessages: &lastMessages,
+	}
 }
 
 // RunAction struct for Run struct
@@ -97,6 +139,96 @@ func (o CreateRunOptions) SetTools(tools []Tool) CreateRunOptions {
 	return o
 }
 
+// SetAdditionalInstructions sets the `additional_instructions` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-additional_instructions
+func (o CreateRunOptions) SetAdditionalInstructions(instructions string) CreateRunOptions {
+	o["additional_instructions"] = instructions
+	return o
+}
+
+// SetAdditionalMessages sets the `additional_messages` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-additional_messages
+func (o CreateRunOptions) SetAdditionalMessages(messages []ThreadMessage) CreateRunOptions {
+	o["additional_messages"] = messages
+	return o
+}
+
+// SetTemperature sets the `temperature` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-temperature
+func (o CreateRunOptions) SetTemperature(temperature float64) CreateRunOptions {
+	o["temperature"] = temperature
+	return o
+}
+
+// SetTopP sets the `top_p` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-top_p
+func (o CreateRunOptions) SetTopP(topP float64) CreateRunOptions {
+	o["top_p"] = topP
+	return o
+}
+
+// SetMaxPromptTokens sets the `max_prompt_tokens` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-max_prompt_tokens
+func (o CreateRunOptions) SetMaxPromptTokens(maxPromptTokens int) CreateRunOptions {
+	o["max_prompt_tokens"] = maxPromptTokens
+	return o
+}
+
+// SetMaxCompletionTokens sets the `max_completion_tokens` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-max_completion_tokens
+func (o CreateRunOptions) SetMaxCompletionTokens(maxCompletionTokens int) CreateRunOptions {
+	o["max_completion_tokens"] = maxCompletionTokens
+	return o
+}
+
+// SetTruncationStrategy sets the `truncation_strategy` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-truncation_strategy
+func (o CreateRunOptions) SetTruncationStrategy(strategy TruncationStrategy) CreateRunOptions {
+	o["truncation_strategy"] = strategy
+	return o
+}
+
+// SetParallelToolCalls sets the `parallel_tool_calls` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-parallel_tool_calls
+func (o CreateRunOptions) SetParallelToolCalls(parallel bool) CreateRunOptions {
+	o["parallel_tool_calls"] = parallel
+	return o
+}
+
+// SetResponseFormat sets the `response_format` parameter of CreateRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-response_format
+func (o CreateRunOptions) SetResponseFormat(format ChatCompletionResponseFormat) CreateRunOptions {
+	o["response_format"] = format
+	return o
+}
+
+// SetResponseFormatAuto sets the `response_format` parameter of CreateRunOptions to 'auto'.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-response_format
+func (o CreateRunOptions) SetResponseFormatAuto() CreateRunOptions {
+	o["response_format"] = "auto"
+	return o
+}
+
+// SetToolChoice sets the `tool_choice` parameter of CreateRunOptions.
+//
+// `toolChoice` can be one of 'none', 'auto', 'required', or an object like: {"type": "file_search"}.
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-tool_choice
+func (o CreateRunOptions) SetToolChoice(toolChoice any) CreateRunOptions {
+	o["tool_choice"] = toolChoice
+	return o
+}
+
 // SetMetadata sets the `metadata` parameter of CreateRunOptions.
 //
 // https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-metadata
@@ -365,6 +497,88 @@ func (o CreateThreadAndRunOptions) SetTools(tools []Tool) CreateThreadAndRunOpti
 	return o
 }
 
+// SetToolResources sets the `tool_resources` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-tool_resources
+func (o CreateThreadAndRunOptions) SetToolResources(resources ToolResources) CreateThreadAndRunOptions {
+	o["tool_resources"] = resources
+	return o
+}
+
+// SetTemperature sets the `temperature` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-temperature
+func (o CreateThreadAndRunOptions) SetTemperature(temperature float64) CreateThreadAndRunOptions {
+	o["temperature"] = temperature
+	return o
+}
+
+// SetTopP sets the `top_p` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-top_p
+func (o CreateThreadAndRunOptions) SetTopP(topP float64) CreateThreadAndRunOptions {
+	o["top_p"] = topP
+	return o
+}
+
+// SetMaxPromptTokens sets the `max_prompt_tokens` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-max_prompt_tokens
+func (o CreateThreadAndRunOptions) SetMaxPromptTokens(maxPromptTokens int) CreateThreadAndRunOptions {
+	o["max_prompt_tokens"] = maxPromptTokens
+	return o
+}
+
+// SetMaxCompletionTokens sets the `max_completion_tokens` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-max_completion_tokens
+func (o CreateThreadAndRunOptions) SetMaxCompletionTokens(maxCompletionTokens int) CreateThreadAndRunOptions {
+	o["max_completion_tokens"] = maxCompletionTokens
+	return o
+}
+
+// SetTruncationStrategy sets the `truncation_strategy` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-truncation_strategy
+func (o CreateThreadAndRunOptions) SetTruncationStrategy(strategy TruncationStrategy) CreateThreadAndRunOptions {
+	o["truncation_strategy"] = strategy
+	return o
+}
+
+// SetParallelToolCalls sets the `parallel_tool_calls` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-parallel_tool_calls
+func (o CreateThreadAndRunOptions) SetParallelToolCalls(parallel bool) CreateThreadAndRunOptions {
+	o["parallel_tool_calls"] = parallel
+	return o
+}
+
+// SetResponseFormat sets the `response_format` parameter of CreateThreadAndRunOptions.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-response_format
+func (o CreateThreadAndRunOptions) SetResponseFormat(format ChatCompletionResponseFormat) CreateThreadAndRunOptions {
+	o["response_format"] = format
+	return o
+}
+
+// SetResponseFormatAuto sets the `response_format` parameter of CreateThreadAndRunOptions to 'auto'.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-response_format
+func (o CreateThreadAndRunOptions) SetResponseFormatAuto() CreateThreadAndRunOptions {
+	o["response_format"] = "auto"
+	return o
+}
+
+// SetToolChoice sets the `tool_choice` parameter of CreateThreadAndRunOptions.
+//
+// `toolChoice` can be one of 'none', 'auto', 'required', or an object like: {"type": "file_search"}.
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-tool_choice
+func (o CreateThreadAndRunOptions) SetToolChoice(toolChoice any) CreateThreadAndRunOptions {
+	o["tool_choice"] = toolChoice
+	return o
+}
+
 // SetMetadata sets the `metadata` parameter of CreateThreadAndRunOptions.
 //
 // https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-metadata
@@ -375,16 +589,20 @@ func (o CreateThreadAndRunOptions) SetMetadata(metadata map[string]string) Creat
 
 // RunnableThread struct for CreateThreadAndRunOptions
 type RunnableThread struct {
-	Messages []RunnableThreadMessage `json:"messages,omitempty"`
-	Metadata map[string]string       `json:"metadata,omitempty"`
+	Messages      []RunnableThreadMessage `json:"messages,omitempty"`
+	ToolResources *ToolResources          `json:"tool_resources,omitempty"`
+	Metadata      map[string]string       `json:"metadata,omitempty"`
 }
 
 // RunnableThreadMessage struct for RunnableThread struct
 type RunnableThreadMessage struct {
-	Role     string            `json:"role"` // == 'user'
-	Content  string            `json:"content"`
-	FileIDs  []string          `json:"file_ids,omitempty"`
-	Metadata map[string]string `json:"metadata,omitempty"`
+	Role        string              `json:"role"`    // 'user' | 'assistant'
+	Content     any                 `json:"content"` // string | []MessageContentPart
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Metadata    map[string]string   `json:"metadata,omitempty"`
+
+	// Deprecated: `file_ids` was removed in v2, use `Attachments` instead.
+	FileIDs []string `json:"file_ids,omitempty"`
 }
 
 // CreateThreadAndRun creates a thread and runs it with given `assistantID` and `options`.
@@ -455,8 +673,11 @@ type RunStepDetailsToolCall struct {
 	Type string `json:"type"`
 
 	CodeInterpreter *RunStepDetailsToolCallCodeInterpreter `json:"code_interpreter,omitempty"` // Type == ToolTypeCodeInterpreter
-	Retrieval       *RunStepDetailsToolCallRetrieval       `json:"retrieval,omitempty"`        // Type -== ToolTypeRetrieval
+	FileSearch      *RunStepDetailsToolCallFileSearch      `json:"file_search,omitempty"`      // Type == 'file_search'
 	Function        *RunStepDetailsToolCallFunction        `json:"function,omitempty"`         // Type == ToolTypeFunction
+
+	// Deprecated: 'retrieval' tool was replaced with 'file_search' in v2.
+	Retrieval *RunStepDetailsToolCallRetrieval `json:"retrieval,omitempty"`
 }
 
 // RunStepDetailsToolCallCodeInterpreter struct for RunStepDetailsToolCall struct
@@ -490,6 +711,23 @@ type ToolCallCodeInterpreterOutput struct {
 // RunStepDetailsToolCallRetrieval struct for RunStepDetailsToolCall struct (empty object for now)
 type RunStepDetailsToolCallRetrieval struct{}
 
+// RunStepDetailsToolCallFileSearch struct for RunStepDetailsToolCall struct
+type RunStepDetailsToolCallFileSearch struct {
+	RankingOptions *ToolFileSearchRankingOptions    `json:"ranking_options,omitempty"`
+	Results        []RunStepDetailsFileSearchResult `json:"results,omitempty"` // with `include[]=step_details.tool_calls[*].file_search.results[*].content`
+}
+
+// RunStepDetailsFileSearchResult struct for RunStepDetailsToolCallFileSearch struct
+type RunStepDetailsFileSearchResult struct {
+	FileID   string  `json:"file_id"`
+	FileName string  `json:"file_name"`
+	Score    float64 `json:"score"`
+	Content  []struct {
+		Type string `json:"type"` // == 'text'
+		Text string `json:"text"`
+	} `json:"content,omitempty"`
+}
+
 // RunStepDetailsToolCallsFunction struct for RunStepDetailsToolCall struct
 type RunStepDetailsToolCallFunction struct {
 	Name      string  `json:"name"`
@@ -515,6 +753,7 @@ type RunStep struct {
 	FailedAt    *int              `json:"failed_at,omitempty"`
 	CompletedAt *int              `json:"completed_at,omitempty"`
 	Metadata    map[string]string `json:"metadata"`
+	Usage       *Usage            `json:"usage,omitempty"`
 }
 
 // RetrieveRunStep retrieves a run step with given `threadID`, `runID` and `stepID`.
@@ -0,0 +1,163 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NOTE: In Beta
+
+// helpers for polling runs until they finish, running required tool calls automatically
+
+const (
+	defaultRunPollInitialInterval = 500 * time.Millisecond
+	defaultRunPollMaxInterval     = 5 * time.Second
+
+	runPollMessagesLimit = 100
+)
+
+// IsTerminal returns if the status is a final one.
+func (s RunStatus) IsTerminal() bool {
+	switch s {
+	case RunStatusCompleted, RunStatusIncomplete, RunStatusFailed, RunStatusCanceled, RunStatusExpired:
+		return true
+	}
+	return false
+}
+
+// RunPollOptions struct for creating and polling runs
+type RunPollOptions struct {
+	// options for creating the run
+	Run CreateRunOptions
+
+	// polling interval at first, and right after tool outputs are submitted (default: 500 milliseconds)
+	InitialInterval time.Duration
+
+	// polling interval doubles up to this value (default: 5 seconds)
+	MaxInterval time.Duration
+
+	// maximum number of tool calls running in parallel (default: 4)
+	MaxConcurrency int
+}
+
+// RunPollResult struct for the result of a polled run
+type RunPollResult struct {
+	// the final state of the run
+	Run Run
+
+	// messages created by the run, in chronological order
+	Messages []Message
+}
+
+// CreateRunAndPoll creates a run with given `threadID`, `assistantID`, and `options`,
+// and polls it until it reaches a terminal status.
+//
+// When the run requires action, its tool calls are run with `handlers` (key: function name)
+// and their outputs are submitted with `SubmitToolOutputs`.
+// Errors of handlers are submitted as outputs, so the model can see them.
+//
+// If `ctx` is cancelled or expired, the run is cancelled with `CancelRun`.
+//
+// The returned run can be failed, cancelled, expired, or incomplete, so check its `Status`.
+func (c *Client) CreateRunAndPoll(ctx context.Context, threadID, assistantID string, options RunPollOptions, handlers map[string]ToolHandler) (result RunPollResult, err error) {
+	if result.Run, err = c.CreateRun(threadID, assistantID, options.Run); err != nil {
+		return result, fmt.Errorf("failed to create run: %s", err)
+	}
+
+	return c.PollRun(ctx, result.Run, options, handlers)
+}
+
+// PollRun polls given `run` until it reaches a terminal status, in the same way as `CreateRunAndPoll`.
+//
+// `options.Run` is ignored.
+func (c *Client) PollRun(ctx context.Context, run Run, options RunPollOptions, handlers map[string]ToolHandler) (result RunPollResult, err error) {
+	initial := options.InitialInterval
+	if initial <= 0 {
+		initial = defaultRunPollInitialInterval
+	}
+	maxInterval := options.MaxInterval
+	if maxInterval < initial {
+		maxInterval = defaultRunPollMaxInterval
+		if maxInterval < initial {
+			maxInterval = initial
+		}
+	}
+
+	dispatcher := newToolDispatcher()
+	for name, handler := range handlers {
+		dispatcher.handlers[name] = handler
+	}
+	if options.MaxConcurrency > 0 {
+		dispatcher.maxConcurrency = options.MaxConcurrency
+	}
+
+	result.Run = run
+	interval := initial
+	for !result.Run.Status.IsTerminal() {
+		if result.Run.Status == RunStatusRequiresAction &&
+			result.Run.RequiredAction != nil &&
+			result.Run.RequiredAction.Type == "submit_tool_outputs" {
+			toolCalls := result.Run.RequiredAction.SubmitToolOutputs.ToolCalls
+
+			outputs := []ToolOutput{}
+			for i, output := range dispatcher.dispatchAll(ctx, toolCalls) {
+				id, output := toolCalls[i].ID, output
+				outputs = append(outputs, ToolOutput{ToolCallID: &id, Output: &output})
+			}
+
+			if err = ctx.Err(); err != nil {
+				return c.cancelPolledRun(result, err)
+			}
+
+			if result.Run, err = c.SubmitToolOutputs(result.Run.ThreadID, result.Run.ID, outputs); err != nil {
+				return result, fmt.Errorf("failed to submit tool outputs: %s", err)
+			}
+			interval = initial
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return c.cancelPolledRun(result, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		if result.Run, err = c.RetrieveRun(run.ThreadID, run.ID); err != nil {
+			return result, fmt.Errorf("failed to retrieve run: %s", err)
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+
+	// fetch messages created by the run
+	listOptions := ListMessagesOptions{}.SetRunID(run.ID).SetOrder("asc").SetLimit(runPollMessagesLimit)
+	for {
+		var listed Messages
+		if listed, err = c.ListMessages(run.ThreadID, listOptions); err != nil {
+			return result, fmt.Errorf("failed to list messages of run: %s", err)
+		}
+		result.Messages = append(result.Messages, listed.Data...)
+
+		if !listed.HasMore || len(listed.Data) <= 0 {
+			break
+		}
+		listOptions.SetAfter(listed.LastID)
+	}
+
+	return result, nil
+}
+
+// cancels a polled run after its context is done, and returns the last state of it with `err`
+func (c *Client) cancelPolledRun(result RunPollResult, err error) (RunPollResult, error) {
+	if cancelled, e := c.CancelRun(result.Run.ThreadID, result.Run.ID); e == nil {
+		result.Run = cancelled
+	} else {
+		err = fmt.Errorf("%w (and failed to cancel run: %s)", err, e)
+	}
+
+	return result, err
+}
@@ -0,0 +1,129 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateRunAndPollMock(t *testing.T) {
+	var mutex sync.Mutex
+	retrieved := 0
+	var submitted []ToolOutput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/threads/thread_1/runs":
+			w.Write([]byte(`{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"queued"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/threads/thread_1/runs/run_1":
+			retrieved++
+			switch retrieved {
+			case 1:
+				w.Write([]byte(`{"id":"run_1","thread_id":"thread_1","status":"requires_action","required_action":{"type":"submit_tool_outputs","submit_tool_outputs":{"tool_calls":[` +
+					`{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Seoul\"}"}},` +
+					`{"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}}`))
+			default:
+				w.Write([]byte(`{"id":"run_1","thread_id":"thread_1","status":"completed"}`))
+			}
+		case r.URL.Path == "/threads/thread_1/runs/run_1/submit_tool_outputs":
+			var body struct {
+				ToolOutputs []ToolOutput `json:"tool_outputs"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			submitted = body.ToolOutputs
+			w.Write([]byte(`{"id":"run_1","thread_id":"thread_1","status":"in_progress"}`))
+		case r.URL.Path == "/threads/thread_1/messages":
+			if r.URL.Query().Get("run_id") != "run_1" {
+				t.Errorf("Expected run_id filter, got '%s'", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"object":"list","data":[{"id":"msg_1","role":"assistant","run_id":"run_1","content":[{"type":"text","text":{"value":"It is sunny.","annotations":[]}}]}],"first_id":"msg_1","last_id":"msg_1","has_more":false}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	result, err := client.CreateRunAndPoll(context.Background(), "thread_1", "asst_1", RunPollOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+	}, map[string]ToolHandler{
+		"get_weather": func(ctx context.Context, call ToolCall) (string, error) {
+			return "sunny", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateRunAndPoll failed: %v", err)
+	}
+
+	if result.Run.Status != RunStatusCompleted {
+		t.Errorf("Expected completed run, got %s", result.Run.Status)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Content[0].Text.Value != "It is sunny." {
+		t.Errorf("Unexpected messages: %+v", result.Messages)
+	}
+	if len(submitted) != 2 {
+		t.Fatalf("Expected 2 tool outputs, got %d", len(submitted))
+	}
+	if *submitted[0].ToolCallID != "call_1" || *submitted[0].Output != "sunny" {
+		t.Errorf("Unexpected tool output: %s = %s", *submitted[0].ToolCallID, *submitted[0].Output)
+	}
+	if *submitted[1].ToolCallID != "call_2" || *submitted[1].Output != "error: no such tool: `get_time`" {
+		t.Errorf("Unexpected tool output: %s = %s", *submitted[1].ToolCallID, *submitted[1].Output)
+	}
+}
+
+func TestCreateRunAndPollCancelledContext(t *testing.T) {
+	var mutex sync.Mutex
+	cancelled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/thread_1/runs/run_1/cancel":
+			cancelled = true
+			w.Write([]byte(`{"id":"run_1","thread_id":"thread_1","status":"cancelling"}`))
+		default:
+			fmt.Fprint(w, `{"id":"run_1","thread_id":"thread_1","status":"in_progress"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := client.CreateRunAndPoll(ctx, "thread_1", "asst_1", RunPollOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     2 * time.Millisecond,
+	}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !cancelled {
+		t.Errorf("Expected the run to be cancelled")
+	}
+	if result.Run.Status != RunStatusCanceling {
+		t.Errorf("Expected cancelling run, got %s", result.Run.Status)
+	}
+}
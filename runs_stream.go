@@ -0,0 +1,558 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NOTE: In Beta
+
+// streaming runs with server-sent events
+//
+// https://platform.openai.com/docs/api-reference/assistants-streaming
+
+// RunStreamEventType type for constants
+type RunStreamEventType string
+
+// RunStreamEventType constants
+const (
+	RunStreamEventThreadCreated = RunStreamEventType("thread.created")
+
+	RunStreamEventRunCreated        = RunStreamEventType("thread.run.created")
+	RunStreamEventRunQueued         = RunStreamEventType("thread.run.queued")
+	RunStreamEventRunInProgress     = RunStreamEventType("thread.run.in_progress")
+	RunStreamEventRunRequiresAction = RunStreamEventType("thread.run.requires_action")
+	RunStreamEventRunCompleted      = RunStreamEventType("thread.run.completed")
+	RunStreamEventRunIncomplete     = RunStreamEventType("thread.run.incomplete")
+	RunStreamEventRunFailed         = RunStreamEventType("thread.run.failed")
+	RunStreamEventRunCancelling     = RunStreamEventType("thread.run.cancelling")
+	RunStreamEventRunCancelled      = RunStreamEventType("thread.run.cancelled")
+	RunStreamEventRunExpired        = RunStreamEventType("thread.run.expired")
+
+	RunStreamEventRunStepCreated    = RunStreamEventType("thread.run.step.created")
+	RunStreamEventRunStepInProgress = RunStreamEventType("thread.run.step.in_progress")
+	RunStreamEventRunStepDelta      = RunStreamEventType("thread.run.step.delta")
+	RunStreamEventRunStepCompleted  = RunStreamEventType("thread.run.step.completed")
+	RunStreamEventRunStepFailed     = RunStreamEventType("thread.run.step.failed")
+	RunStreamEventRunStepCancelled  = RunStreamEventType("thread.run.step.cancelled")
+	RunStreamEventRunStepExpired    = RunStreamEventType("thread.run.step.expired")
+
+	RunStreamEventMessageCreated    = RunStreamEventType("thread.message.created")
+	RunStreamEventMessageInProgress = RunStreamEventType("thread.message.in_progress")
+	RunStreamEventMessageDelta      = RunStreamEventType("thread.message.delta")
+	RunStreamEventMessageCompleted  = RunStreamEventType("thread.message.completed")
+	RunStreamEventMessageIncomplete = RunStreamEventType("thread.message.incomplete")
+
+	RunStreamEventError = RunStreamEventType("error")
+	RunStreamEventDone  = RunStreamEventType("done")
+)
+
+// RunStreamEvent struct for events of streamed runs
+//
+// Only one of the pointer fields is set, according to `Type`.
+type RunStreamEvent struct {
+	Type RunStreamEventType
+
+	Thread       *Thread       // thread.created
+	Run          *Run          // thread.run.*
+	RunStep      *RunStep      // thread.run.step.* (except delta)
+	RunStepDelta *RunStepDelta // thread.run.step.delta
+	Message      *Message      // thread.message.* (except delta)
+	MessageDelta *MessageDelta // thread.message.delta
+	Error        *Error        // error
+}
+
+type runStreamCallback func(event RunStreamEvent, done bool, err error)
+
+// MessageDelta struct for `thread.message.delta` events
+//
+// https://platform.openai.com/docs/api-reference/assistants-streaming/message-delta-object
+type MessageDelta struct {
+	ID    string `json:"id"`
+	Delta struct {
+		Role    string                `json:"role,omitempty"`
+		Content []MessageContentDelta `json:"content,omitempty"`
+	} `json:"delta"`
+}
+
+// MessageContentDelta struct for MessageDelta
+type MessageContentDelta struct {
+	Index int                `json:"index"`
+	Type  MessageContentType `json:"type"`
+
+	ImageFile *MessageContentImageFile `json:"image_file,omitempty"` // Type == 'image_file'
+	ImageURL  *MessageContentImageURL  `json:"image_url,omitempty"`  // Type == 'image_url'
+	Text      *MessageContentTextDelta `json:"text,omitempty"`       // Type == 'text'
+	Refusal   *string                  `json:"refusal,omitempty"`    // Type == 'refusal'
+}
+
+// MessageContentTextDelta struct for MessageContentDelta
+type MessageContentTextDelta struct {
+	Value       *string                             `json:"value,omitempty"`
+	Annotations []MessageContentTextAnnotationDelta `json:"annotations,omitempty"`
+}
+
+// MessageContentTextAnnotationDelta struct for MessageContentTextDelta
+type MessageContentTextAnnotationDelta struct {
+	Index int `json:"index"`
+
+	MessageContentTextAnnotation
+}
+
+// RunStepDelta struct for `thread.run.step.delta` events
+//
+// https://platform.openai.com/docs/api-reference/assistants-streaming/run-step-delta-object
+type RunStepDelta struct {
+	ID    string `json:"id"`
+	Delta struct {
+		StepDetails RunStepDetailsDelta `json:"step_details"`
+	} `json:"delta"`
+}
+
+// RunStepDetailsDelta struct for RunStepDelta
+type RunStepDetailsDelta struct {
+	Type RunStepType `json:"type"`
+
+	MessageCreation *RunStepDetailsMessageCreation `json:"message_creation,omitempty"` // Type == RunStepTypeMessageCreation
+	ToolCalls       []RunStepDetailsToolCallDelta  `json:"tool_calls,omitempty"`       // Type == RunStepTypeToolCalls
+}
+
+// RunStepDetailsToolCallDelta struct for RunStepDetailsDelta
+type RunStepDetailsToolCallDelta struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type,omitempty"`
+
+	CodeInterpreter *RunStepDetailsToolCallCodeInterpreterDelta `json:"code_interpreter,omitempty"`
+	FileSearch      *RunStepDetailsToolCallFileSearch           `json:"file_search,omitempty"`
+	Function        *RunStepDetailsToolCallFunction             `json:"function,omitempty"`
+}
+
+// RunStepDetailsToolCallCodeInterpreterDelta struct for RunStepDetailsToolCallDelta
+type RunStepDetailsToolCallCodeInterpreterDelta struct {
+	Input   string                               `json:"input,omitempty"`
+	Outputs []ToolCallCodeInterpreterOutputDelta `json:"outputs,omitempty"`
+}
+
+// ToolCallCodeInterpreterOutputDelta struct for RunStepDetailsToolCallCodeInterpreterDelta
+type ToolCallCodeInterpreterOutputDelta struct {
+	Index int `json:"index"`
+
+	ToolCallCodeInterpreterOutput
+}
+
+// AccumulateMessageContentDeltas applies `deltas` to `contents` and returns the result.
+//
+// Text values are concatenated, and annotations are added or replaced by their indices.
+func AccumulateMessageContentDeltas(contents []MessageContent, deltas []MessageContentDelta) []MessageContent {
+	for _, delta := range deltas {
+		for len(contents) <= delta.Index {
+			contents = append(contents, MessageContent{})
+		}
+		content := &contents[delta.Index]
+
+		if delta.Type != "" {
+			content.Type = delta.Type
+		}
+		if delta.ImageFile != nil {
+			content.ImageFile = delta.ImageFile
+		}
+		if delta.ImageURL != nil {
+			content.ImageURL = delta.ImageURL
+		}
+		if delta.Refusal != nil {
+			refusal := *delta.Refusal
+			if content.Refusal != nil {
+				refusal = *content.Refusal + refusal
+			}
+			content.Refusal = &refusal
+		}
+		if delta.Text != nil {
+			if content.Text == nil {
+				content.Text = &MessageContentText{Annotations: []MessageContentTextAnnotation{}}
+			}
+			if delta.Text.Value != nil {
+				content.Text.Value += *delta.Text.Value
+			}
+			for _, annotation := range delta.Text.Annotations {
+				for len(content.Text.Annotations) <= annotation.Index {
+					content.Text.Annotations = append(content.Text.Annotations, MessageContentTextAnnotation{})
+				}
+				content.Text.Annotations[annotation.Index] = annotation.MessageContentTextAnnotation
+			}
+		}
+	}
+
+	return contents
+}
+
+// AccumulateRunStepDetailsDelta applies `delta` to `details` and returns the result.
+//
+// Code interpreter inputs and function arguments are concatenated, and outputs are added by their indices.
+func AccumulateRunStepDetailsDelta(details RunStepDetails, delta RunStepDetailsDelta) RunStepDetails {
+	if delta.Type != "" {
+		details.Type = delta.Type
+	}
+	if delta.MessageCreation != nil {
+		details.MessageCreation = delta.MessageCreation
+	}
+
+	for _, d := range delta.ToolCalls {
+		for len(details.ToolCalls) <= d.Index {
+			details.ToolCalls = append(details.ToolCalls, RunStepDetailsToolCall{})
+		}
+		call := &details.ToolCalls[d.Index]
+
+		if d.ID != "" {
+			call.ID = d.ID
+		}
+		if d.Type != "" {
+			call.Type = d.Type
+		}
+		if d.CodeInterpreter != nil {
+			if call.CodeInterpreter == nil {
+				call.CodeInterpreter = &RunStepDetailsToolCallCodeInterpreter{Outputs: []ToolCallCodeInterpreterOutput{}}
+			}
+			call.CodeInterpreter.Input += d.CodeInterpreter.Input
+			for _, output := range d.CodeInterpreter.Outputs {
+				for len(call.CodeInterpreter.Outputs) <= output.Index {
+					call.CodeInterpreter.Outputs = append(call.CodeInterpreter.Outputs, ToolCallCodeInterpreterOutput{})
+				}
+				call.CodeInterpreter.Outputs[output.Index] = output.ToolCallCodeInterpreterOutput
+			}
+		}
+		if d.FileSearch != nil {
+			call.FileSearch = d.FileSearch
+		}
+		if d.Function != nil {
+			if call.Function == nil {
+				call.Function = &RunStepDetailsToolCallFunction{}
+			}
+			call.Function.Name += d.Function.Name
+			call.Function.Arguments += d.Function.Arguments
+			if d.Function.Output != nil {
+				call.Function.Output = d.Function.Output
+			}
+		}
+	}
+
+	return details
+}
+
+// RunStreamAccumulator accumulates events of streamed runs into runs, run steps, and messages.
+type RunStreamAccumulator struct {
+	run *Run
+
+	steps     map[string]*RunStep
+	stepOrder []string
+
+	messages     map[string]*Message
+	messageOrder []string
+}
+
+// NewRunStreamAccumulator returns a new RunStreamAccumulator.
+func NewRunStreamAccumulator() *RunStreamAccumulator {
+	return &RunStreamAccumulator{
+		steps:    map[string]*RunStep{},
+		messages: map[string]*Message{},
+	}
+}
+
+// Add applies given `event`.
+func (a *RunStreamAccumulator) Add(event RunStreamEvent) {
+	switch {
+	case event.Run != nil:
+		run := *event.Run
+		a.run = &run
+	case event.RunStep != nil:
+		step := a.step(event.RunStep.ID)
+		*step = *event.RunStep
+	case event.RunStepDelta != nil:
+		step := a.step(event.RunStepDelta.ID)
+		step.StepDetails = AccumulateRunStepDetailsDelta(step.StepDetails, event.RunStepDelta.Delta.StepDetails)
+	case event.Message != nil:
+		message := a.message(event.Message.ID)
+		contents := message.Content
+		*message = *event.Message
+		if len(message.Content) <= 0 {
+			message.Content = contents // keep accumulated contents if not given
+		}
+	case event.MessageDelta != nil:
+		message := a.message(event.MessageDelta.ID)
+		if event.MessageDelta.Delta.Role != "" {
+			message.Role = event.MessageDelta.Delta.Role
+		}
+		message.Content = AccumulateMessageContentDeltas(message.Content, event.MessageDelta.Delta.Content)
+	}
+}
+
+// Run returns the last state of the run, or nil if no run event was added.
+func (a *RunStreamAccumulator) Run() *Run {
+	return a.run
+}
+
+// RunSteps returns accumulated run steps in the order of their first appearances.
+func (a *RunStreamAccumulator) RunSteps() (steps []RunStep) {
+	for _, id := range a.stepOrder {
+		steps = append(steps, *a.steps[id])
+	}
+	return steps
+}
+
+// Messages returns accumulated messages in the order of their first appearances.
+func (a *RunStreamAccumulator) Messages() (messages []Message) {
+	for _, id := range a.messageOrder {
+		messages = append(messages, *a.messages[id])
+	}
+	return messages
+}
+
+// Text returns the concatenated text values of the message with given `messageID`.
+func (a *RunStreamAccumulator) Text(messageID string) string {
+	message, exists := a.messages[messageID]
+	if !exists {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, content := range message.Content {
+		if content.Text != nil {
+			builder.WriteString(content.Text.Value)
+		}
+	}
+	return builder.String()
+}
+
+// returns the run step with given id, creating it if needed
+func (a *RunStreamAccumulator) step(id string) *RunStep {
+	if step, exists := a.steps[id]; exists {
+		return step
+	}
+	step := &RunStep{ID: id}
+	a.steps[id] = step
+	a.stepOrder = append(a.stepOrder, id)
+	return step
+}
+
+// returns the message with given id, creating it if needed
+func (a *RunStreamAccumulator) message(id string) *Message {
+	if message, exists := a.messages[id]; exists {
+		return message
+	}
+	message := &Message{ID: id}
+	a.messages[id] = message
+	a.messageOrder = append(a.messageOrder, id)
+	return message
+}
+
+// parses the data of a run stream event with given `name`
+func parseRunStreamEvent(name string, data []byte) (event RunStreamEvent, err error) {
+	event.Type = RunStreamEventType(name)
+
+	var target any
+	switch {
+	case event.Type == RunStreamEventDone:
+		return event, nil
+	case event.Type == RunStreamEventError:
+		event.Error = &Error{}
+		target = event.Error
+	case event.Type == RunStreamEventThreadCreated:
+		event.Thread = &Thread{}
+		target = event.Thread
+	case event.Type == RunStreamEventRunStepDelta:
+		event.RunStepDelta = &RunStepDelta{}
+		target = event.RunStepDelta
+	case strings.HasPrefix(name, "thread.run.step."):
+		event.RunStep = &RunStep{}
+		target = event.RunStep
+	case strings.HasPrefix(name, "thread.run."):
+		event.Run = &Run{}
+		target = event.Run
+	case event.Type == RunStreamEventMessageDelta:
+		event.MessageDelta = &MessageDelta{}
+		target = event.MessageDelta
+	case strings.HasPrefix(name, "thread.message."):
+		event.Message = &Message{}
+		target = event.Message
+	default:
+		return event, nil // unknown events are passed as they are
+	}
+
+	if err = json.Unmarshal(data, target); err != nil {
+		return event, fmt.Errorf("failed to parse '%s' event: %s", name, err)
+	}
+
+	// error can be wrapped in an `error` property
+	if event.Error != nil && event.Error.Message == "" {
+		var wrapped CommonResponse
+		if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.Error != nil {
+			event.Error = wrapped.Error
+		}
+	}
+
+	return event, nil
+}
+
+// sends HTTP POST request for a streamed run, and calls `cb` with its events in background
+func (c *Client) postCBRunsWithContext(ctx context.Context, endpoint string, params map[string]any, cb runStreamCallback) (err error) {
+	resp, err := c.openStreamWithContext(ctx, endpoint, params)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer resp.Body.Close()
+
+		finished := false
+		err := readServerSentEvents(ctx, resp.Body, func(name string, data []byte) bool {
+			event, err := parseRunStreamEvent(name, data)
+			if err != nil {
+				finished = true
+				cb(event, true, err)
+				return false
+			}
+
+			switch event.Type {
+			case RunStreamEventDone:
+				finished = true
+				cb(event, true, nil)
+				return false
+			case RunStreamEventError:
+				finished = true
+				cb(event, true, event.Error.err())
+				return false
+			}
+
+			cb(event, false, nil)
+			return true
+		})
+		if !finished {
+			// stream was closed with an error, or without a `done` event
+			cb(RunStreamEvent{}, true, err)
+		}
+	}()
+
+	return nil
+}
+
+// CreateRunStream creates a run with given `threadID`, `assistantID`, and `options`, and streams its events to `cb`.
+//
+// `cb` is called with `done` == true on the last event (`done` or `error`).
+//
+// https://platform.openai.com/docs/api-reference/runs/createRun#runs-createrun-stream
+func (c *Client) CreateRunStream(threadID, assistantID string, options CreateRunOptions, cb runStreamCallback) (err error) {
+	return c.CreateRunStreamWithContext(context.Background(), threadID, assistantID, options, cb)
+}
+
+// CreateRunStreamWithContext does the same as `CreateRunStream` with given `ctx`.
+func (c *Client) CreateRunStreamWithContext(ctx context.Context, threadID, assistantID string, options CreateRunOptions, cb runStreamCallback) (err error) {
+	if options == nil {
+		options = CreateRunOptions{}
+	}
+	options["assistant_id"] = assistantID
+	options["stream"] = true
+
+	return c.postCBRunsWithContext(ctx, fmt.Sprintf("threads/%s/runs", threadID), options, cb)
+}
+
+// CreateThreadAndRunStream creates a thread, runs it with given `assistantID` and `options`, and streams its events to `cb`.
+//
+// `cb` is called with `done` == true on the last event (`done` or `error`).
+//
+// https://platform.openai.com/docs/api-reference/runs/createThreadAndRun#runs-createthreadandrun-stream
+func (c *Client) CreateThreadAndRunStream(assistantID string, options CreateThreadAndRunOptions, cb runStreamCallback) (err error) {
+	return c.CreateThreadAndRunStreamWithContext(context.Background(), assistantID, options, cb)
+}
+
+// CreateThreadAndRunStreamWithContext does the same as `CreateThreadAndRunStream` with given `ctx`.
+func (c *Client) CreateThreadAndRunStreamWithContext(ctx context.Context, assistantID string, options CreateThreadAndRunOptions, cb runStreamCallback) (err error) {
+	if options == nil {
+		options = CreateThreadAndRunOptions{}
+	}
+	options["assistant_id"] = assistantID
+	options["stream"] = true
+
+	return c.postCBRunsWithContext(ctx, "threads/runs", options, cb)
+}
+
+// SubmitToolOutputsStream submits tool outputs with given `threadID` and `runID`, and streams the continued run's events to `cb`.
+//
+// https://platform.openai.com/docs/api-reference/runs/submitToolOutputs#runs-submittooloutputs-stream
+func (c *Client) SubmitToolOutputsStream(threadID, runID string, toolOutputs []ToolOutput, cb runStreamCallback) (err error) {
+	return c.SubmitToolOutputsStreamWithContext(context.Background(), threadID, runID, toolOutputs, cb)
+}
+
+// SubmitToolOutputsStreamWithContext does the same as `SubmitToolOutputsStream` with given `ctx`.
+func (c *Client) SubmitToolOutputsStreamWithContext(ctx context.Context, threadID, runID string, toolOutputs []ToolOutput, cb runStreamCallback) (err error) {
+	return c.postCBRunsWithContext(ctx, fmt.Sprintf("threads/%s/runs/%s/submit_tool_outputs", threadID, runID), map[string]any{
+		"tool_outputs": toolOutputs,
+		"stream":       true,
+	}, cb)
+}
+
+// RunToolOutputsFunc type for generating tool outputs of a run which requires action
+type RunToolOutputsFunc func(ctx context.Context, run Run) ([]ToolOutput, error)
+
+// CreateRunStreamWithToolOutputs does the same as `CreateRunStreamWithContext`,
+// but when the run requires action, submits tool outputs generated by `toolOutputs`
+// and keeps streaming the continued run's events to `cb`.
+//
+// `cb` is called with `done` == true only once, on the last event of the whole run.
+func (c *Client) CreateRunStreamWithToolOutputs(ctx context.Context, threadID, assistantID string, options CreateRunOptions, toolOutputs RunToolOutputsFunc, cb runStreamCallback) (err error) {
+	return c.CreateRunStreamWithContext(ctx, threadID, assistantID, options, c.continuingRunStreamCallback(ctx, toolOutputs, cb))
+}
+
+// CreateThreadAndRunStreamWithToolOutputs does the same as `CreateThreadAndRunStreamWithContext`,
+// but when the run requires action, submits tool outputs generated by `toolOutputs`
+// and keeps streaming the continued run's events to `cb`.
+//
+// `cb` is called with `done` == true only once, on the last event of the whole run.
+func (c *Client) CreateThreadAndRunStreamWithToolOutputs(ctx context.Context, assistantID string, options CreateThreadAndRunOptions, toolOutputs RunToolOutputsFunc, cb runStreamCallback) (err error) {
+	return c.CreateThreadAndRunStreamWithContext(ctx, assistantID, options, c.continuingRunStreamCallback(ctx, toolOutputs, cb))
+}
+
+// returns a callback which submits tool outputs when a run requires action, and continues streaming with it
+func (c *Client) continuingRunStreamCallback(ctx context.Context, toolOutputs RunToolOutputsFunc, cb runStreamCallback) runStreamCallback {
+	var mutex sync.Mutex
+	var requiresAction *Run
+
+	var continuing runStreamCallback
+	continuing = func(event RunStreamEvent, done bool, err error) {
+		if event.Type == RunStreamEventRunRequiresAction && event.Run != nil {
+			mutex.Lock()
+			run := *event.Run
+			requiresAction = &run
+			mutex.Unlock()
+		}
+
+		if !done || err != nil {
+			cb(event, done, err)
+			return
+		}
+
+		mutex.Lock()
+		run := requiresAction
+		requiresAction = nil
+		mutex.Unlock()
+
+		if run == nil {
+			cb(event, true, nil)
+			return
+		}
+
+		// submit tool outputs and continue
+		outputs, err := toolOutputs(ctx, *run)
+		if err != nil {
+			cb(RunStreamEvent{}, true, fmt.Errorf("failed to generate tool outputs: %s", err))
+			return
+		}
+		if err := c.SubmitToolOutputsStreamWithContext(ctx, run.ThreadID, run.ID, outputs, continuing); err != nil {
+			cb(RunStreamEvent{}, true, fmt.Errorf("failed to submit tool outputs: %s", err))
+		}
+	}
+
+	return continuing
+}
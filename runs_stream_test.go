@@ -0,0 +1,190 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writes server-sent events of a run stream
+func writeRunStreamEvents(w http.ResponseWriter, events [][2]string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, event := range events {
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event[0], event[1])
+	}
+}
+
+func TestCreateRunStreamWithToolOutputsMock(t *testing.T) {
+	var submitted []ToolOutput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if body["stream"] != true {
+			t.Errorf("Expected stream: true, got %v", body["stream"])
+		}
+
+		switch r.URL.Path {
+		case "/threads/thread_1/runs":
+			writeRunStreamEvents(w, [][2]string{
+				{"thread.run.created", `{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"queued"}`},
+				{"thread.run.step.created", `{"id":"step_1","object":"thread.run.step","type":"tool_calls","status":"in_progress","step_details":{"type":"tool_calls","tool_calls":[]}}`},
+				{"thread.run.step.delta", `{"id":"step_1","object":"thread.run.step.delta","delta":{"step_details":{"type":"tool_calls","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}}`},
+				{"thread.run.step.delta", `{"id":"step_1","object":"thread.run.step.delta","delta":{"step_details":{"type":"tool_calls","tool_calls":[{"index":0,"function":{"arguments":"\"Seoul\"}"}}]}}}`},
+				{"thread.run.requires_action", `{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"requires_action","required_action":{"type":"submit_tool_outputs","submit_tool_outputs":{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Seoul\"}"}}]}}}`},
+				{"done", `[DONE]`},
+			})
+		case "/threads/thread_1/runs/run_1/submit_tool_outputs":
+			bytes, _ := json.Marshal(body["tool_outputs"])
+			_ = json.Unmarshal(bytes, &submitted)
+
+			writeRunStreamEvents(w, [][2]string{
+				{"thread.message.created", `{"id":"msg_1","object":"thread.message","role":"assistant","content":[]}`},
+				{"thread.message.delta", `{"id":"msg_1","object":"thread.message.delta","delta":{"content":[{"index":0,"type":"text","text":{"value":"It is "}}]}}`},
+				{"thread.message.delta", `{"id":"msg_1","object":"thread.message.delta","delta":{"content":[{"index":0,"type":"text","text":{"value":"sunny."}}]}}`},
+				{"thread.run.completed", `{"id":"run_1","object":"thread.run","thread_id":"thread_1","status":"completed"}`},
+				{"done", `[DONE]`},
+			})
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	accumulator := NewRunStreamAccumulator()
+	finished := make(chan error, 1)
+	err := client.CreateRunStreamWithToolOutputs(context.Background(), "thread_1", "asst_1", nil,
+		func(ctx context.Context, run Run) ([]ToolOutput, error) {
+			outputs := []ToolOutput{}
+			for _, call := range run.RequiredAction.SubmitToolOutputs.ToolCalls {
+				id, output := call.ID, "sunny"
+				outputs = append(outputs, ToolOutput{ToolCallID: &id, Output: &output})
+			}
+			return outputs, nil
+		},
+		func(event RunStreamEvent, done bool, err error) {
+			accumulator.Add(event)
+			if done {
+				finished <- err
+			}
+		})
+	if err != nil {
+		t.Fatalf("CreateRunStreamWithToolOutputs failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+
+	if len(submitted) != 1 || *submitted[0].ToolCallID != "call_1" || *submitted[0].Output != "sunny" {
+		t.Errorf("Unexpected submitted tool outputs: %+v", submitted)
+	}
+	if run := accumulator.Run(); run == nil || run.Status != RunStatusCompleted {
+		t.Errorf("Expected a completed run, got %+v", run)
+	}
+	if text := accumulator.Text("msg_1"); text != "It is sunny." {
+		t.Errorf("Expected 'It is sunny.', got '%s'", text)
+	}
+	steps := accumulator.RunSteps()
+	if len(steps) != 1 || len(steps[0].StepDetails.ToolCalls) != 1 ||
+		steps[0].StepDetails.ToolCalls[0].Function.Arguments != `{"city":"Seoul"}` {
+		t.Errorf("Unexpected run steps: %+v", steps)
+	}
+}
+
+func TestRunStreamErrorEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeRunStreamEvents(w, [][2]string{
+			{"thread.run.created", `{"id":"run_1","status":"queued"}`},
+			{"error", `{"message":"Something went wrong.","type":"server_error"}`},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	finished := make(chan error, 1)
+	if err := client.CreateThreadAndRunStream("asst_1", nil, func(event RunStreamEvent, done bool, err error) {
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateThreadAndRunStream failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err == nil || !strings.Contains(err.Error(), "Something went wrong.") {
+			t.Errorf("Expected stream error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+}
+
+func TestAccumulateMessageContentDeltas(t *testing.T) {
+	value1, value2 := "See ", "the file."
+	contents := AccumulateMessageContentDeltas(nil, []MessageContentDelta{
+		{Index: 0, Type: MessageContentTypeText, Text: &MessageContentTextDelta{Value: &value1}},
+		{Index: 1, Type: MessageContentTypeImageFile, ImageFile: &MessageContentImageFile{FileID: "file_1"}},
+	})
+	contents = AccumulateMessageContentDeltas(contents, []MessageContentDelta{
+		{Index: 0, Text: &MessageContentTextDelta{
+			Value: &value2,
+			Annotations: []MessageContentTextAnnotationDelta{
+				{Index: 0, MessageContentTextAnnotation: MessageContentTextAnnotation{Type: MessageContentTextAnnotationTypeFilePath, Text: "the file"}},
+			},
+		}},
+	})
+
+	if len(contents) != 2 {
+		t.Fatalf("Expected 2 contents, got %d", len(contents))
+	}
+	if contents[0].Text.Value != "See the file." || len(contents[0].Text.Annotations) != 1 {
+		t.Errorf("Unexpected text content: %+v", contents[0].Text)
+	}
+	if contents[1].ImageFile == nil || contents[1].ImageFile.FileID != "file_1" {
+		t.Errorf("Unexpected image content: %+v", contents[1])
+	}
+}
+
+func TestReadServerSentEvents(t *testing.T) {
+	stream := ": comment\nevent: first\ndata: line 1\ndata: line 2\n\ndata: {\"a\":1}\n\nevent: last\ndata: no trailing blank line"
+
+	events := [][2]string{}
+	if err := readServerSentEvents(context.Background(), strings.NewReader(stream), func(event string, data []byte) bool {
+		events = append(events, [2]string{event, string(data)})
+		return true
+	}); err != nil {
+		t.Fatalf("readServerSentEvents failed: %v", err)
+	}
+
+	expected := [][2]string{
+		{"first", "line 1\nline 2"},
+		{"", `{"a":1}`},
+		{"last", "no trailing blank line"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], events[i])
+		}
+	}
+}
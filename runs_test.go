@@ -18,8 +18,6 @@ func TestRuns(t *testing.T) {
 		t.Errorf("environment variables `OPENAI_API_KEY` and `OPENAI_ORGANIZATION` are needed")
 	}
 
-	client.SetBetaHeader(`assistants=v1`)
-
 	// (A) testing runs
 	if thread, err := client.CreateThread(CreateThreadOptions{}.
 		SetMessages([]ThreadMessage{
@@ -100,8 +98,6 @@ func TestRunsWithToolOutputs(t *testing.T) {
 		t.Errorf("environment variables `OPENAI_API_KEY` and `OPENAI_ORGANIZATION` are needed")
 	}
 
-	client.SetBetaHeader(`assistants=v1`)
-
 	// (B) testing runs that need submission of tool outputs
 	if assistant, err := client.CreateAssistant(assistantsModel, CreateAssistantOptions{}.
 		SetName("Weather Notifier").
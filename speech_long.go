@@ -0,0 +1,293 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// helpers for synthesizing speech from texts longer than the API's limit
+
+const (
+	speechInputMaxChars             = 4096
+	defaultLongSpeechMaxConcurrency = 4
+)
+
+// SynthesizeLongTextOptions struct for synthesizing long text
+type SynthesizeLongTextOptions struct {
+	// options for each chunk
+	//
+	// `response_format` should be one of `mp3` (default), `wav`, or `pcm`, which can be joined.
+	// `stream_format` is ignored.
+	Speech SpeechOptions
+
+	// maximum number of characters in a chunk (default: 4096)
+	MaxChunkChars int
+
+	// maximum number of chunks synthesized in parallel (default: 4)
+	MaxConcurrency int
+
+	// called each time a chunk is synthesized, with the number of completed and total chunks
+	Progress func(completed, total int)
+}
+
+// SynthesizeLongText generates audio from `text` which can be longer than the API's limit.
+//
+// `text` is split on paragraph and sentence boundaries into chunks, which are synthesized in parallel,
+// and their audio is joined in order into a single file of `response_format`.
+func (c *Client) SynthesizeLongText(ctx context.Context, model string, text string, voice SpeechVoice, options SynthesizeLongTextOptions) (audio []byte, err error) {
+	if options.MaxChunkChars <= 0 || options.MaxChunkChars > speechInputMaxChars {
+		options.MaxChunkChars = speechInputMaxChars
+	}
+	if options.MaxConcurrency <= 0 {
+		options.MaxConcurrency = defaultLongSpeechMaxConcurrency
+	}
+
+	format := SpeechResponseFormatMP3
+	if f, exists := options.Speech["response_format"]; exists {
+		format = SpeechResponseFormat(fmt.Sprintf("%v", f))
+	}
+	switch format {
+	case SpeechResponseFormatMP3, SpeechResponseFormatWAV, SpeechResponseFormatPCM:
+	default:
+		return nil, fmt.Errorf("cannot join audio of response format: %s", format)
+	}
+
+	chunks := splitSpeechText(text, options.MaxChunkChars)
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no text to synthesize")
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	audios := make([][]byte, len(chunks))
+	semaphore := make(chan struct{}, options.MaxConcurrency)
+
+	var mutex sync.Mutex
+	completed := 0
+	var failure error
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			chunkOptions := SpeechOptions{}
+			for k, v := range options.Speech {
+				chunkOptions[k] = v
+			}
+			delete(chunkOptions, "stream_format")
+			chunkOptions.SetResponseFormat(format)
+
+			audio, err := c.CreateSpeechWithContext(ctx, model, chunk, voice, chunkOptions)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				if failure == nil {
+					failure = fmt.Errorf("failed to synthesize chunk %d/%d: %w", i+1, len(chunks), err)
+					cancel()
+				}
+				return
+			}
+			audios[i] = audio
+
+			completed++
+			if options.Progress != nil {
+				options.Progress(completed, len(chunks))
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if err = parent.Err(); err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+
+	return joinAudio(format, audios)
+}
+
+// splits `text` into chunks no longer than `maxChars`, on paragraph, sentence, and word boundaries
+func splitSpeechText(text string, maxChars int) (chunks []string) {
+	chunks = []string{}
+
+	current := ""
+	appendPiece := func(piece, separator string) {
+		if current == "" {
+			current = piece
+		} else if utf8.RuneCountInString(current)+utf8.RuneCountInString(separator)+utf8.RuneCountInString(piece) <= maxChars {
+			current += separator + piece
+		} else {
+			chunks = append(chunks, current)
+			current = piece
+		}
+	}
+
+	for _, paragraph := range splitParagraphs(text) {
+		if utf8.RuneCountInString(paragraph) <= maxChars {
+			appendPiece(paragraph, "\n\n")
+			continue
+		}
+
+		separator := "\n\n"
+		for _, sentence := range splitSentences(paragraph) {
+			for _, piece := range splitByLength(sentence, maxChars) {
+				appendPiece(piece, separator)
+				separator = " "
+			}
+		}
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// splits `text` into trimmed paragraphs separated by blank lines
+func splitParagraphs(text string) (paragraphs []string) {
+	paragraphs = []string{}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
+
+// splits `text` into sentences, after sentence-ending punctuations
+func splitSentences(text string) (sentences []string) {
+	sentences = []string{}
+
+	runes := []rune(text)
+	start := 0
+	for i, r := range runes {
+		ending := false
+		switch r {
+		case '.', '!', '?', '…':
+			// followed by a space (or closing quotes/brackets and then a space)
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune(`"'”’)]`, runes[j]) {
+				j++
+			}
+			ending = j >= len(runes) || unicode.IsSpace(runes[j])
+		case '。', '！', '？':
+			ending = true
+		}
+
+		if ending {
+			if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i + 1
+		}
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// splits `text` into pieces no longer than `maxChars`, on word boundaries if possible
+func splitByLength(text string, maxChars int) (pieces []string) {
+	pieces = []string{}
+
+	current := []rune{}
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+
+		// a word longer than the limit
+		for len(w) > maxChars {
+			if len(current) > 0 {
+				pieces = append(pieces, string(current))
+				current = []rune{}
+			}
+			pieces = append(pieces, string(w[:maxChars]))
+			w = w[maxChars:]
+		}
+
+		if len(current) == 0 {
+			current = w
+		} else if len(current)+1+len(w) <= maxChars {
+			current = append(append(current, ' '), w...)
+		} else {
+			pieces = append(pieces, string(current))
+			current = w
+		}
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, string(current))
+	}
+
+	return pieces
+}
+
+// joins `audios` of given `format` into a single file
+func joinAudio(format SpeechResponseFormat, audios [][]byte) (joined []byte, err error) {
+	switch format {
+	case SpeechResponseFormatPCM:
+		return bytes.Join(audios, nil), nil
+	case SpeechResponseFormatWAV:
+		return joinWAV(audios)
+	case SpeechResponseFormatMP3:
+		return joinMP3(audios)
+	}
+	return nil, fmt.Errorf("cannot join audio of response format: %s", format)
+}
+
+// joins WAV files with the same format into one
+func joinWAV(audios [][]byte) (joined []byte, err error) {
+	var fmtChunk []byte
+	samples := []byte{}
+	for i, audio := range audios {
+		var format wavFormat
+		if format, err = parseWAV(audio); err != nil {
+			return nil, fmt.Errorf("failed to parse wav of chunk %d: %s", i+1, err)
+		}
+
+		if fmtChunk == nil {
+			fmtChunk = format.fmtChunk
+		} else if !bytes.Equal(fmtChunk, format.fmtChunk) {
+			return nil, fmt.Errorf("wav format of chunk %d differs from the others", i+1)
+		}
+		samples = append(samples, format.data[:len(format.data)/format.blockAlign*format.blockAlign]...)
+	}
+
+	return buildWAV(fmtChunk, samples), nil
+}
+
+// joins MP3 files by concatenating their frames, without ID3 tags or Xing/Info frames
+func joinMP3(audios [][]byte) (joined []byte, err error) {
+	joined = []byte{}
+	for i, audio := range audios {
+		frames := scanMP3Frames(audio)
+		if len(frames) == 0 {
+			return nil, fmt.Errorf("no mp3 frames in chunk %d", i+1)
+		}
+		for _, frame := range frames {
+			joined = append(joined, audio[frame.offset:frame.offset+frame.length]...)
+		}
+	}
+	return joined, nil
+}
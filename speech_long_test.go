@@ -0,0 +1,164 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSplitSpeechText(t *testing.T) {
+	text := "First paragraph.\n\nSecond one. It has two sentences!\n\n" +
+		"A much longer paragraph goes here. It should be split on sentences, because it does not fit. Right?"
+
+	chunks := splitSpeechText(text, 60)
+	expected := []string{
+		"First paragraph.\n\nSecond one. It has two sentences!",
+		"A much longer paragraph goes here.",
+		"It should be split on sentences, because it does not fit.",
+		"Right?",
+	}
+	if strings.Join(chunks, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, chunks)
+	}
+	for _, chunk := range chunks {
+		if utf8.RuneCountInString(chunk) > 60 {
+			t.Errorf("Chunk is longer than the limit: %q", chunk)
+		}
+	}
+
+	if pieces := splitByLength("one two three", 7); strings.Join(pieces, "|") != "one two|three" {
+		t.Errorf("Expected to be split on words, got %q", pieces)
+	}
+	if chunks := splitSpeechText(strings.Repeat("가", 25), 10); len(chunks) != 3 || chunks[2] != strings.Repeat("가", 5) {
+		t.Errorf("Expected a long word to be split by characters, got %q", chunks)
+	}
+	if sentences := splitSentences(`He said "Stop." Then 3.14 is pi. 끝。다음`); len(sentences) != 4 {
+		t.Errorf("Unexpected sentences: %q", sentences)
+	}
+}
+
+func TestSynthesizeLongTextMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		var n int
+		fmt.Sscanf(body["input"].(string), "Paragraph %d.", &n)
+
+		// respond in reverse order
+		time.Sleep(time.Duration(10-n) * 5 * time.Millisecond)
+
+		switch body["response_format"] {
+		case "wav":
+			w.Write(newTestWAV(24000, 10, func(int) int16 { return int16(n) }))
+		case "mp3":
+			w.Write(newTestMP3(n + 1))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	paragraphs := []string{}
+	for i := 0; i < 5; i++ {
+		paragraphs = append(paragraphs, fmt.Sprintf("Paragraph %d.", i))
+	}
+	text := strings.Join(paragraphs, "\n\n")
+
+	// wav
+	var mutex sync.Mutex
+	progresses := []int{}
+	audio, err := client.SynthesizeLongText(context.Background(), "gpt-4o-mini-tts", text, SpeechVoiceAlloy, SynthesizeLongTextOptions{
+		Speech:        SpeechOptions{}.SetResponseFormat(SpeechResponseFormatWAV),
+		MaxChunkChars: 15,
+		Progress: func(completed, total int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if total != 5 {
+				t.Errorf("Expected 5 chunks in total, got %d", total)
+			}
+			progresses = append(progresses, completed)
+		},
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeLongText failed: %v", err)
+	}
+
+	format, err := parseWAV(audio)
+	if err != nil {
+		t.Fatalf("Joined audio is not a valid wav: %v", err)
+	}
+	if len(format.data) != 5*10*2 {
+		t.Fatalf("Expected 100 bytes of samples, got %d", len(format.data))
+	}
+	for i := 0; i < 5; i++ {
+		if sample := int16(binary.LittleEndian.Uint16(format.data[i*20:])); int(sample) != i {
+			t.Errorf("Expected chunk %d in order, got samples of chunk %d", i, sample)
+		}
+	}
+	if fmt.Sprint(progresses) != "[1 2 3 4 5]" {
+		t.Errorf("Unexpected progresses: %v", progresses)
+	}
+
+	// mp3
+	audio, err = client.SynthesizeLongText(context.Background(), "gpt-4o-mini-tts", text, SpeechVoiceAlloy, SynthesizeLongTextOptions{
+		MaxChunkChars: 15,
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeLongText failed: %v", err)
+	}
+	if bytes.HasPrefix(audio, []byte("ID3")) || len(audio) != (1+2+3+4+5)*417 {
+		t.Errorf("Expected frames only, got %d bytes", len(audio))
+	}
+	frames := scanMP3Frames(audio)
+	if len(frames) != 15 || audio[frames[1].offset+4] != 0 || audio[frames[2].offset+4] != 1 {
+		t.Errorf("Unexpected frames of joined mp3: %d frames", len(frames))
+	}
+
+	// unsupported format
+	if _, err := client.SynthesizeLongText(context.Background(), "gpt-4o-mini-tts", text, SpeechVoiceAlloy, SynthesizeLongTextOptions{
+		Speech: SpeechOptions{}.SetResponseFormat(SpeechResponseFormatOpus),
+	}); err == nil {
+		t.Errorf("Expected an error for unsupported format")
+	}
+}
+
+func TestSynthesizeLongTextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte{0x01, 0x02})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel after the first chunk, while the others are waiting for their turns
+	audio, err := client.SynthesizeLongText(ctx, "gpt-4o-mini-tts", "One.\n\nTwo.\n\nThree.\n\nFour.", SpeechVoiceAlloy, SynthesizeLongTextOptions{
+		Speech:         SpeechOptions{}.SetResponseFormat(SpeechResponseFormatPCM),
+		MaxChunkChars:  5,
+		MaxConcurrency: 1,
+		Progress: func(completed, total int) {
+			cancel()
+		},
+	})
+	if !errors.Is(err, context.Canceled) || audio != nil {
+		t.Errorf("Expected context.Canceled without audio, got %v (%d bytes)", err, len(audio))
+	}
+}
@@ -0,0 +1,149 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streaming speech synthesis
+
+// SpeechStreamEventType type for constants
+type SpeechStreamEventType string
+
+const (
+	SpeechStreamEventAudioDelta SpeechStreamEventType = "speech.audio.delta"
+	SpeechStreamEventAudioDone  SpeechStreamEventType = "speech.audio.done"
+	SpeechStreamEventError      SpeechStreamEventType = "error"
+)
+
+// SpeechStreamEvent struct for server-sent events of speech stream
+//
+// https://platform.openai.com/docs/api-reference/audio/speech-audio-delta-event
+type SpeechStreamEvent struct {
+	Type  SpeechStreamEventType `json:"type"`
+	Audio string                `json:"audio,omitempty"` // base64-encoded, for `speech.audio.delta`
+	Usage *SpeechUsage          `json:"usage,omitempty"` // for `speech.audio.done`
+	Error *Error                `json:"error,omitempty"`
+}
+
+// SpeechUsage struct for usages of speech
+type SpeechUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// AudioBytes decodes the base64-encoded audio of the event.
+func (e SpeechStreamEvent) AudioBytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.Audio)
+}
+
+func (e SpeechStreamEvent) withName(name string) SpeechStreamEvent {
+	if e.Type == "" {
+		e.Type = SpeechStreamEventType(name)
+	}
+	return e
+}
+
+func (e SpeechStreamEvent) eventError() *Error {
+	return e.Error
+}
+
+func (e SpeechStreamEvent) isLast() bool {
+	return e.Type == SpeechStreamEventAudioDone
+}
+
+// callback function for speech stream events
+type speechStreamCallback func(event SpeechStreamEvent, done bool, err error)
+
+// returns the request params of speech
+func speechParams(model string, input string, voice SpeechVoice, options SpeechOptions) SpeechOptions {
+	if options == nil {
+		options = SpeechOptions{}
+	}
+	options["model"] = model
+	options["input"] = input
+	options["voice"] = voice
+	return options
+}
+
+// CreateSpeechStream generates audio from the input text, and writes it to `w` as it arrives.
+//
+// If `w` implements `http.Flusher`, it is flushed after each write.
+//
+// With `stream_format` of `sse`, audio is decoded from `speech.audio.delta` events before written.
+//
+// https://platform.openai.com/docs/api-reference/audio/createSpeech
+func (c *Client) CreateSpeechStream(ctx context.Context, model string, input string, voice SpeechVoice, options SpeechOptions, w io.Writer) (written int64, err error) {
+	options = speechParams(model, input, voice, options)
+
+	var resp *http.Response
+	if resp, err = c.openStreamWithContext(ctx, "audio/speech", options); err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	flusher, _ := w.(http.Flusher)
+	write := func(bs []byte) error {
+		n, err := w.Write(bs)
+		written += int64(n)
+		if err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	if fmt.Sprintf("%v", options["stream_format"]) == string(SpeechStreamFormatSSE) {
+		err = readTypedServerSentEvents(ctx, resp.Body, func(event SpeechStreamEvent) error {
+			if event.Type != SpeechStreamEventAudioDelta {
+				return nil
+			}
+
+			audio, err := event.AudioBytes()
+			if err != nil {
+				return fmt.Errorf("failed to decode audio delta: %s", err)
+			}
+			return write(audio)
+		})
+		return written, err
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, e := resp.Body.Read(buf)
+		if n > 0 {
+			if err = write(buf[:n]); err != nil {
+				return written, err
+			}
+		}
+		if e == io.EOF {
+			return written, nil
+		} else if e != nil {
+			return written, e
+		}
+	}
+}
+
+// CreateSpeechStreamEvents generates audio from the input text, and streams its events to `cb`.
+//
+// `stream_format` is set to `sse`, and `cb` is called with `done` == true on the last event (`speech.audio.done`).
+//
+// https://platform.openai.com/docs/api-reference/audio/createSpeech#audio-createspeech-stream_format
+func (c *Client) CreateSpeechStreamEvents(ctx context.Context, model string, input string, voice SpeechVoice, options SpeechOptions, cb speechStreamCallback) (err error) {
+	options = speechParams(model, input, voice, options).SetStreamFormat(SpeechStreamFormatSSE)
+
+	var resp *http.Response
+	if resp, err = c.openStreamWithContext(ctx, "audio/speech", options); err != nil {
+		return err
+	}
+
+	streamTypedEvents(ctx, resp, cb)
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a writer which counts flushes
+type flushCountingWriter struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushCountingWriter) Flush() {
+	w.flushes++
+}
+
+func TestCreateSpeechStreamMock(t *testing.T) {
+	chunks := [][]byte{[]byte("RIFF"), []byte("chunk-1"), []byte("chunk-2")}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if body["instructions"] != "Speak cheerfully." || body["voice"] != "coral" || body["response_format"] != "wav" {
+			t.Errorf("Unexpected request body: %v", body)
+		}
+
+		w.Header().Set("Content-Type", "audio/wav")
+		for _, chunk := range chunks {
+			w.Write(chunk)
+			w.(http.Flusher).Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	w := &flushCountingWriter{}
+	written, err := client.CreateSpeechStream(context.Background(), "gpt-4o-mini-tts", "Hello!", SpeechVoiceCoral, SpeechOptions{}.
+		SetInstructions("Speak cheerfully.").
+		SetResponseFormat(SpeechResponseFormatWAV), w)
+	if err != nil {
+		t.Fatalf("CreateSpeechStream failed: %v", err)
+	}
+
+	expected := bytes.Join(chunks, nil)
+	if written != int64(len(expected)) || !bytes.Equal(w.Bytes(), expected) {
+		t.Errorf("Expected %q (%d bytes), got %q (%d bytes)", expected, len(expected), w.Bytes(), written)
+	}
+	if w.flushes < 1 {
+		t.Errorf("Expected the writer to be flushed")
+	}
+}
+
+func TestCreateSpeechStreamSSEMock(t *testing.T) {
+	pcm1, pcm2 := []byte{0x01, 0x02}, []byte{0x03, 0x04}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if body["stream_format"] != "sse" {
+			t.Errorf("Expected stream_format: sse, got %v", body["stream_format"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: {\"type\":\"speech.audio.delta\",\"audio\":\"%s\"}\n\n", base64.StdEncoding.EncodeToString(pcm1))
+		fmt.Fprintf(w, "data: {\"type\":\"speech.audio.delta\",\"audio\":\"%s\"}\n\n", base64.StdEncoding.EncodeToString(pcm2))
+		fmt.Fprint(w, "data: {\"type\":\"speech.audio.done\",\"usage\":{\"input_tokens\":5,\"output_tokens\":20,\"total_tokens\":25}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// events
+	var audio bytes.Buffer
+	var usage *SpeechUsage
+	finished := make(chan error, 1)
+	if err := client.CreateSpeechStreamEvents(context.Background(), "gpt-4o-mini-tts", "Hello!", SpeechVoiceMarin, SpeechOptions{}.
+		SetResponseFormat(SpeechResponseFormatPCM), func(event SpeechStreamEvent, done bool, err error) {
+		if err == nil {
+			switch event.Type {
+			case SpeechStreamEventAudioDelta:
+				bs, _ := event.AudioBytes()
+				audio.Write(bs)
+			case SpeechStreamEventAudioDone:
+				usage = event.Usage
+			}
+		}
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateSpeechStreamEvents failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+	if !bytes.Equal(audio.Bytes(), append(pcm1, pcm2...)) {
+		t.Errorf("Unexpected audio: %v", audio.Bytes())
+	}
+	if usage == nil || usage.TotalTokens != 25 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	// writer
+	var w bytes.Buffer
+	if _, err := client.CreateSpeechStream(context.Background(), "gpt-4o-mini-tts", "Hello!", SpeechVoiceMarin, SpeechOptions{}.
+		SetStreamFormat(SpeechStreamFormatSSE), &w); err != nil {
+		t.Fatalf("CreateSpeechStream failed: %v", err)
+	}
+	if !bytes.Equal(w.Bytes(), append(pcm1, pcm2...)) {
+		t.Errorf("Expected decoded audio, got %v", w.Bytes())
+	}
+}
+
+func TestCreateSpeechStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Invalid voice.","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	var w bytes.Buffer
+	if _, err := client.CreateSpeechStream(context.Background(), "gpt-4o-mini-tts", "Hello!", SpeechVoice("unknown"), nil, &w); err == nil || !strings.Contains(err.Error(), "Invalid voice.") {
+		t.Errorf("Expected error, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structured outputs with JSON schemas
+//
+// https://platform.openai.com/docs/guides/structured-outputs
+
+// RefusalError is returned when the model refuses to generate a structured output.
+type RefusalError struct {
+	Refusal string
+}
+
+// Error returns the error message.
+func (e *RefusalError) Error() string {
+	return fmt.Sprintf("model refused to respond: %s", e.Refusal)
+}
+
+// TruncatedOutputError is returned when a structured output was truncated before completion
+// (eg. by `max_tokens`), along with the partially parsed result.
+type TruncatedOutputError struct {
+	Reason string // eg. "length", "max_output_tokens"
+	Output string // truncated output as it is
+}
+
+// Error returns the error message.
+func (e *TruncatedOutputError) Error() string {
+	return fmt.Sprintf("output was truncated (%s), result is partial", e.Reason)
+}
+
+// NewChatCompletionResponseFormatJSONSchema returns a `json_schema` ChatCompletionResponseFormat with given `schema`.
+func NewChatCompletionResponseFormatJSONSchema(name string, schema map[string]any, strict bool) ChatCompletionResponseFormat {
+	return ChatCompletionResponseFormat{
+		Type: ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &ChatCompletionResponseFormatJSONSchema{
+			Name:   name,
+			Schema: schema,
+			Strict: &strict,
+		},
+	}
+}
+
+// ChatCompletionResponseFormatFromStruct returns a strict `json_schema` ChatCompletionResponseFormat
+// with the schema generated from struct `T`.
+//
+// If `name` is empty, the name of `T` is used.
+func ChatCompletionResponseFormatFromStruct[T any](name string) (format ChatCompletionResponseFormat, err error) {
+	var schema map[string]any
+	if name, schema, err = structuredOutputSchema[T](name); err != nil {
+		return ChatCompletionResponseFormat{}, err
+	}
+
+	return NewChatCompletionResponseFormatJSONSchema(name, schema, true), nil
+}
+
+// NewResponseTextFormatJSONSchema returns a `json_schema` ResponseTextFormat with given `schema`.
+func NewResponseTextFormatJSONSchema(name string, schema map[string]any, strict bool) ResponseTextFormat {
+	return ResponseTextFormat{
+		Type:   "json_schema",
+		Name:   name,
+		Schema: schema,
+		Strict: &strict,
+	}
+}
+
+// ResponseTextFormatFromStruct returns a strict `json_schema` ResponseTextFormat
+// with the schema generated from struct `T`.
+//
+// If `name` is empty, the name of `T` is used.
+func ResponseTextFormatFromStruct[T any](name string) (format ResponseTextFormat, err error) {
+	var schema map[string]any
+	if name, schema, err = structuredOutputSchema[T](name); err != nil {
+		return ResponseTextFormat{}, err
+	}
+
+	return NewResponseTextFormatJSONSchema(name, schema, true), nil
+}
+
+// generates a strict schema and its name from struct `T`
+func structuredOutputSchema[T any](name string) (string, map[string]any, error) {
+	typ3 := reflect.TypeOf((*T)(nil)).Elem()
+	for typ3.Kind() == reflect.Pointer {
+		typ3 = typ3.Elem()
+	}
+	if name == "" {
+		name = invalidDefNameChars.ReplaceAllString(typ3.Name(), "_")
+		if name == "" {
+			name = "output"
+		}
+	}
+
+	schema, err := jsonSchemaFromType(typ3, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, schema, nil
+}
+
+// CreateChatCompletionParsed creates a chat completion with a strict `json_schema` response format
+// generated from struct `T`, and decodes its output into `T`.
+//
+// Returns *RefusalError if the model refuses, and *TruncatedOutputError with a partial result
+// if the output is truncated by `max_tokens`.
+func CreateChatCompletionParsed[T any](ctx context.Context, client *Client, model string, messages []ChatMessage, options ChatCompletionOptions) (parsed T, completion ChatCompletion, err error) {
+	var format ChatCompletionResponseFormat
+	if format, err = ChatCompletionResponseFormatFromStruct[T](""); err != nil {
+		return parsed, ChatCompletion{}, err
+	}
+
+	// copy options, not to modify the given ones
+	copied := ChatCompletionOptions{}
+	for k, v := range options {
+		copied[k] = v
+	}
+	copied.SetResponseFormat(format)
+	delete(copied, "stream")
+
+	if completion, err = client.CreateChatCompletionWithContext(ctx, model, messages, copied); err != nil {
+		return parsed, completion, err
+	}
+	if len(completion.Choices) <= 0 {
+		return parsed, completion, fmt.Errorf("no choice in chat completion")
+	}
+
+	choice := completion.Choices[0]
+	if choice.Message.Refusal != nil && *choice.Message.Refusal != "" {
+		return parsed, completion, &RefusalError{Refusal: *choice.Message.Refusal}
+	}
+
+	var content string
+	if content, err = choice.Message.ContentString(); err != nil {
+		return parsed, completion, err
+	}
+
+	if choice.FinishReason == "length" {
+		parsed, err = decodeTruncatedStructuredOutput[T](content, choice.FinishReason)
+		return parsed, completion, err
+	}
+
+	if err = json.Unmarshal([]byte(content), &parsed); err != nil {
+		return parsed, completion, fmt.Errorf("failed to decode structured output: %s", err)
+	}
+
+	return parsed, completion, nil
+}
+
+// CreateResponseParsed creates a response with a strict `json_schema` text format
+// generated from struct `T`, and decodes its output into `T`.
+//
+// Returns *RefusalError if the model refuses, and *TruncatedOutputError with a partial result
+// if the output is truncated by `max_output_tokens`.
+func CreateResponseParsed[T any](ctx context.Context, client *Client, model string, input any, options ResponseOptions) (parsed T, response Response, err error) {
+	var format ResponseTextFormat
+	if format, err = ResponseTextFormatFromStruct[T](""); err != nil {
+		return parsed, Response{}, err
+	}
+
+	// copy options (including nested `text`), not to modify the given ones
+	copied := ResponseOptions{}
+	for k, v := range options {
+		copied[k] = v
+	}
+	if text, ok := options["text"].(map[string]any); ok {
+		copiedText := map[string]any{}
+		for k, v := range text {
+			copiedText[k] = v
+		}
+		copied["text"] = copiedText
+	}
+	copied.SetTextFormat(format)
+	delete(copied, "stream")
+
+	if response, err = client.CreateResponseWithContext(ctx, model, input, copied); err != nil {
+		return parsed, response, err
+	}
+
+	texts := []string{}
+	for _, output := range response.Output {
+		if output.Type != "message" {
+			continue
+		}
+		for _, content := range output.Content {
+			switch content.Type {
+			case "refusal":
+				return parsed, response, &RefusalError{Refusal: content.Refusal}
+			case "output_text":
+				texts = append(texts, content.Text)
+			}
+		}
+	}
+	text := strings.Join(texts, "")
+
+	if response.Status == "incomplete" {
+		parsed, err = decodeTruncatedStructuredOutput[T](text, responseIncompleteReason(response))
+		return parsed, response, err
+	}
+	if len(texts) <= 0 {
+		return parsed, response, fmt.Errorf("no text output in response")
+	}
+
+	if err = json.Unmarshal([]byte(text), &parsed); err != nil {
+		return parsed, response, fmt.Errorf("failed to decode structured output: %s", err)
+	}
+
+	return parsed, response, nil
+}
+
+// returns the reason of an incomplete response
+func responseIncompleteReason(response Response) string {
+	if details, ok := response.IncompleteDetails.(map[string]any); ok {
+		if reason, ok := details["reason"].(string); ok {
+			return reason
+		}
+	}
+	return "incomplete"
+}
+
+// decodes a truncated output into `T` as much as possible, and returns it with *TruncatedOutputError
+func decodeTruncatedStructuredOutput[T any](output, reason string) (parsed T, err error) {
+	parsed, _, _ = ParsePartialJSONInto[T](output)
+
+	return parsed, &TruncatedOutputError{Reason: reason, Output: output}
+}
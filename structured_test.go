@@ -0,0 +1,130 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type structuredTestRecipe struct {
+	Title       string   `json:"title"`
+	Ingredients []string `json:"ingredients"`
+	Minutes     *int     `json:"minutes"`
+}
+
+func TestCreateChatCompletionParsed(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			ResponseFormat ChatCompletionResponseFormat `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if requestBody.ResponseFormat.Type != ChatCompletionResponseFormatTypeJSONSchema ||
+			requestBody.ResponseFormat.JSONSchema == nil ||
+			requestBody.ResponseFormat.JSONSchema.Name != "structuredTestRecipe" ||
+			requestBody.ResponseFormat.JSONSchema.Strict == nil || !*requestBody.ResponseFormat.JSONSchema.Strict {
+			t.Errorf("Unexpected response format: %+v", requestBody.ResponseFormat)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	messages := []ChatMessage{NewChatUserMessage("Give me a recipe for kimchi stew.")}
+
+	// complete
+	body = `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"{\"title\":\"Kimchi stew\",\"ingredients\":[\"kimchi\",\"pork\"],\"minutes\":30}"},"finish_reason":"stop"}]}`
+	options := ChatCompletionOptions{}.SetTemperature(0.5)
+	recipe, _, err := CreateChatCompletionParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", messages, options)
+	if err != nil {
+		t.Fatalf("CreateChatCompletionParsed failed: %v", err)
+	}
+	if _, exists := options["response_format"]; exists || len(options) != 1 {
+		t.Errorf("Expected given options not to be modified, got %+v", options)
+	}
+	if recipe.Title != "Kimchi stew" || len(recipe.Ingredients) != 2 || recipe.Minutes == nil || *recipe.Minutes != 30 {
+		t.Errorf("Unexpected parsed result: %+v", recipe)
+	}
+
+	// refused
+	body = `{"id":"chatcmpl-2","choices":[{"index":0,"message":{"role":"assistant","content":null,"refusal":"I cannot help with that."},"finish_reason":"stop"}]}`
+	_, _, err = CreateChatCompletionParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", messages, nil)
+	var refusal *RefusalError
+	if !errors.As(err, &refusal) || refusal.Refusal != "I cannot help with that." {
+		t.Errorf("Expected RefusalError, got %v", err)
+	}
+
+	// truncated
+	body = `{"id":"chatcmpl-3","choices":[{"index":0,"message":{"role":"assistant","content":"{\"title\":\"Kimchi stew\",\"ingredients\":[\"kimchi\",\"po"},"finish_reason":"length"}]}`
+	recipe, _, err = CreateChatCompletionParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", messages, nil)
+	var truncated *TruncatedOutputError
+	if !errors.As(err, &truncated) || truncated.Reason != "length" {
+		t.Errorf("Expected TruncatedOutputError, got %v", err)
+	}
+	if recipe.Title != "Kimchi stew" || len(recipe.Ingredients) != 2 || recipe.Ingredients[1] != "po" {
+		t.Errorf("Unexpected partial result: %+v", recipe)
+	}
+}
+
+func TestCreateResponseParsed(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Text struct {
+				Format ResponseTextFormat `json:"format"`
+			} `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if requestBody.Text.Format.Type != "json_schema" || requestBody.Text.Format.Schema == nil {
+			t.Errorf("Unexpected text format: %+v", requestBody.Text.Format)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	body = `{"id":"resp_1","status":"completed","output":[{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"output_text","text":"{\"title\":\"Bibimbap\",\"ingredients\":[\"rice\"],\"minutes\":null}"}]}]}`
+	options := ResponseOptions{"stream": true, "text": map[string]any{"verbosity": "low"}}
+	recipe, _, err := CreateResponseParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", "Give me a recipe.", options)
+	if err != nil {
+		t.Fatalf("CreateResponseParsed failed: %v", err)
+	}
+	if recipe.Title != "Bibimbap" || recipe.Minutes != nil {
+		t.Errorf("Unexpected parsed result: %+v", recipe)
+	}
+	if text := options["text"].(map[string]any); len(options) != 2 || options["stream"] != true || len(text) != 1 {
+		t.Errorf("Expected given options not to be modified, got %+v", options)
+	}
+
+	body = `{"id":"resp_2","status":"completed","output":[{"id":"msg_2","type":"message","role":"assistant","content":[{"type":"refusal","refusal":"No."}]}]}`
+	_, _, err = CreateResponseParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", "Give me a recipe.", nil)
+	var refusal *RefusalError
+	if !errors.As(err, &refusal) {
+		t.Errorf("Expected RefusalError, got %v", err)
+	}
+
+	body = `{"id":"resp_3","status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},"output":[{"id":"msg_3","type":"message","role":"assistant","content":[{"type":"output_text","text":"{\"title\":\"Bibim"}]}]}`
+	recipe, _, err = CreateResponseParsed[structuredTestRecipe](context.Background(), client, "gpt-4o", "Give me a recipe.", nil)
+	var truncated *TruncatedOutputError
+	if !errors.As(err, &truncated) || truncated.Reason != "max_output_tokens" {
+		t.Errorf("Expected TruncatedOutputError, got %v", err)
+	}
+	if recipe.Title != "Bibim" {
+		t.Errorf("Unexpected partial result: %+v", recipe)
+	}
+}
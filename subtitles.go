@@ -0,0 +1,521 @@
+package openai
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// utilities for parsing, writing, and post-processing subtitles (SRT and WebVTT) of transcriptions
+
+// SubtitleCue struct for a cue of subtitles
+type SubtitleCue struct {
+	// identifier of the cue (index in SRT, optional identifier in WebVTT)
+	ID string
+
+	Start time.Duration
+	End   time.Duration
+
+	// lines of text, separated with '\n'
+	Text string
+
+	// WebVTT cue settings (eg. "align:start position:10%")
+	Settings string
+}
+
+// Duration returns the duration of the cue.
+func (c SubtitleCue) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// ParseSubtitles parses given SRT or WebVTT `subtitles` into cues, detecting its format.
+func ParseSubtitles(subtitles string) (cues []SubtitleCue, err error) {
+	if strings.HasPrefix(normalizeSubtitles(subtitles), "WEBVTT") {
+		return ParseVTT(subtitles)
+	}
+	return ParseSRT(subtitles)
+}
+
+// ParseSRT parses given SRT `subtitles` into cues.
+func ParseSRT(subtitles string) (cues []SubtitleCue, err error) {
+	cues = []SubtitleCue{}
+
+	for _, block := range subtitleBlocks(normalizeSubtitles(subtitles)) {
+		lines := strings.Split(block, "\n")
+
+		// index line is optional here, for tolerating malformed files
+		id := ""
+		if !strings.Contains(lines[0], "-->") {
+			id = strings.TrimSpace(lines[0])
+			lines = lines[1:]
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("no timing line in srt cue: '%s'", block)
+		}
+
+		var cue SubtitleCue
+		if cue, err = parseSubtitleTiming(lines[0]); err != nil {
+			return nil, err
+		}
+		cue.ID = id
+		cue.Text = strings.Join(lines[1:], "\n")
+
+		cues = append(cues, cue)
+	}
+
+	return cues, nil
+}
+
+// ParseVTT parses given WebVTT `subtitles` into cues.
+//
+// Header, NOTE, STYLE, and REGION blocks are skipped.
+func ParseVTT(subtitles string) (cues []SubtitleCue, err error) {
+	blocks := subtitleBlocks(normalizeSubtitles(subtitles))
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0], "WEBVTT") {
+		return nil, fmt.Errorf("not a webvtt file: no 'WEBVTT' header")
+	}
+
+	cues = []SubtitleCue{}
+	for _, block := range blocks[1:] {
+		if strings.HasPrefix(block, "NOTE") ||
+			strings.HasPrefix(block, "STYLE") ||
+			strings.HasPrefix(block, "REGION") {
+			continue
+		}
+
+		lines := strings.Split(block, "\n")
+
+		id := ""
+		if !strings.Contains(lines[0], "-->") {
+			id = lines[0]
+			lines = lines[1:]
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("no timing line in webvtt cue: '%s'", block)
+		}
+
+		var cue SubtitleCue
+		if cue, err = parseSubtitleTiming(lines[0]); err != nil {
+			return nil, err
+		}
+		cue.ID = id
+		cue.Text = strings.Join(lines[1:], "\n")
+
+		cues = append(cues, cue)
+	}
+
+	return cues, nil
+}
+
+// normalizes line endings and removes BOM
+func normalizeSubtitles(subtitles string) string {
+	subtitles = strings.TrimPrefix(subtitles, "\ufeff")
+	subtitles = strings.ReplaceAll(subtitles, "\r\n", "\n")
+	subtitles = strings.ReplaceAll(subtitles, "\r", "\n")
+	return strings.TrimSpace(subtitles)
+}
+
+// splits subtitles into blocks separated by blank lines
+func subtitleBlocks(subtitles string) (blocks []string) {
+	blocks = []string{}
+
+	lines := []string{}
+	for _, line := range strings.Split(subtitles, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(lines) > 0 {
+				blocks = append(blocks, strings.Join(lines, "\n"))
+				lines = []string{}
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	return blocks
+}
+
+// parses a timing line (eg. "00:00:01,000 --> 00:00:02,500 align:start")
+func parseSubtitleTiming(line string) (cue SubtitleCue, err error) {
+	splitted := strings.SplitN(line, "-->", 2)
+	if len(splitted) != 2 {
+		return cue, fmt.Errorf("invalid subtitle timing line: '%s'", line)
+	}
+
+	if cue.Start, err = parseSubtitleTimestamp(strings.TrimSpace(splitted[0])); err != nil {
+		return cue, err
+	}
+
+	rest := strings.Fields(splitted[1])
+	if len(rest) == 0 {
+		return cue, fmt.Errorf("invalid subtitle timing line: '%s'", line)
+	}
+	if cue.End, err = parseSubtitleTimestamp(rest[0]); err != nil {
+		return cue, err
+	}
+	cue.Settings = strings.Join(rest[1:], " ")
+
+	return cue, nil
+}
+
+// parses a timestamp in "HH:MM:SS,mmm", "HH:MM:SS.mmm", or "MM:SS.mmm" format
+func parseSubtitleTimestamp(timestamp string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid subtitle timestamp: '%s'", timestamp)
+
+	millis := 0
+	if i := strings.LastIndexAny(timestamp, ",."); i >= 0 {
+		fraction := timestamp[i+1:]
+		if len(fraction) == 0 || len(fraction) > 3 {
+			return 0, invalid
+		}
+		fraction += strings.Repeat("0", 3-len(fraction))
+
+		var err error
+		if millis, err = strconv.Atoi(fraction); err != nil {
+			return 0, invalid
+		}
+		timestamp = timestamp[:i]
+	}
+
+	parts := strings.Split(timestamp, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, invalid
+	}
+
+	values := []int{}
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, invalid
+		}
+		values = append(values, value)
+	}
+	if len(values) == 2 {
+		values = append([]int{0}, values...)
+	}
+
+	return time.Duration(values[0])*time.Hour +
+		time.Duration(values[1])*time.Minute +
+		time.Duration(values[2])*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+// formats a timestamp with given millisecond `separator`
+func formatSubtitleTimestamp(d time.Duration, separator string) string {
+	if d < 0 {
+		d = 0
+	}
+	millis := d.Milliseconds()
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		millis/3600000,
+		(millis/60000)%60,
+		(millis/1000)%60,
+		separator,
+		millis%1000)
+}
+
+// FormatSRT writes given `cues` in SRT format.
+//
+// Cues are numbered sequentially from 1.
+func FormatSRT(cues []SubtitleCue) string {
+	var sb strings.Builder
+	for i, cue := range cues {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n",
+			i+1,
+			formatSubtitleTimestamp(cue.Start, ","),
+			formatSubtitleTimestamp(cue.End, ","),
+			cue.Text)
+	}
+	return sb.String()
+}
+
+// FormatVTT writes given `cues` in WebVTT format.
+func FormatVTT(cues []SubtitleCue) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		sb.WriteString("\n")
+		if cue.ID != "" {
+			fmt.Fprintf(&sb, "%s\n", cue.ID)
+		}
+		fmt.Fprintf(&sb, "%s --> %s", formatSubtitleTimestamp(cue.Start, "."), formatSubtitleTimestamp(cue.End, "."))
+		if cue.Settings != "" {
+			fmt.Fprintf(&sb, " %s", cue.Settings)
+		}
+		fmt.Fprintf(&sb, "\n%s\n", cue.Text)
+	}
+	return sb.String()
+}
+
+// FormatSubtitles writes given `cues` in `format` (`srt` or `vtt`).
+func FormatSubtitles(cues []SubtitleCue, format TranscriptionResponseFormat) (string, error) {
+	switch format {
+	case TranscriptionResponseFormatSRT:
+		return FormatSRT(cues), nil
+	case TranscriptionResponseFormatVTT:
+		return FormatVTT(cues), nil
+	}
+	return "", fmt.Errorf("not a subtitle format: %s", format)
+}
+
+// ConvertSubtitles converts given SRT or WebVTT `subtitles` into `format` (`srt` or `vtt`).
+func ConvertSubtitles(subtitles string, format TranscriptionResponseFormat) (converted string, err error) {
+	var cues []SubtitleCue
+	if cues, err = ParseSubtitles(subtitles); err != nil {
+		return "", err
+	}
+	return FormatSubtitles(cues, format)
+}
+
+// SubtitleCuesFromSegments converts `verbose_json` transcription segments into cues.
+func SubtitleCuesFromSegments(segments []TranscriptionSegment) []SubtitleCue {
+	cues := []SubtitleCue{}
+	for _, segment := range segments {
+		cues = append(cues, SubtitleCue{
+			Start: secondsToDuration(segment.Start),
+			End:   secondsToDuration(segment.End),
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	return cues
+}
+
+// converts seconds in float into a duration, rounded to milliseconds
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds*1000+0.5) * time.Millisecond
+}
+
+// SubtitleCues returns cues of the transcription,
+// from its `verbose_json` segments, or its `srt` or `vtt` response.
+func (t Transcription) SubtitleCues() ([]SubtitleCue, error) {
+	switch {
+	case len(t.Segments) > 0:
+		return SubtitleCuesFromSegments(t.Segments), nil
+	case t.SRT != nil:
+		return ParseSRT(*t.SRT)
+	case t.VTT != nil:
+		return ParseVTT(*t.VTT)
+	}
+	return nil, fmt.Errorf("no segments or subtitles in the transcription")
+}
+
+// ShiftSubtitleCues returns `cues` shifted by `offset`.
+//
+// Start times are clamped to zero, and cues which end before zero are dropped.
+func ShiftSubtitleCues(cues []SubtitleCue, offset time.Duration) []SubtitleCue {
+	shifted := []SubtitleCue{}
+	for _, cue := range cues {
+		cue.Start += offset
+		cue.End += offset
+		if cue.End <= 0 {
+			continue
+		}
+		if cue.Start < 0 {
+			cue.Start = 0
+		}
+		shifted = append(shifted, cue)
+	}
+	return shifted
+}
+
+// SubtitleMergeOptions struct for merging subtitle cues
+type SubtitleMergeOptions struct {
+	// maximum gap between merged cues (default: 0, only contiguous or overlapping cues are merged)
+	MaxGap time.Duration
+
+	// maximum duration of a merged cue (0 for no limit)
+	MaxDuration time.Duration
+
+	// maximum number of characters of a merged cue's text (0 for no limit)
+	MaxChars int
+}
+
+// MergeSubtitleCues merges adjacent `cues` into longer ones within the limits of `options`.
+//
+// Texts of merged cues are joined with a space.
+func MergeSubtitleCues(cues []SubtitleCue, options SubtitleMergeOptions) []SubtitleCue {
+	merged := []SubtitleCue{}
+	for _, cue := range cues {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			text := joinSubtitleTexts(last.Text, cue.Text)
+
+			if cue.Start-last.End <= options.MaxGap &&
+				(options.MaxDuration <= 0 || cue.End-last.Start <= options.MaxDuration) &&
+				(options.MaxChars <= 0 || utf8.RuneCountInString(text) <= options.MaxChars) {
+				last.Text = text
+				if cue.End > last.End {
+					last.End = cue.End
+				}
+				continue
+			}
+		}
+		merged = append(merged, cue)
+	}
+	return merged
+}
+
+// joins texts of two cues into a single line
+func joinSubtitleTexts(a, b string) string {
+	a = strings.Join(strings.Fields(a), " ")
+	b = strings.Join(strings.Fields(b), " ")
+	if a == "" {
+		return b
+	} else if b == "" {
+		return a
+	}
+	return a + " " + b
+}
+
+// SubtitleWrapOptions struct for re-wrapping subtitle cues
+type SubtitleWrapOptions struct {
+	// maximum number of characters in a line (default: 42)
+	MaxLineLength int
+
+	// maximum number of lines in a cue (default: 2)
+	MaxLines int
+
+	// maximum duration of a cue (0 for no limit)
+	MaxDuration time.Duration
+}
+
+const (
+	defaultSubtitleMaxLineLength = 42
+	defaultSubtitleMaxLines      = 2
+)
+
+// WrapSubtitleCues re-wraps texts of `cues` by words within the limits of `options`.
+//
+// Cues with too many lines are split into consecutive ones, and cues longer than `MaxDuration`
+// are split by words into as many as needed. Times are distributed in proportion to the number of characters.
+func WrapSubtitleCues(cues []SubtitleCue, options SubtitleWrapOptions) []SubtitleCue {
+	if options.MaxLineLength <= 0 {
+		options.MaxLineLength = defaultSubtitleMaxLineLength
+	}
+	if options.MaxLines <= 0 {
+		options.MaxLines = defaultSubtitleMaxLines
+	}
+
+	wrapped := []SubtitleCue{}
+	for _, cue := range cues {
+		lines := wrapSubtitleText(cue.Text, options.MaxLineLength)
+		if len(lines) == 0 {
+			continue
+		}
+
+		chunks := groupSubtitleLines(lines, options.MaxLines)
+
+		// split by words if chunks are still too long
+		if options.MaxDuration > 0 && cue.Duration() > options.MaxDuration*time.Duration(len(chunks)) {
+			pieces := int((cue.Duration() + options.MaxDuration - 1) / options.MaxDuration)
+
+			chunks = [][]string{}
+			for _, text := range splitWordsEvenly(strings.Fields(cue.Text), pieces) {
+				chunks = append(chunks, groupSubtitleLines(wrapSubtitleText(text, options.MaxLineLength), options.MaxLines)...)
+			}
+		}
+
+		// distribute times by the number of characters
+		total := 0
+		for _, chunk := range chunks {
+			for _, line := range chunk {
+				total += utf8.RuneCountInString(line)
+			}
+		}
+		start := cue.Start
+		counted := 0
+		for i, chunk := range chunks {
+			for _, line := range chunk {
+				counted += utf8.RuneCountInString(line)
+			}
+
+			end := cue.Start + time.Duration(int64(cue.Duration())*int64(counted)/int64(total))
+			if i == len(chunks)-1 {
+				end = cue.End
+			}
+
+			split := cue
+			split.Start = start
+			split.End = end
+			split.Text = strings.Join(chunk, "\n")
+			if len(chunks) > 1 && cue.ID != "" {
+				split.ID = fmt.Sprintf("%s-%d", cue.ID, i+1)
+			}
+			wrapped = append(wrapped, split)
+
+			start = end
+		}
+	}
+	return wrapped
+}
+
+// groups `lines` into chunks of at most `maxLines` lines
+func groupSubtitleLines(lines []string, maxLines int) (chunks [][]string) {
+	for i := 0; i < len(lines); i += maxLines {
+		end := i + maxLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		chunks = append(chunks, lines[i:end])
+	}
+	return chunks
+}
+
+// splits `words` into `n` texts (or fewer, if there are not enough words) with similar numbers of characters
+func splitWordsEvenly(words []string, n int) (texts []string) {
+	total := 0
+	for _, word := range words {
+		total += utf8.RuneCountInString(word)
+	}
+
+	piece := []string{}
+	counted := 0
+	for _, word := range words {
+		length := utf8.RuneCountInString(word)
+
+		// close the piece before this word, if it is closer to its share
+		target := float64(total) * float64(len(texts)+1) / float64(n)
+		if len(piece) > 0 && len(texts) < n-1 && math.Abs(float64(counted)-target) < math.Abs(float64(counted+length)-target) {
+			texts = append(texts, strings.Join(piece, " "))
+			piece = []string{}
+		}
+
+		piece = append(piece, word)
+		counted += length
+	}
+	if len(piece) > 0 {
+		texts = append(texts, strings.Join(piece, " "))
+	}
+	return texts
+}
+
+// wraps `text` into lines by words, each of them no longer than `maxLineLength` (unless a word is longer)
+func wrapSubtitleText(text string, maxLineLength int) (lines []string) {
+	lines = []string{}
+
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line == "" {
+			line = word
+		} else if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= maxLineLength {
+			line += " " + word
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
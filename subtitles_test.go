@@ -0,0 +1,166 @@
+package openai
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSubtitles(t *testing.T) {
+	srt := "\ufeff1\r\n00:00:00,000 --> 00:00:02,500\r\nHello world.\r\n\r\n2\r\n00:00:02,500 --> 00:01:03,040\r\nThis is the second line,\r\nwith two lines.\r\n"
+	vtt := "WEBVTT - from whisper\n\nNOTE this is a comment\n\nintro\n00:00.000 --> 00:02.500 align:start\nHello world.\n\n01:00:02.500 --> 01:01:03.040\nThis is the second line,\nwith two lines.\n"
+
+	srtCues, err := ParseSubtitles(srt)
+	if err != nil {
+		t.Fatalf("Failed to parse srt: %v", err)
+	}
+	if len(srtCues) != 2 {
+		t.Fatalf("Expected 2 srt cues, got %d", len(srtCues))
+	}
+	if srtCues[1].ID != "2" || srtCues[1].Start != 2500*time.Millisecond || srtCues[1].End != 63040*time.Millisecond {
+		t.Errorf("Unexpected srt cue: %+v", srtCues[1])
+	}
+	if srtCues[1].Text != "This is the second line,\nwith two lines." {
+		t.Errorf("Unexpected srt cue text: '%s'", srtCues[1].Text)
+	}
+
+	vttCues, err := ParseSubtitles(vtt)
+	if err != nil {
+		t.Fatalf("Failed to parse vtt: %v", err)
+	}
+	if len(vttCues) != 2 {
+		t.Fatalf("Expected 2 vtt cues, got %d", len(vttCues))
+	}
+	if vttCues[0].ID != "intro" || vttCues[0].Settings != "align:start" || vttCues[0].End != 2500*time.Millisecond {
+		t.Errorf("Unexpected vtt cue: %+v", vttCues[0])
+	}
+	if vttCues[1].Start != time.Hour+2500*time.Millisecond {
+		t.Errorf("Unexpected vtt cue start: %v", vttCues[1].Start)
+	}
+
+	if _, err := ParseSRT("1\n00:00:xx,000 --> 00:00:01,000\nbroken"); err == nil {
+		t.Errorf("Expected an error for invalid timestamp")
+	}
+	if _, err := ParseVTT("1\n00:00:00.000 --> 00:00:01.000\nno header"); err == nil {
+		t.Errorf("Expected an error for missing header")
+	}
+}
+
+func TestConvertSubtitles(t *testing.T) {
+	srt := "1\n00:00:00,000 --> 00:00:02,500\nHello world.\n\n2\n00:00:02,500 --> 00:00:04,000\nBye.\n"
+
+	vtt, err := ConvertSubtitles(srt, TranscriptionResponseFormatVTT)
+	if err != nil {
+		t.Fatalf("Failed to convert srt to vtt: %v", err)
+	}
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n1\n00:00:00.000 --> 00:00:02.500\nHello world.\n") {
+		t.Errorf("Unexpected vtt: '%s'", vtt)
+	}
+
+	converted, err := ConvertSubtitles(vtt, TranscriptionResponseFormatSRT)
+	if err != nil {
+		t.Fatalf("Failed to convert vtt to srt: %v", err)
+	}
+	if converted != srt {
+		t.Errorf("Expected round-tripped srt '%s', got '%s'", srt, converted)
+	}
+
+	if _, err := ConvertSubtitles(srt, TranscriptionResponseFormatJSON); err == nil {
+		t.Errorf("Expected an error for non-subtitle format")
+	}
+}
+
+func TestSubtitleCuesFromTranscription(t *testing.T) {
+	transcription := Transcription{
+		Segments: []TranscriptionSegment{
+			{Start: 0, End: 1.2345, Text: " Hello."},
+			{Start: 1.2345, End: 3, Text: " World."},
+		},
+	}
+
+	cues, err := transcription.SubtitleCues()
+	if err != nil {
+		t.Fatalf("Failed to get cues: %v", err)
+	}
+
+	expected := "1\n00:00:00,000 --> 00:00:01,235\nHello.\n\n2\n00:00:01,235 --> 00:00:03,000\nWorld.\n"
+	if srt := FormatSRT(cues); srt != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, srt)
+	}
+}
+
+func TestShiftAndMergeSubtitleCues(t *testing.T) {
+	cues := []SubtitleCue{
+		{Start: 0, End: time.Second, Text: "Gone."},
+		{Start: time.Second, End: 3 * time.Second, Text: "Hello"},
+		{Start: 3100 * time.Millisecond, End: 4 * time.Second, Text: "world."},
+		{Start: 6 * time.Second, End: 7 * time.Second, Text: "Far away."},
+	}
+
+	shifted := ShiftSubtitleCues(cues, -time.Second)
+	if len(shifted) != 3 {
+		t.Fatalf("Expected 3 shifted cues, got %d", len(shifted))
+	}
+	if shifted[0].Start != 0 || shifted[0].End != 2*time.Second {
+		t.Errorf("Unexpected shifted cue: %+v", shifted[0])
+	}
+
+	merged := MergeSubtitleCues(shifted, SubtitleMergeOptions{MaxGap: 200 * time.Millisecond, MaxDuration: 5 * time.Second})
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 merged cues, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].Text != "Hello world." || merged[0].End != 3*time.Second {
+		t.Errorf("Unexpected merged cue: %+v", merged[0])
+	}
+
+	limited := MergeSubtitleCues(shifted, SubtitleMergeOptions{MaxGap: 200 * time.Millisecond, MaxChars: 8})
+	if len(limited) != 3 {
+		t.Errorf("Expected cues not to be merged over MaxChars, got %+v", limited)
+	}
+}
+
+func TestWrapSubtitleCues(t *testing.T) {
+	cues := []SubtitleCue{
+		{ID: "a", Start: 0, End: 8 * time.Second, Text: "one two three four five six seven eight"},
+	}
+
+	wrapped := WrapSubtitleCues(cues, SubtitleWrapOptions{MaxLineLength: 11, MaxLines: 2})
+	if len(wrapped) != 2 {
+		t.Fatalf("Expected 2 wrapped cues, got %d: %+v", len(wrapped), wrapped)
+	}
+	if wrapped[0].Text != "one two\nthree four" || wrapped[1].Text != "five six\nseven eight" {
+		t.Errorf("Unexpected wrapped texts: %q, %q", wrapped[0].Text, wrapped[1].Text)
+	}
+	if wrapped[0].ID != "a-1" || wrapped[1].ID != "a-2" {
+		t.Errorf("Unexpected wrapped ids: %s, %s", wrapped[0].ID, wrapped[1].ID)
+	}
+	if wrapped[0].Start != 0 || wrapped[1].End != 8*time.Second || wrapped[0].End != wrapped[1].Start {
+		t.Errorf("Unexpected wrapped times: %+v", wrapped)
+	}
+	if expected := 8 * time.Second * 17 / 36; wrapped[0].End != expected {
+		t.Errorf("Expected times to be distributed by characters (%v), got %v", expected, wrapped[0].End)
+	}
+
+	split := WrapSubtitleCues(cues, SubtitleWrapOptions{MaxLineLength: 11, MaxLines: 2, MaxDuration: 3 * time.Second})
+	if len(split) != 3 || split[0].Text != "one two\nthree" || split[2].Text != "seven eight" {
+		t.Errorf("Expected 3 cues split by words, got %+v", split)
+	}
+	for _, cue := range split {
+		if cue.Duration() > 3*time.Second {
+			t.Errorf("Expected cues not longer than 3 seconds, got %+v", cue)
+		}
+	}
+
+	// a single long line
+	long := WrapSubtitleCues([]SubtitleCue{
+		{Start: time.Second, End: 13 * time.Second, Text: "aaa bbb ccc ddd eee fff"},
+	}, SubtitleWrapOptions{MaxDuration: 4 * time.Second})
+	if len(long) != 3 {
+		t.Fatalf("Expected 3 cues split by duration, got %d: %+v", len(long), long)
+	}
+	for i, expected := range []string{"aaa bbb", "ccc ddd", "eee fff"} {
+		if long[i].Text != expected || long[i].Start != time.Duration(1+4*i)*time.Second || long[i].Duration() != 4*time.Second {
+			t.Errorf("Unexpected cue %d: %+v", i, long[i])
+		}
+	}
+}
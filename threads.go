@@ -15,17 +15,21 @@ import (
 type Thread struct {
 	CommonResponse
 
-	ID        string            `json:"id"`
-	CreatedAt int               `json:"created_at"`
-	Metadata  map[string]string `json:"metadata"`
+	ID            string            `json:"id"`
+	CreatedAt     int               `json:"created_at"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata"`
 }
 
 // ThreadMessage struct for Thread
 type ThreadMessage struct {
-	Role     string            `json:"role"` // == 'user'
-	Content  string            `json:"content"`
-	FileIDs  []string          `json:"file_ids,omitempty"`
-	Metadata map[string]string `json:"metadata,omitempty"`
+	Role        string              `json:"role"`    // 'user' | 'assistant'
+	Content     any                 `json:"content"` // string | []MessageContentPart
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Metadata    map[string]string   `json:"metadata,omitempty"`
+
+	// Deprecated: `file_ids` was removed in v2, use `Attachments` instead.
+	FileIDs []string `json:"file_ids,omitempty"`
 }
 
 // NewThreadMessage returns a new ThreadMessage with given `content`.
@@ -36,7 +40,23 @@ func NewThreadMessage(content string) ThreadMessage {
 	}
 }
 
+// NewThreadMessageWithContentParts returns a new ThreadMessage with given content `parts` (text and images).
+func NewThreadMessageWithContentParts(parts []MessageContentPart) ThreadMessage {
+	return ThreadMessage{
+		Role:    "user",
+		Content: parts,
+	}
+}
+
+// SetAttachments sets the `attachments` value of ThreadMessage and return it.
+func (m ThreadMessage) SetAttachments(attachments []MessageAttachment) ThreadMessage {
+	m.Attachments = attachments
+	return m
+}
+
 // SetFileIDs sets the `file_ids` value of ThreadMessage and return it.
+//
+// Deprecated: `file_ids` was removed in v2, use `SetAttachments` instead.
 func (m ThreadMessage) SetFileIDs(fileIDs []string) ThreadMessage {
 	m.FileIDs = fileIDs
 	return m
@@ -59,6 +79,14 @@ func (o CreateThreadOptions) SetMessages(messages []ThreadMessage) CreateThreadO
 	return o
 }
 
+// SetToolResources sets the `tool_resources` parameter of CreateThreadOptions.
+//
+// https://platform.openai.com/docs/api-reference/threads/createThread#threads-createthread-tool_resources
+func (o CreateThreadOptions) SetToolResources(resources ToolResources) CreateThreadOptions {
+	o["tool_resources"] = resources
+	return o
+}
+
 // SetMetadata sets the `metadata` parameter of CreateThreadOptions.
 //
 // https://platform.openai.com/docs/api-reference/threads/createThread#threads-createthread-metadata
@@ -120,6 +148,14 @@ func (c *Client) RetrieveThread(threadID string) (response Thread, err error) {
 // ModifyThreadOptions for modifying thread
 type ModifyThreadOptions map[string]any
 
+// SetToolResources sets the `tool_resources` parameter of ModifyThreadOptions.
+//
+// https://platform.openai.com/docs/api-reference/threads/modifyThread#threads-modifythread-tool_resources
+func (o ModifyThreadOptions) SetToolResources(resources ToolResources) ModifyThreadOptions {
+	o["tool_resources"] = resources
+	return o
+}
+
 // SetMetadata sets the `metadata` parameter of ModifyThreadOptions.
 //
 // https://platform.openai.com/docs/api-reference/threads/modifyThread#threads-modifythread-metadata
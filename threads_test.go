@@ -17,8 +17,6 @@ func TestThreads(t *testing.T) {
 		t.Errorf("environment variables `OPENAI_API_KEY` and `OPENAI_ORGANIZATION` are needed")
 	}
 
-	client.SetBetaHeader(`assistants=v1`)
-
 	// === CreateThread ===
 	if created, err := client.CreateThread(CreateThreadOptions{}.
 		SetMessages([]ThreadMessage{
@@ -0,0 +1,570 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // for decoding image configs
+	_ "image/jpeg" // for decoding image configs
+	_ "image/png"  // for decoding image configs
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// local BPE tokenizer for counting tokens before sending requests
+//
+// https://github.com/openai/tiktoken
+// https://cookbook.openai.com/examples/how_to_count_tokens_with_tiktoken
+
+// TokenizerEncoding type for constants
+type TokenizerEncoding string
+
+// TokenizerEncoding constants
+const (
+	TokenizerEncodingCL100kBase TokenizerEncoding = "cl100k_base"
+	TokenizerEncodingO200kBase  TokenizerEncoding = "o200k_base"
+)
+
+// unicode whitespaces (`\s` of go's regexp matches ascii whitespaces only)
+const tokenizerWhitespaces = `\s\x{0B}\x{85}\p{Z}`
+
+// pre-tokenization patterns of encodings
+//
+// NOTE: go's regexp has no lookahead, so `\s+(?!\S)|\s+` is captured as `(\s+)` and handled in `splitPieces`.
+var tokenizerPatterns = map[TokenizerEncoding]string{
+	TokenizerEncodingCL100kBase: `(?i:'s|'t|'re|'ve|'m|'ll|'d)` +
+		`|[^\r\n\p{L}\p{N}]?\p{L}+` +
+		`|\p{N}{1,3}` +
+		`| ?[^` + tokenizerWhitespaces + `\p{L}\p{N}]+[\r\n]*` +
+		`|[` + tokenizerWhitespaces + `]*[\r\n]+` +
+		`|([` + tokenizerWhitespaces + `]+)`,
+	TokenizerEncodingO200kBase: `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}` +
+		`| ?[^` + tokenizerWhitespaces + `\p{L}\p{N}]+[\r\n/]*` +
+		`|[` + tokenizerWhitespaces + `]*[\r\n]+` +
+		`|([` + tokenizerWhitespaces + `]+)`,
+}
+
+// Tokenizer struct for BPE tokenization
+type Tokenizer struct {
+	Encoding TokenizerEncoding
+
+	pattern *regexp.Regexp
+	ranks   map[string]int
+	tokens  map[int]string
+}
+
+// NewTokenizer returns a new Tokenizer with given `encoding` and `.tiktoken` ranks read from `r`.
+func NewTokenizer(encoding TokenizerEncoding, r io.Reader) (tokenizer *Tokenizer, err error) {
+	pattern, exists := tokenizerPatterns[encoding]
+	if !exists {
+		return nil, fmt.Errorf("unsupported encoding: '%s'", encoding)
+	}
+
+	tokenizer = &Tokenizer{
+		Encoding: encoding,
+		pattern:  regexp.MustCompile(pattern),
+		ranks:    map[string]int{},
+		tokens:   map[int]string{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		fields := bytes.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed line %d in ranks file: '%s'", lineNum, line)
+		}
+
+		var token []byte
+		if token, err = base64.StdEncoding.DecodeString(string(fields[0])); err != nil {
+			return nil, fmt.Errorf("failed to decode token at line %d: %s", lineNum, err)
+		}
+		var rank int
+		if rank, err = strconv.Atoi(string(fields[1])); err != nil {
+			return nil, fmt.Errorf("failed to parse rank at line %d: %s", lineNum, err)
+		}
+
+		tokenizer.ranks[string(token)] = rank
+		tokenizer.tokens[rank] = string(token)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ranks file: %s", err)
+	}
+
+	// every single byte should be encodable
+	for b := 0; b < 256; b++ {
+		if _, exists := tokenizer.ranks[string([]byte{byte(b)})]; !exists {
+			return nil, fmt.Errorf("incomplete ranks file: no rank for byte 0x%02x", b)
+		}
+	}
+
+	return tokenizer, nil
+}
+
+// NewTokenizerFromFile returns a new Tokenizer with given `encoding` and `.tiktoken` file at `path`.
+func NewTokenizerFromFile(encoding TokenizerEncoding, path string) (tokenizer *Tokenizer, err error) {
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewTokenizer(encoding, f)
+}
+
+// NewTokenizerFromFS returns a new Tokenizer with given `encoding` and `.tiktoken` file at `path` of `fsys` (eg. embed.FS).
+func NewTokenizerFromFS(encoding TokenizerEncoding, fsys fs.FS, path string) (tokenizer *Tokenizer, err error) {
+	var f fs.File
+	if f, err = fsys.Open(path); err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewTokenizer(encoding, f)
+}
+
+// Encode returns the token ids of given `text`. Special tokens are encoded as ordinary text.
+func (t *Tokenizer) Encode(text string) (tokens []int) {
+	tokens = []int{}
+	for _, piece := range t.splitPieces(text) {
+		if rank, exists := t.ranks[piece]; exists {
+			tokens = append(tokens, rank)
+			continue
+		}
+		tokens = append(tokens, t.bytePairEncode(piece)...)
+	}
+	return tokens
+}
+
+// Decode returns the text of given token ids.
+func (t *Tokenizer) Decode(tokens []int) (text string, err error) {
+	var sb strings.Builder
+	for _, token := range tokens {
+		if str, exists := t.tokens[token]; exists {
+			sb.WriteString(str)
+		} else {
+			return "", fmt.Errorf("unknown token: %d", token)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Count returns the number of tokens of given `text`.
+func (t *Tokenizer) Count(text string) int {
+	return len(t.Encode(text))
+}
+
+// splits given text into pieces with the encoding's pattern
+func (t *Tokenizer) splitPieces(text string) (pieces []string) {
+	for pos := 0; pos < len(text); {
+		loc := t.pattern.FindStringSubmatchIndex(text[pos:])
+		if loc == nil || loc[1] == 0 {
+			// should not happen, but take a rune not to loop forever
+			_, size := utf8.DecodeRuneInString(text[pos:])
+			pieces = append(pieces, text[pos:pos+size])
+			pos += size
+			continue
+		}
+
+		end := pos + loc[1]
+
+		// `\s+(?!\S)`: leave the last whitespace for the following non-whitespace
+		if loc[2] >= 0 && end < len(text) {
+			if ws := text[pos:end]; utf8.RuneCountInString(ws) > 1 {
+				_, size := utf8.DecodeLastRuneInString(ws)
+				end -= size
+			}
+		}
+
+		pieces = append(pieces, text[pos:end])
+		pos = end
+	}
+	return pieces
+}
+
+// merges bytes of given piece by their ranks
+func (t *Tokenizer) bytePairEncode(piece string) (tokens []int) {
+	starts := make([]int, len(piece)+1)
+	for i := range starts {
+		starts[i] = i
+	}
+
+	for len(starts) > 2 {
+		minRank, minIndex := math.MaxInt, -1
+		for i := 0; i < len(starts)-2; i++ {
+			if rank, exists := t.ranks[piece[starts[i]:starts[i+2]]]; exists && rank < minRank {
+				minRank, minIndex = rank, i
+			}
+		}
+		if minIndex < 0 {
+			break
+		}
+		starts = append(starts[:minIndex+1], starts[minIndex+2:]...)
+	}
+
+	tokens = make([]int, 0, len(starts)-1)
+	for i := 0; i < len(starts)-1; i++ {
+		tokens = append(tokens, t.ranks[piece[starts[i]:starts[i+1]]])
+	}
+	return tokens
+}
+
+// registered tokenizers
+var (
+	tokenizers     = map[TokenizerEncoding]*Tokenizer{}
+	tokenizersLock sync.RWMutex
+)
+
+// RegisterTokenizer registers given `tokenizer` for its encoding, so that it can be used for counting tokens of models.
+func RegisterTokenizer(tokenizer *Tokenizer) {
+	tokenizersLock.Lock()
+	defer tokenizersLock.Unlock()
+
+	tokenizers[tokenizer.Encoding] = tokenizer
+}
+
+// TokenizerForModel returns the registered tokenizer for given `model`.
+func TokenizerForModel(model string) (tokenizer *Tokenizer, err error) {
+	var encoding TokenizerEncoding
+	if encoding, err = EncodingForModel(model); err != nil {
+		return nil, err
+	}
+
+	tokenizersLock.RLock()
+	defer tokenizersLock.RUnlock()
+
+	if tokenizer, exists := tokenizers[encoding]; exists {
+		return tokenizer, nil
+	}
+	return nil, fmt.Errorf("no tokenizer registered for encoding: '%s'", encoding)
+}
+
+// encodings of models (exact matches)
+var modelEncodings = map[string]TokenizerEncoding{
+	"gpt-4":                  TokenizerEncodingCL100kBase,
+	"gpt-3.5-turbo":          TokenizerEncodingCL100kBase,
+	"text-embedding-ada-002": TokenizerEncodingCL100kBase,
+	"text-embedding-3-small": TokenizerEncodingCL100kBase,
+	"text-embedding-3-large": TokenizerEncodingCL100kBase,
+}
+
+// encodings of models (prefix matches, in order)
+var modelPrefixEncodings = []struct {
+	prefix   string
+	encoding TokenizerEncoding
+}{
+	{"gpt-5", TokenizerEncodingO200kBase},
+	{"gpt-4.5", TokenizerEncodingO200kBase},
+	{"gpt-4.1", TokenizerEncodingO200kBase},
+	{"gpt-4o", TokenizerEncodingO200kBase},
+	{"chatgpt-4o", TokenizerEncodingO200kBase},
+	{"gpt-oss", TokenizerEncodingO200kBase},
+	{"o1", TokenizerEncodingO200kBase},
+	{"o3", TokenizerEncodingO200kBase},
+	{"o4", TokenizerEncodingO200kBase},
+	{"gpt-4-", TokenizerEncodingCL100kBase},
+	{"gpt-3.5-turbo-", TokenizerEncodingCL100kBase},
+	{"gpt-35-turbo", TokenizerEncodingCL100kBase},
+}
+
+// EncodingForModel returns the tokenizer encoding of given `model`.
+func EncodingForModel(model string) (encoding TokenizerEncoding, err error) {
+	// fine-tuned models: 'ft:gpt-4o-mini-2024-07-18:org::id'
+	if strings.HasPrefix(model, "ft:") {
+		model = strings.SplitN(strings.TrimPrefix(model, "ft:"), ":", 2)[0]
+	}
+
+	if encoding, exists := modelEncodings[model]; exists {
+		return encoding, nil
+	}
+	for _, pe := range modelPrefixEncodings {
+		if strings.HasPrefix(model, pe.prefix) {
+			return pe.encoding, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown encoding for model: '%s'", model)
+}
+
+// token overheads of chat messages and tools
+const (
+	tokensPerMessage      = 3
+	tokensPerReplyPrimer  = 3
+	tokensPerToolCall     = 3
+	tokensPerFunctionEnd  = 12
+	tokensPerPropertyInit = 3
+	tokensPerPropertyKey  = 3
+	tokensPerEnumInit     = -3
+	tokensPerEnumItem     = 3
+)
+
+// CountChatTokens counts the prompt tokens of given chat `messages` and `tools` for `model`,
+// with the tokenizer registered with `RegisterTokenizer`.
+func CountChatTokens(model string, messages []ChatMessage, tools []ChatCompletionTool) (count int, err error) {
+	var tokenizer *Tokenizer
+	if tokenizer, err = TokenizerForModel(model); err != nil {
+		return 0, err
+	}
+
+	return tokenizer.CountChatTokens(model, messages, tools)
+}
+
+// CountChatTokens counts the prompt tokens of given chat `messages` and `tools` for `model`.
+//
+// NOTE: the result is an estimation based on the known per-message and tool-schema overheads.
+func (t *Tokenizer) CountChatTokens(model string, messages []ChatMessage, tools []ChatCompletionTool) (count int, err error) {
+	for i, message := range messages {
+		var n int
+		if n, err = t.countMessageTokens(model, message); err != nil {
+			return 0, fmt.Errorf("failed to count tokens of message at index %d: %s", i, err)
+		}
+		count += n
+	}
+	count += tokensPerReplyPrimer
+
+	if len(tools) > 0 {
+		count += t.CountToolsTokens(tools)
+	}
+
+	return count, nil
+}
+
+// counts tokens of a chat message
+func (t *Tokenizer) countMessageTokens(model string, message ChatMessage) (count int, err error) {
+	count = tokensPerMessage + t.Count(string(message.Role))
+
+	switch content := message.Content.(type) {
+	case nil:
+	case string:
+		count += t.Count(content)
+	case *string:
+		if content != nil {
+			count += t.Count(*content)
+		}
+	case []ChatMessageContent:
+		for _, part := range content {
+			switch part.Type {
+			case "text":
+				if part.Text != nil {
+					count += t.Count(*part.Text)
+				}
+			case "image_url", "input_image":
+				count += part.ImageTokens(model)
+			default:
+				if part.Text != nil {
+					count += t.Count(*part.Text)
+				}
+			}
+		}
+	default:
+		return 0, fmt.Errorf("unsupported type of content: %T", message.Content)
+	}
+
+	for _, toolCall := range message.ToolCalls {
+		count += tokensPerToolCall + t.Count(toolCall.Function.Name) + t.Count(toolCall.Function.Arguments)
+	}
+	if message.ToolCallID != nil {
+		count += t.Count(*message.ToolCallID)
+	}
+
+	return count, nil
+}
+
+// CountToolsTokens counts the tokens of given `tools` definitions.
+func (t *Tokenizer) CountToolsTokens(tools []ChatCompletionTool) (count int) {
+	tokensPerFunctionInit := 7
+	if t.Encoding == TokenizerEncodingCL100kBase {
+		tokensPerFunctionInit = 10
+	}
+
+	for _, tool := range tools {
+		count += tokensPerFunctionInit
+
+		description := ""
+		if tool.Function.Description != nil {
+			description = strings.TrimSuffix(*tool.Function.Description, ".")
+		}
+		count += t.Count(tool.Function.Name + ":" + description)
+
+		properties := toolParameterProperties(tool.Function.Parameters)
+		if len(properties) > 0 {
+			count += tokensPerPropertyInit
+
+			for name, property := range properties {
+				count += tokensPerPropertyKey
+
+				typ3, _ := property["type"].(string)
+				description, _ := property["description"].(string)
+				if enums := toolPropertyEnums(property["enum"]); len(enums) > 0 {
+					count += tokensPerEnumInit
+					for _, enum := range enums {
+						count += tokensPerEnumItem + t.Count(enum)
+					}
+				}
+				count += t.Count(name + ":" + typ3 + ":" + strings.TrimSuffix(description, "."))
+			}
+		}
+	}
+	count += tokensPerFunctionEnd
+
+	return count
+}
+
+// returns properties of tool function parameters as maps
+func toolParameterProperties(parameters ToolFunctionParameters) (properties map[string]map[string]any) {
+	properties = map[string]map[string]any{}
+
+	if ps, ok := parameters["properties"].(map[string]any); ok {
+		for name, property := range ps {
+			switch p := property.(type) {
+			case map[string]any:
+				properties[name] = p
+			case map[string]string:
+				converted := map[string]any{}
+				for k, v := range p {
+					converted[k] = v
+				}
+				properties[name] = converted
+			}
+		}
+	}
+
+	return properties
+}
+
+// returns enum values of a tool property as strings
+func toolPropertyEnums(enum any) (enums []string) {
+	switch es := enum.(type) {
+	case []string:
+		return es
+	case []any:
+		for _, e := range es {
+			enums = append(enums, fmt.Sprintf("%v", e))
+		}
+	}
+	return enums
+}
+
+// returns the url and detail from `image_url` value of ChatMessageContent
+func imageURLAndDetail(imageURL any) (url, detail string) {
+	switch v := imageURL.(type) {
+	case string:
+		url = v
+	case *string:
+		if v != nil {
+			url = *v
+		}
+	case map[string]string:
+		url, detail = v["url"], v["detail"]
+	case map[string]any:
+		url, _ = v["url"].(string)
+		detail, _ = v["detail"].(string)
+	}
+	return url, detail
+}
+
+// image token costs
+const (
+	imageTokensLowDetail = 85
+	imageTokensBase      = 85
+	imageTokensPerTile   = 170
+
+	imageTokensBaseMini    = 2833
+	imageTokensPerTileMini = 5667
+
+	imageMaxDimension      = 2048
+	imageShortestDimension = 768
+	imageTileDimension     = 512
+)
+
+// ImageTokens returns the estimated token cost of an image with given dimensions and `detail` ('low', 'high', or 'auto') for `model`.
+//
+// https://platform.openai.com/docs/guides/images-vision#calculating-costs
+func ImageTokens(model string, width, height int, detail string) int {
+	base, perTile, lowDetail := imageTokensBase, imageTokensPerTile, imageTokensLowDetail
+	if strings.HasPrefix(model, "gpt-4o-mini") {
+		base, perTile, lowDetail = imageTokensBaseMini, imageTokensPerTileMini, imageTokensBaseMini
+	}
+
+	if detail == "low" {
+		return lowDetail
+	}
+
+	return base + perTile*imageTiles(highDetailImageSize(width, height))
+}
+
+// returns the dimensions of an image scaled for high detail: fit within 2048 x 2048, and then the shortest side to 768
+func highDetailImageSize(width, height int) (int, int) {
+	w, h := float64(width), float64(height)
+
+	// fit within 2048 x 2048
+	if w > imageMaxDimension || h > imageMaxDimension {
+		scale := imageMaxDimension / math.Max(w, h)
+		w, h = math.Floor(w*scale), math.Floor(h*scale)
+	}
+
+	// scale down so that the shortest side is 768
+	if shortest := math.Min(w, h); shortest > imageShortestDimension {
+		scale := imageShortestDimension / shortest
+		w, h = math.Floor(w*scale), math.Floor(h*scale)
+	}
+
+	return int(w), int(h)
+}
+
+// returns the number of 512px tiles of an image with given dimensions
+func imageTiles(width, height int) int {
+	return ((width + imageTileDimension - 1) / imageTileDimension) * ((height + imageTileDimension - 1) / imageTileDimension)
+}
+
+// ImageTokensForURL returns the estimated token cost of an image url for `model`.
+//
+// Dimensions are read from base64-encoded data URLs; for other URLs,
+// the largest possible cost for `detail` is returned.
+func ImageTokensForURL(model, url, detail string) int {
+	if width, height, err := dataURLImageSize(url); err == nil {
+		return ImageTokens(model, width, height, detail)
+	}
+
+	// worst case: 768 x 2048 after scaling
+	return ImageTokens(model, imageShortestDimension, imageMaxDimension, detail)
+}
+
+// returns the dimensions of an image in given data url
+func dataURLImageSize(url string) (width, height int, err error) {
+	if !strings.HasPrefix(url, "data:") {
+		return 0, 0, fmt.Errorf("not a data url")
+	}
+	index := strings.Index(url, ";base64,")
+	if index < 0 {
+		return 0, 0, fmt.Errorf("not a base64-encoded data url")
+	}
+
+	var decoded []byte
+	if decoded, err = base64.StdEncoding.DecodeString(url[index+len(";base64,"):]); err != nil {
+		return 0, 0, err
+	}
+
+	var config image.Config
+	if config, _, err = image.DecodeConfig(bytes.NewReader(decoded)); err != nil {
+		return 0, 0, err
+	}
+
+	return config.Width, config.Height, nil
+}
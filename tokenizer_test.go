@@ -0,0 +1,201 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// builds a small `.tiktoken` vocabulary: all single bytes, and a few merges
+func fixtureRanks(merges ...string) []byte {
+	var buf bytes.Buffer
+	rank := 0
+	for b := 0; b < 256; b++ {
+		buf.WriteString(fmt.Sprintf("%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), rank))
+		rank++
+	}
+	for _, merge := range merges {
+		buf.WriteString(fmt.Sprintf("%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), rank))
+		rank++
+	}
+	return buf.Bytes()
+}
+
+func fixtureTokenizer(t *testing.T, encoding TokenizerEncoding) *Tokenizer {
+	tokenizer, err := NewTokenizer(encoding, bytes.NewReader(fixtureRanks("he", "ll", "hell", "hello", " w", "or", " wor", "ld", " world")))
+	if err != nil {
+		t.Fatalf("failed to create tokenizer: %v", err)
+	}
+	return tokenizer
+}
+
+func TestTokenizerLoading(t *testing.T) {
+	ranks := fixtureRanks("he")
+
+	path := filepath.Join(t.TempDir(), "fixture.tiktoken")
+	if err := os.WriteFile(path, ranks, 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if _, err := NewTokenizerFromFile(TokenizerEncodingCL100kBase, path); err != nil {
+		t.Errorf("NewTokenizerFromFile failed: %v", err)
+	}
+
+	fsys := fstest.MapFS{"ranks/fixture.tiktoken": &fstest.MapFile{Data: ranks}}
+	if _, err := NewTokenizerFromFS(TokenizerEncodingO200kBase, fsys, "ranks/fixture.tiktoken"); err != nil {
+		t.Errorf("NewTokenizerFromFS failed: %v", err)
+	}
+
+	// incomplete vocabulary
+	if _, err := NewTokenizer(TokenizerEncodingCL100kBase, strings.NewReader("aGU= 0\n")); err == nil {
+		t.Errorf("Expected error for incomplete ranks file")
+	}
+
+	// unknown encoding
+	if _, err := NewTokenizer("p50k_base", bytes.NewReader(ranks)); err == nil {
+		t.Errorf("Expected error for unsupported encoding")
+	}
+}
+
+func TestTokenizerEncode(t *testing.T) {
+	tokenizer := fixtureTokenizer(t, TokenizerEncodingCL100kBase)
+
+	pieces := tokenizer.splitPieces("Hello  world\n\nfoo's 12345")
+	expected := []string{"Hello", " ", " world", "\n\n", "foo", "'s", " ", "123", "45"}
+	if fmt.Sprintf("%q", pieces) != fmt.Sprintf("%q", expected) {
+		t.Errorf("Expected pieces %q, got %q", expected, pieces)
+	}
+
+	tokens := tokenizer.Encode("hello world")
+	if len(tokens) != 2 {
+		t.Errorf("Expected 2 tokens for 'hello world', got %v", tokens)
+	}
+
+	// 'hellx' = 'hell' + 'x'
+	if tokens := tokenizer.Encode("hellx"); len(tokens) != 2 || tokens[0] != 256+2 {
+		t.Errorf("Expected ['hell', 'x'], got %v", tokens)
+	}
+
+	text := "héllo, wörld! 안녕"
+	if decoded, err := tokenizer.Decode(tokenizer.Encode(text)); err != nil || decoded != text {
+		t.Errorf("Expected round trip of '%s', got '%s' (%v)", text, decoded, err)
+	}
+
+	// o200k splits camel case words
+	o200k := fixtureTokenizer(t, TokenizerEncodingO200kBase)
+	pieces = o200k.splitPieces("HelloWorld path/to")
+	expected = []string{"Hello", "World", " path", "/to"}
+	if fmt.Sprintf("%q", pieces) != fmt.Sprintf("%q", expected) {
+		t.Errorf("Expected pieces %q, got %q", expected, pieces)
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	for model, expected := range map[string]TokenizerEncoding{
+		"gpt-4":                             TokenizerEncodingCL100kBase,
+		"gpt-4-0613":                        TokenizerEncodingCL100kBase,
+		"gpt-3.5-turbo-1106":                TokenizerEncodingCL100kBase,
+		"gpt-4o":                            TokenizerEncodingO200kBase,
+		"gpt-4o-mini-2024-07-18":            TokenizerEncodingO200kBase,
+		"gpt-4.1-nano":                      TokenizerEncodingO200kBase,
+		"o3-mini":                           TokenizerEncodingO200kBase,
+		"ft:gpt-4o-mini-2024-07-18:org::id": TokenizerEncodingO200kBase,
+	} {
+		if encoding, err := EncodingForModel(model); err != nil || encoding != expected {
+			t.Errorf("Expected encoding %s for model %s, got %s (%v)", expected, model, encoding, err)
+		}
+	}
+
+	if _, err := EncodingForModel("unknown-model"); err == nil {
+		t.Errorf("Expected error for unknown model")
+	}
+}
+
+func TestCountChatTokens(t *testing.T) {
+	tokenizer := fixtureTokenizer(t, TokenizerEncodingO200kBase)
+	RegisterTokenizer(tokenizer)
+
+	messages := []ChatMessage{
+		NewChatSystemMessage("hello"),
+		NewChatUserMessage("hello world"),
+	}
+
+	count, err := CountChatTokens("gpt-4o", messages, nil)
+	if err != nil {
+		t.Fatalf("CountChatTokens failed: %v", err)
+	}
+	expected := (tokensPerMessage + tokenizer.Count("system") + 1) +
+		(tokensPerMessage + tokenizer.Count("user") + 2) +
+		tokensPerReplyPrimer
+	if count != expected {
+		t.Errorf("Expected %d tokens, got %d", expected, count)
+	}
+
+	// with tools
+	tools := []ChatCompletionTool{
+		NewChatCompletionTool("get_weather", "Get weather.", NewToolFunctionParameters().
+			AddPropertyWithEnums("unit", "string", "Unit.", []string{"c", "f"})),
+	}
+	withTools, err := CountChatTokens("gpt-4o", messages, tools)
+	if err != nil {
+		t.Fatalf("CountChatTokens failed: %v", err)
+	}
+	toolsCount := 7 + tokenizer.Count("get_weather:Get weather") +
+		tokensPerPropertyInit + tokensPerPropertyKey +
+		tokensPerEnumInit + (tokensPerEnumItem + 1) + (tokensPerEnumItem + 1) +
+		tokenizer.Count("unit:string:Unit") +
+		tokensPerFunctionEnd
+	if withTools != count+toolsCount {
+		t.Errorf("Expected %d tokens with tools, got %d", count+toolsCount, withTools)
+	}
+
+	// with an image
+	img := image.NewRGBA(image.Rect(0, 0, 1024, 1024))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode image: %v", err)
+	}
+	withImage, err := CountChatTokens("gpt-4o", []ChatMessage{
+		NewChatUserMessage([]ChatMessageContent{NewChatMessageContentWithBytes(buf.Bytes())}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("CountChatTokens failed: %v", err)
+	}
+	if expected := tokensPerMessage + tokenizer.Count("user") + 765 + tokensPerReplyPrimer; withImage != expected {
+		t.Errorf("Expected %d tokens with image, got %d", expected, withImage)
+	}
+
+	// no tokenizer registered for cl100k_base
+	if _, err := CountChatTokens("gpt-4", messages, nil); err == nil {
+		t.Errorf("Expected error for unregistered encoding")
+	}
+}
+
+func TestImageTokens(t *testing.T) {
+	for _, tc := range []struct {
+		model         string
+		width, height int
+		detail        string
+		expected      int
+	}{
+		{"gpt-4o", 1024, 1024, "high", 765},
+		{"gpt-4o", 2048, 4096, "high", 1105},
+		{"gpt-4o", 4096, 8192, "low", 85},
+		{"gpt-4o", 512, 512, "auto", 255},
+		{"gpt-4o-mini", 4096, 8192, "low", 2833},
+	} {
+		if tokens := ImageTokens(tc.model, tc.width, tc.height, tc.detail); tokens != tc.expected {
+			t.Errorf("Expected %d tokens for %dx%d (%s), got %d", tc.expected, tc.width, tc.height, tc.detail, tokens)
+		}
+	}
+
+	if tokens := ImageTokensForURL("gpt-4o", "https://example.com/image.png", "high"); tokens != 1445 {
+		t.Errorf("Expected worst case 1445 tokens for remote url, got %d", tokens)
+	}
+}
@@ -0,0 +1,421 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// automatic tool-calling loop for chat completions
+
+const (
+	defaultToolRunnerMaxIterations  = 10
+	defaultToolRunnerMaxConcurrency = 4
+)
+
+// ErrMaxIterationsReached is returned when the model still requests tool calls after the maximum number of iterations.
+var ErrMaxIterationsReached = errors.New("maximum number of iterations reached")
+
+// ToolHandler type for handling tool calls
+//
+// Returned `output` is sent back to the model; a returned error is sent back as an error message.
+type ToolHandler func(ctx context.Context, call ToolCall) (output string, err error)
+
+// ToolApprover type for approving or denying tool calls before they run
+//
+// A non-nil error denies the call, and its message is sent back to the model.
+type ToolApprover func(ctx context.Context, call ToolCall) error
+
+// ToolPanicHandler type for handling panics recovered from tool handlers
+//
+// Returned string is sent back to the model as the output of the call.
+type ToolPanicHandler func(call ToolCall, recovered any) string
+
+// NewToolHandler returns a ToolHandler which parses arguments into `T` before calling `fn`.
+//
+// Result of `fn` is sent back as it is if it is a string, or serialized as JSON.
+func NewToolHandler[T any](fn func(ctx context.Context, args T) (result any, err error)) ToolHandler {
+	return func(ctx context.Context, call ToolCall) (output string, err error) {
+		var args T
+		if err = call.ArgumentsInto(&args); err != nil {
+			return "", fmt.Errorf("failed to parse arguments: %s", err)
+		}
+
+		var result any
+		if result, err = fn(ctx, args); err != nil {
+			return "", err
+		}
+
+		return toolOutputString(result)
+	}
+}
+
+// converts a result of tool call to a string
+func toolOutputString(result any) (string, error) {
+	switch r := result.(type) {
+	case string:
+		return r, nil
+	case []byte:
+		return string(r), nil
+	}
+
+	serialized, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize tool result: %s", err)
+	}
+	return string(serialized), nil
+}
+
+// ToolRunResult struct for the result of ToolRunner
+type ToolRunResult struct {
+	// all messages, including the given ones and the generated ones
+	Messages []ChatMessage
+
+	// the last chat completion
+	Completion ChatCompletion
+
+	// number of chat completion requests
+	Iterations int
+}
+
+// dispatches tool calls to registered handlers
+type toolDispatcher struct {
+	handlers       map[string]ToolHandler
+	parameters     map[string]ToolFunctionParameters
+	maxConcurrency int
+
+	validateArguments bool
+
+	approver     ToolApprover
+	panicHandler ToolPanicHandler
+}
+
+// returns a new toolDispatcher with default values
+func newToolDispatcher() toolDispatcher {
+	return toolDispatcher{
+		handlers:          map[string]ToolHandler{},
+		parameters:        map[string]ToolFunctionParameters{},
+		maxConcurrency:    defaultToolRunnerMaxConcurrency,
+		validateArguments: true,
+	}
+}
+
+// returns a semaphore channel for limiting concurrent tool calls
+func (d *toolDispatcher) semaphore() chan struct{} {
+	maxConcurrency := d.maxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+	return make(chan struct{}, maxConcurrency)
+}
+
+// runs tool calls in parallel, and returns their outputs in the same order
+func (d *toolDispatcher) dispatchAll(ctx context.Context, toolCalls []ToolCall) (outputs []string) {
+	outputs = make([]string, len(toolCalls))
+	semaphore := d.semaphore()
+	var wg sync.WaitGroup
+	for i, toolCall := range toolCalls {
+		wg.Add(1)
+		go func(i int, toolCall ToolCall) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			outputs[i] = d.dispatch(ctx, toolCall)
+		}(i, toolCall)
+	}
+	wg.Wait()
+
+	return outputs
+}
+
+// runs a tool call, and returns its output (or error message) for the model
+func (d *toolDispatcher) dispatch(ctx context.Context, toolCall ToolCall) (output string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if d.panicHandler != nil {
+				output = d.panicHandler(toolCall, recovered)
+			} else {
+				output = fmt.Sprintf("error: tool `%s` panicked: %v", toolCall.Function.Name, recovered)
+			}
+		}
+	}()
+
+	handler, exists := d.handlers[toolCall.Function.Name]
+	if !exists {
+		return fmt.Sprintf("error: no such tool: `%s`", toolCall.Function.Name)
+	}
+
+	if d.validateArguments {
+		if parameters := d.parameters[toolCall.Function.Name]; parameters != nil {
+			if err := parameters.ValidateArguments(toolCall.Function.Arguments); err != nil {
+				return fmt.Sprintf("error: invalid arguments for tool `%s`: %s. Fix the arguments and call it again.", toolCall.Function.Name, err)
+			}
+		}
+	}
+
+	if d.approver != nil {
+		if err := d.approver(ctx, toolCall); err != nil {
+			return fmt.Sprintf("error: tool call was denied: %s", err)
+		}
+	}
+
+	var err error
+	if output, err = handler(ctx, toolCall); err != nil {
+		return fmt.Sprintf("error: %s", err)
+	}
+
+	return output
+}
+
+// ToolRunner runs tool calls of chat completions automatically with registered handlers,
+// and feeds their results back to the model until it stops calling tools.
+type ToolRunner struct {
+	toolDispatcher
+
+	client  *Client
+	model   string
+	options ChatCompletionOptions
+
+	tools []ChatCompletionTool
+
+	maxIterations int
+}
+
+// NewToolRunner returns a new ToolRunner for `model`.
+func NewToolRunner(client *Client, model string) *ToolRunner {
+	return &ToolRunner{
+		toolDispatcher: newToolDispatcher(),
+		client:         client,
+		model:          model,
+		maxIterations:  defaultToolRunnerMaxIterations,
+	}
+}
+
+// Register registers a `tool` definition with its `handler`.
+func (r *ToolRunner) Register(tool ChatCompletionTool, handler ToolHandler) *ToolRunner {
+	name := tool.Function.Name
+	if _, exists := r.handlers[name]; exists {
+		for i := range r.tools {
+			if r.tools[i].Function.Name == name {
+				r.tools[i] = tool
+			}
+		}
+	} else {
+		r.tools = append(r.tools, tool)
+	}
+	r.handlers[name] = handler
+	r.parameters[name] = tool.Function.Parameters
+
+	return r
+}
+
+// Tools returns the registered tool definitions.
+func (r *ToolRunner) Tools() []ChatCompletionTool {
+	return r.tools
+}
+
+// SetOptions sets chat completion options used for each request.
+//
+// `tools` will be overwritten with the registered ones.
+func (r *ToolRunner) SetOptions(options ChatCompletionOptions) *ToolRunner {
+	r.options = options
+	return r
+}
+
+// SetMaxIterations sets the maximum number of chat completion requests. (default: 10)
+func (r *ToolRunner) SetMaxIterations(maxIterations int) *ToolRunner {
+	r.maxIterations = maxIterations
+	return r
+}
+
+// SetMaxConcurrency sets the maximum number of tool calls running in parallel. (default: 4)
+func (r *ToolRunner) SetMaxConcurrency(maxConcurrency int) *ToolRunner {
+	r.maxConcurrency = maxConcurrency
+	return r
+}
+
+// SetValidateArguments sets whether to validate arguments against the parameters of tools before running them. (default: true)
+//
+// Invalid arguments are not passed to handlers, and an error describing them is sent back to the model instead.
+func (r *ToolRunner) SetValidateArguments(validate bool) *ToolRunner {
+	r.validateArguments = validate
+	return r
+}
+
+// SetApprover sets a function which approves or denies each tool call before it runs.
+func (r *ToolRunner) SetApprover(approver ToolApprover) *ToolRunner {
+	r.approver = approver
+	return r
+}
+
+// SetPanicHandler sets a function which handles panics recovered from tool handlers.
+func (r *ToolRunner) SetPanicHandler(panicHandler ToolPanicHandler) *ToolRunner {
+	r.panicHandler = panicHandler
+	return r
+}
+
+// Run requests chat completions with given `messages`, running requested tool calls,
+// until the model stops calling tools or the maximum number of iterations is reached.
+func (r *ToolRunner) Run(ctx context.Context, messages []ChatMessage) (result ToolRunResult, err error) {
+	return r.run(ctx, messages, nil)
+}
+
+// RunStream does the same as `Run`, but with streaming.
+//
+// `cb` receives deltas of all chat completions, and the final answer with `done` == true.
+func (r *ToolRunner) RunStream(ctx context.Context, messages []ChatMessage, cb callback) (result ToolRunResult, err error) {
+	return r.run(ctx, messages, cb)
+}
+
+// runs the loop, with streaming if `cb` is not nil
+func (r *ToolRunner) run(ctx context.Context, messages []ChatMessage, cb callback) (result ToolRunResult, err error) {
+	result.Messages = append([]ChatMessage{}, messages...)
+
+	for result.Iterations < r.maxIterations {
+		options := ChatCompletionOptions{}
+		for k, v := range r.options {
+			options[k] = v
+		}
+		delete(options, "stream")
+		if len(r.tools) > 0 {
+			options.SetTools(r.tools)
+		}
+
+		result.Iterations++
+		if cb != nil {
+			result.Completion, err = r.streamCompletion(ctx, result.Messages, options, cb)
+		} else {
+			result.Completion, err = r.client.CreateChatCompletionWithContext(ctx, r.model, result.Messages, options)
+		}
+		if err != nil {
+			if cb != nil {
+				cb(ChatCompletion{}, true, err)
+			}
+			return result, err
+		}
+		if len(result.Completion.Choices) <= 0 {
+			err = fmt.Errorf("no choice in chat completion")
+			if cb != nil {
+				cb(ChatCompletion{}, true, err)
+			}
+			return result, err
+		}
+
+		message := result.Completion.Choices[0].Message
+		result.Messages = append(result.Messages, message)
+
+		if len(message.ToolCalls) <= 0 {
+			if cb != nil {
+				cb(result.Completion, true, nil)
+			}
+			return result, nil
+		}
+
+		result.Messages = append(result.Messages, r.runToolCalls(ctx, message.ToolCalls)...)
+
+		if err = ctx.Err(); err != nil {
+			if cb != nil {
+				cb(ChatCompletion{}, true, err)
+			}
+			return result, err
+		}
+	}
+
+	if cb != nil {
+		cb(ChatCompletion{}, true, ErrMaxIterationsReached)
+	}
+	return result, ErrMaxIterationsReached
+}
+
+// requests a streaming chat completion, forwarding deltas to `cb`, and returns the accumulated one
+func (r *ToolRunner) streamCompletion(ctx context.Context, messages []ChatMessage, options ChatCompletionOptions, cb callback) (completion ChatCompletion, err error) {
+	var content strings.Builder
+	var last ChatCompletion
+	var streamErr error
+	finishReason := ""
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	if err = r.client.CreateChatCompletionStreamWithContext(ctx, r.model, messages, options, func(response ChatCompletion, isDone bool, err error) {
+		if err != nil {
+			streamErr = err
+			once.Do(func() { close(done) })
+			return
+		}
+
+		if len(response.Choices) > 0 {
+			if delta, ok := response.Choices[0].Delta.Content.(string); ok {
+				content.WriteString(delta)
+			}
+			if response.Choices[0].FinishReason != "" {
+				finishReason = response.Choices[0].FinishReason
+			}
+		}
+		if response.ID != "" {
+			last = response
+		}
+
+		if isDone {
+			if len(response.Choices) > 0 && len(response.Choices[0].Message.ToolCalls) > 0 {
+				last.Choices = response.Choices
+			}
+			once.Do(func() { close(done) })
+			return
+		}
+
+		cb(response, false, nil)
+	}); err != nil {
+		return ChatCompletion{}, err
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ChatCompletion{}, ctx.Err()
+	}
+	if streamErr != nil {
+		return ChatCompletion{}, streamErr
+	}
+
+	message := ChatMessage{
+		Role: ChatMessageRoleAssistant,
+	}
+	if content.Len() > 0 {
+		message.Content = content.String()
+	}
+	if len(last.Choices) > 0 {
+		for _, toolCall := range last.Choices[0].Message.ToolCalls {
+			if toolCall.ID == "" {
+				continue
+			}
+			toolCall.Index = nil
+			message.ToolCalls = append(message.ToolCalls, toolCall)
+		}
+	}
+
+	completion = last
+	completion.Choices = []ChatCompletionChoice{{
+		Message:      message,
+		FinishReason: finishReason,
+	}}
+
+	return completion, nil
+}
+
+// runs tool calls in parallel, and returns tool messages in the same order
+func (r *ToolRunner) runToolCalls(ctx context.Context, toolCalls []ToolCall) (messages []ChatMessage) {
+	outputs := r.dispatchAll(ctx, toolCalls)
+
+	messages = make([]ChatMessage, len(toolCalls))
+	for i, toolCall := range toolCalls {
+		messages[i] = NewChatToolMessage(toolCall.ID, outputs[i])
+	}
+
+	return messages
+}
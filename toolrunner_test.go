@@ -0,0 +1,199 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestToolRunner(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Tools    []ChatCompletionTool `json:"tools"`
+			Messages []struct {
+				Role       string `json:"role"`
+				Content    string `json:"content"`
+				ToolCallID string `json:"tool_call_id"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(requestBody.Tools) != 3 {
+			t.Errorf("Expected 3 tools, got %d", len(requestBody.Tools))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","tool_calls":[
+				{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"location\":\"Seoul\"}"}},
+				{"id":"call_2","type":"function","function":{"name":"explode","arguments":"{}"}},
+				{"id":"call_3","type":"function","function":{"name":"delete_files","arguments":"{}"}},
+				{"id":"call_4","type":"function","function":{"name":"unknown","arguments":"{}"}}
+			]},"finish_reason":"tool_calls"}]}`))
+		default:
+			outputs := map[string]string{}
+			for _, message := range requestBody.Messages {
+				if message.Role == string(ChatMessageRoleTool) {
+					outputs[message.ToolCallID] = message.Content
+				}
+			}
+			if outputs["call_1"] != `{"location":"Seoul","weather":"sunny"}` {
+				t.Errorf("Unexpected output of call_1: %s", outputs["call_1"])
+			}
+			if !strings.Contains(outputs["call_2"], "panicked") {
+				t.Errorf("Expected panic to be recovered, got: %s", outputs["call_2"])
+			}
+			if !strings.Contains(outputs["call_3"], "denied") {
+				t.Errorf("Expected call to be denied, got: %s", outputs["call_3"])
+			}
+			if !strings.Contains(outputs["call_4"], "no such tool") {
+				t.Errorf("Expected unknown tool error, got: %s", outputs["call_4"])
+			}
+
+			w.Write([]byte(`{"id":"chatcmpl-2","choices":[{"index":0,"message":{"role":"assistant","content":"It is sunny in Seoul."},"finish_reason":"stop"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	type weatherArgs struct {
+		Location string `json:"location"`
+	}
+
+	runner := NewToolRunner(client, "gpt-4o").
+		Register(NewChatCompletionTool("get_weather", "Get current weather of a location",
+			NewToolFunctionParameters().
+				AddPropertyWithDescription("location", "string", "City name").
+				SetRequiredParameters([]string{"location"})),
+			NewToolHandler(func(ctx context.Context, args weatherArgs) (any, error) {
+				return map[string]string{"location": args.Location, "weather": "sunny"}, nil
+			})).
+		Register(NewChatCompletionTool("explode", "Always panics", NewToolFunctionParameters()),
+			func(ctx context.Context, call ToolCall) (string, error) {
+				panic("boom")
+			}).
+		Register(NewChatCompletionTool("delete_files", "Deletes all files", NewToolFunctionParameters()),
+			func(ctx context.Context, call ToolCall) (string, error) {
+				t.Errorf("Expected denied tool not to be called")
+				return "", nil
+			}).
+		SetApprover(func(ctx context.Context, call ToolCall) error {
+			if call.Function.Name == "delete_files" {
+				return fmt.Errorf("not allowed")
+			}
+			return nil
+		}).
+		SetMaxConcurrency(2)
+
+	result, err := runner.Run(context.Background(), []ChatMessage{NewChatUserMessage("What is the weather in Seoul?")})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Expected 2 iterations, got %d", result.Iterations)
+	}
+	// user + assistant (tool calls) + 4 tool results + assistant
+	if len(result.Messages) != 7 {
+		t.Errorf("Expected 7 messages, got %d", len(result.Messages))
+	}
+	if answer, _ := result.Completion.Choices[0].Message.ContentString(); answer != "It is sunny in Seoul." {
+		t.Errorf("Unexpected final answer: %s", answer)
+	}
+}
+
+func TestToolRunnerMaxIterations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","tool_calls":[
+			{"id":"call_1","type":"function","function":{"name":"again","arguments":"{}"}}
+		]},"finish_reason":"tool_calls"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	runner := NewToolRunner(client, "gpt-4o").
+		Register(NewChatCompletionTool("again", "Call me again", NewToolFunctionParameters()),
+			func(ctx context.Context, call ToolCall) (string, error) {
+				return "call me again", nil
+			}).
+		SetMaxIterations(3)
+
+	result, err := runner.Run(context.Background(), []ChatMessage{NewChatUserMessage("Loop forever.")})
+	if !errors.Is(err, ErrMaxIterationsReached) {
+		t.Errorf("Expected ErrMaxIterationsReached, got %v", err)
+	}
+	if result.Iterations != 3 {
+		t.Errorf("Expected 3 iterations, got %d", result.Iterations)
+	}
+}
+
+func TestToolRunnerStream(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_time","arguments":""}}]}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{}"}}]}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`+"\n\n")
+		default:
+			fmt.Fprint(w, `data: {"id":"chatcmpl-2","choices":[{"index":0,"delta":{"role":"assistant","content":"It is "}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"id":"chatcmpl-2","choices":[{"index":0,"delta":{"content":"noon."}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"id":"chatcmpl-2","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	runner := NewToolRunner(client, "gpt-4o").
+		Register(NewChatCompletionTool("get_time", "Get current time", NewToolFunctionParameters()),
+			func(ctx context.Context, call ToolCall) (string, error) {
+				return "12:00", nil
+			})
+
+	var streamed strings.Builder
+	var final string
+	result, err := runner.RunStream(context.Background(), []ChatMessage{NewChatUserMessage("What time is it?")}, func(response ChatCompletion, done bool, err error) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		if done {
+			final, _ = response.Choices[0].Message.ContentString()
+			return
+		}
+		if len(response.Choices) > 0 {
+			if delta, ok := response.Choices[0].Delta.Content.(string); ok {
+				streamed.WriteString(delta)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Expected 2 iterations, got %d", result.Iterations)
+	}
+	if streamed.String() != "It is noon." || final != "It is noon." {
+		t.Errorf("Unexpected streamed answer: '%s' / '%s'", streamed.String(), final)
+	}
+	if toolCalls := result.Messages[1].ToolCalls; len(toolCalls) != 1 || toolCalls[0].Function.Arguments != "{}" {
+		t.Errorf("Expected accumulated tool call, got %+v", toolCalls)
+	}
+}
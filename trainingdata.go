@@ -0,0 +1,389 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// builders of training data (JSONL) for fine-tuning
+//
+// https://platform.openai.com/docs/api-reference/fine-tuning/chat-input
+// https://platform.openai.com/docs/api-reference/fine-tuning/preference-input
+
+// TrainingMessage struct for TrainingExample
+type TrainingMessage struct {
+	ChatMessage
+
+	// 0 or 1, only for messages with role: 'assistant'
+	Weight *int `json:"weight,omitempty"`
+}
+
+// TrainingExample struct for supervised fine-tuning
+type TrainingExample struct {
+	Messages          []TrainingMessage    `json:"messages"`
+	Tools             []ChatCompletionTool `json:"tools,omitempty"`
+	ParallelToolCalls *bool                `json:"parallel_tool_calls,omitempty"`
+}
+
+// NewTrainingExample returns a new TrainingExample with given chat `messages`.
+func NewTrainingExample(messages []ChatMessage) TrainingExample {
+	converted := make([]TrainingMessage, len(messages))
+	for i, message := range messages {
+		converted[i] = TrainingMessage{
+			ChatMessage: normalizedTrainingMessage(message),
+		}
+	}
+
+	return TrainingExample{
+		Messages: converted,
+	}
+}
+
+// SetTools sets the `tools` value of TrainingExample and returns it.
+//
+// `parallel_tool_calls` is also set to true if any assistant message has multiple tool calls.
+func (e TrainingExample) SetTools(tools []ChatCompletionTool) TrainingExample {
+	e.Tools = tools
+
+	for _, message := range e.Messages {
+		if len(message.ToolCalls) > 1 {
+			parallel := true
+			e.ParallelToolCalls = &parallel
+			break
+		}
+	}
+	return e
+}
+
+// SetParallelToolCalls sets the `parallel_tool_calls` value of TrainingExample and returns it.
+func (e TrainingExample) SetParallelToolCalls(parallel bool) TrainingExample {
+	e.ParallelToolCalls = &parallel
+	return e
+}
+
+// SetWeight sets the `weight` value of the message at `index` and returns the TrainingExample.
+//
+// `weight` should be 0 (not trained on) or 1, and only assistant messages can have it.
+func (e TrainingExample) SetWeight(index, weight int) TrainingExample {
+	messages := make([]TrainingMessage, len(e.Messages))
+	copy(messages, e.Messages)
+	if index >= 0 && index < len(messages) {
+		messages[index].Weight = &weight
+	}
+	e.Messages = messages
+	return e
+}
+
+// validate checks if TrainingExample is valid for fine-tuning.
+func (e TrainingExample) validate() error {
+	if len(e.Messages) <= 0 {
+		return fmt.Errorf("no messages in training example")
+	}
+	if err := validateTrainingTools(e.Tools); err != nil {
+		return err
+	}
+
+	hasAssistant := false
+	for i, message := range e.Messages {
+		if err := validateTrainingMessage(message.ChatMessage); err != nil {
+			return fmt.Errorf("invalid message at index %d: %s", i, err)
+		}
+
+		if message.Role == ChatMessageRoleAssistant {
+			hasAssistant = true
+		}
+		if message.Weight != nil {
+			if message.Role != ChatMessageRoleAssistant {
+				return fmt.Errorf("`weight` is set on a non-assistant message at index %d", i)
+			}
+			if *message.Weight != 0 && *message.Weight != 1 {
+				return fmt.Errorf("`weight` should be 0 or 1, got %d at index %d", *message.Weight, i)
+			}
+		}
+	}
+	if !hasAssistant {
+		return fmt.Errorf("no assistant message in training example")
+	}
+
+	return nil
+}
+
+// PreferenceExample struct for DPO fine-tuning
+type PreferenceExample struct {
+	Input              PreferenceExampleInput `json:"input"`
+	PreferredOutput    []ChatMessage          `json:"preferred_output"`
+	NonPreferredOutput []ChatMessage          `json:"non_preferred_output"`
+}
+
+// PreferenceExampleInput struct for PreferenceExample
+type PreferenceExampleInput struct {
+	Messages          []ChatMessage        `json:"messages"`
+	Tools             []ChatCompletionTool `json:"tools,omitempty"`
+	ParallelToolCalls *bool                `json:"parallel_tool_calls,omitempty"`
+}
+
+// NewPreferenceExample returns a new PreferenceExample with given input `messages`,
+// and `chosen`/`rejected` assistant messages.
+func NewPreferenceExample(messages []ChatMessage, chosen, rejected ChatMessage) PreferenceExample {
+	input := make([]ChatMessage, len(messages))
+	for i, message := range messages {
+		input[i] = normalizedTrainingMessage(message)
+	}
+
+	return PreferenceExample{
+		Input: PreferenceExampleInput{
+			Messages: input,
+		},
+		PreferredOutput:    []ChatMessage{normalizedTrainingMessage(chosen)},
+		NonPreferredOutput: []ChatMessage{normalizedTrainingMessage(rejected)},
+	}
+}
+
+// NewPreferenceExampleFromCompletions returns a new PreferenceExample with given input `messages`,
+// and the first choices of `chosen`/`rejected` chat completions.
+func NewPreferenceExampleFromCompletions(messages []ChatMessage, chosen, rejected ChatCompletion) (example PreferenceExample, err error) {
+	if len(chosen.Choices) <= 0 {
+		return PreferenceExample{}, fmt.Errorf("no choice in chosen completion")
+	}
+	if len(rejected.Choices) <= 0 {
+		return PreferenceExample{}, fmt.Errorf("no choice in rejected completion")
+	}
+
+	return NewPreferenceExample(messages, chosen.Choices[0].Message, rejected.Choices[0].Message), nil
+}
+
+// SetTools sets the `tools` value of PreferenceExample's input and returns it.
+func (e PreferenceExample) SetTools(tools []ChatCompletionTool) PreferenceExample {
+	e.Input.Tools = tools
+	return e
+}
+
+// SetParallelToolCalls sets the `parallel_tool_calls` value of PreferenceExample's input and returns it.
+func (e PreferenceExample) SetParallelToolCalls(parallel bool) PreferenceExample {
+	e.Input.ParallelToolCalls = &parallel
+	return e
+}
+
+// validate checks if PreferenceExample is valid for fine-tuning.
+func (e PreferenceExample) validate() error {
+	if len(e.Input.Messages) <= 0 {
+		return fmt.Errorf("no input messages in preference example")
+	}
+	if err := validateTrainingTools(e.Input.Tools); err != nil {
+		return err
+	}
+	for i, message := range e.Input.Messages {
+		if err := validateTrainingMessage(message); err != nil {
+			return fmt.Errorf("invalid input message at index %d: %s", i, err)
+		}
+	}
+
+	for name, outputs := range map[string][]ChatMessage{
+		"preferred_output":     e.PreferredOutput,
+		"non_preferred_output": e.NonPreferredOutput,
+	} {
+		if len(outputs) != 1 {
+			return fmt.Errorf("`%s` should have exactly one message, got %d", name, len(outputs))
+		}
+		if outputs[0].Role != ChatMessageRoleAssistant {
+			return fmt.Errorf("`%s` should be an assistant message, got role: '%s'", name, outputs[0].Role)
+		}
+		if err := validateTrainingMessage(outputs[0]); err != nil {
+			return fmt.Errorf("invalid `%s`: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// TrainingDataExample interface for type constraints in `TrainingDataBuilder`
+type TrainingDataExample interface {
+	TrainingExample | PreferenceExample
+
+	validate() error
+}
+
+// TrainingDataBuilder collects deduplicated training examples and writes them as JSONL.
+type TrainingDataBuilder[T TrainingDataExample] struct {
+	examples   []T
+	seen       map[string]bool
+	duplicates int
+}
+
+// NewTrainingDataBuilder returns a new builder for supervised fine-tuning data.
+func NewTrainingDataBuilder() *TrainingDataBuilder[TrainingExample] {
+	return &TrainingDataBuilder[TrainingExample]{
+		seen: map[string]bool{},
+	}
+}
+
+// NewPreferenceDataBuilder returns a new builder for DPO fine-tuning data.
+func NewPreferenceDataBuilder() *TrainingDataBuilder[PreferenceExample] {
+	return &TrainingDataBuilder[PreferenceExample]{
+		seen: map[string]bool{},
+	}
+}
+
+// Add validates and adds given `example`.
+//
+// Returns false (with nil error) if the same example was already added.
+func (b *TrainingDataBuilder[T]) Add(example T) (added bool, err error) {
+	if err = example.validate(); err != nil {
+		return false, err
+	}
+
+	var serialized []byte
+	if serialized, err = json.Marshal(example); err != nil {
+		return false, fmt.Errorf("failed to serialize training example: %s", err)
+	}
+
+	key := string(serialized)
+	if b.seen[key] {
+		b.duplicates++
+		return false, nil
+	}
+	b.seen[key] = true
+	b.examples = append(b.examples, example)
+
+	return true, nil
+}
+
+// Len returns the number of added examples.
+func (b *TrainingDataBuilder[T]) Len() int {
+	return len(b.examples)
+}
+
+// Duplicates returns the number of examples which were skipped as duplicates.
+func (b *TrainingDataBuilder[T]) Duplicates() int {
+	return b.duplicates
+}
+
+// Examples returns the added examples.
+func (b *TrainingDataBuilder[T]) Examples() []T {
+	return b.examples
+}
+
+// Split shuffles added examples with `seed` and holds out `validationRatio` (0.0 ~ 1.0) of them for validation.
+//
+// Same `seed` always yields the same split.
+func (b *TrainingDataBuilder[T]) Split(validationRatio float64, seed int64) (training, validation []T) {
+	shuffled := make([]T, len(b.examples))
+	copy(shuffled, b.examples)
+	rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	numValidation := int(math.Round(float64(len(shuffled)) * validationRatio))
+	if numValidation <= 0 && validationRatio > 0 && len(shuffled) > 1 {
+		numValidation = 1
+	}
+	if numValidation > len(shuffled) {
+		numValidation = len(shuffled)
+	}
+
+	return shuffled[numValidation:], shuffled[:numValidation]
+}
+
+// JSONL returns all added examples as JSONL bytes.
+func (b *TrainingDataBuilder[T]) JSONL() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteTrainingJSONL(&buf, b.examples); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SplitJSONL does the same as `Split` and returns both sets as JSONL bytes.
+func (b *TrainingDataBuilder[T]) SplitJSONL(validationRatio float64, seed int64) (training, validation []byte, err error) {
+	trainingExamples, validationExamples := b.Split(validationRatio, seed)
+
+	var trainingBuf, validationBuf bytes.Buffer
+	if err = WriteTrainingJSONL(&trainingBuf, trainingExamples); err != nil {
+		return nil, nil, err
+	}
+	if err = WriteTrainingJSONL(&validationBuf, validationExamples); err != nil {
+		return nil, nil, err
+	}
+
+	return trainingBuf.Bytes(), validationBuf.Bytes(), nil
+}
+
+// WriteTrainingJSONL writes given `examples` to `w`, one JSON object per line.
+func WriteTrainingJSONL[T TrainingDataExample](w io.Writer, examples []T) (err error) {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	for i, example := range examples {
+		if err = encoder.Encode(example); err != nil {
+			return fmt.Errorf("failed to encode training example at index %d: %s", i, err)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// returns a copy of given message with stream-only values removed
+func normalizedTrainingMessage(message ChatMessage) ChatMessage {
+	if len(message.ToolCalls) > 0 {
+		toolCalls := make([]ToolCall, len(message.ToolCalls))
+		for i, toolCall := range message.ToolCalls {
+			toolCall.Index = nil
+			if toolCall.Type == "" {
+				toolCall.Type = "function"
+			}
+			toolCalls[i] = toolCall
+		}
+		message.ToolCalls = toolCalls
+	}
+
+	return message
+}
+
+// checks if given message is valid for training data
+func validateTrainingMessage(message ChatMessage) error {
+	switch message.Role {
+	case ChatMessageRoleSystem, ChatMessageRoleUser:
+		if message.Content == nil {
+			return fmt.Errorf("no content in '%s' message", message.Role)
+		}
+	case ChatMessageRoleAssistant:
+		if message.Content == nil && len(message.ToolCalls) <= 0 {
+			return fmt.Errorf("no content or tool calls in assistant message")
+		}
+		for _, toolCall := range message.ToolCalls {
+			if toolCall.ID == "" || toolCall.Function.Name == "" {
+				return fmt.Errorf("tool call without id or function name in assistant message")
+			}
+		}
+	case ChatMessageRoleTool:
+		if message.ToolCallID == nil {
+			return fmt.Errorf("no `tool_call_id` in tool message")
+		}
+	default:
+		return fmt.Errorf("unsupported role: '%s'", message.Role)
+	}
+
+	return nil
+}
+
+// checks if given tools are valid for training data
+func validateTrainingTools(tools []ChatCompletionTool) error {
+	names := map[string]bool{}
+	for _, tool := range tools {
+		if tool.Function.Name == "" {
+			return fmt.Errorf("tool without function name")
+		}
+		if names[tool.Function.Name] {
+			return fmt.Errorf("duplicated tool name: '%s'", tool.Function.Name)
+		}
+		names[tool.Function.Name] = true
+	}
+
+	return nil
+}
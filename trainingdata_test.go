@@ -0,0 +1,157 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestTrainingDataBuilder(t *testing.T) {
+	weatherTool := NewChatCompletionTool("get_weather", "Get current weather of a location",
+		NewToolFunctionParameters().
+			AddPropertyWithDescription("location", "string", "City name").
+			SetRequiredParameters([]string{"location"}))
+
+	index := 1
+	conversation := []ChatMessage{
+		NewChatSystemMessage("You are a weather bot."),
+		NewChatUserMessage("Weather in Seoul and Tokyo?"),
+		{
+			Role: ChatMessageRoleAssistant,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Seoul"}`}},
+				{Index: &index, ID: "call_2", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Tokyo"}`}},
+			},
+		},
+		NewChatToolMessage("call_1", "sunny"),
+		NewChatToolMessage("call_2", "rainy"),
+		NewChatAssistantMessage("Seoul is sunny, and Tokyo is rainy."),
+	}
+
+	builder := NewTrainingDataBuilder()
+
+	example := NewTrainingExample(conversation).
+		SetTools([]ChatCompletionTool{weatherTool}).
+		SetWeight(2, 0)
+	if added, err := builder.Add(example); err != nil || !added {
+		t.Fatalf("failed to add training example: %v", err)
+	}
+	if example.ParallelToolCalls == nil || !*example.ParallelToolCalls {
+		t.Errorf("Expected parallel_tool_calls to be set for parallel tool calls")
+	}
+
+	// duplicated one should be skipped
+	if added, err := builder.Add(NewTrainingExample(conversation).SetTools([]ChatCompletionTool{weatherTool}).SetWeight(2, 0)); err != nil || added {
+		t.Errorf("Expected duplicated example to be skipped, got added: %v, err: %v", added, err)
+	}
+	if builder.Len() != 1 || builder.Duplicates() != 1 {
+		t.Errorf("Expected 1 example and 1 duplicate, got %d and %d", builder.Len(), builder.Duplicates())
+	}
+
+	// invalid ones should fail
+	if _, err := builder.Add(NewTrainingExample(conversation[:2])); err == nil {
+		t.Errorf("Expected error for example without assistant message")
+	}
+	if _, err := builder.Add(NewTrainingExample(conversation).SetWeight(1, 0)); err == nil {
+		t.Errorf("Expected error for weight on a user message")
+	}
+
+	jsonl, err := builder.JSONL()
+	if err != nil {
+		t.Fatalf("failed to build jsonl: %v", err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(jsonl), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line, got %d", len(lines))
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(lines[0], &decoded); err != nil {
+		t.Fatalf("failed to decode jsonl line: %v", err)
+	}
+	messages := decoded["messages"].([]any)
+	assistant := messages[2].(map[string]any)
+	if assistant["weight"] != float64(0) {
+		t.Errorf("Expected weight 0 on assistant message, got %v", assistant["weight"])
+	}
+	toolCalls := assistant["tool_calls"].([]any)
+	if len(toolCalls) != 2 {
+		t.Errorf("Expected 2 tool calls, got %d", len(toolCalls))
+	}
+	if second := toolCalls[1].(map[string]any); second["index"] != nil || second["type"] != "function" {
+		t.Errorf("Expected normalized tool call, got %v", second)
+	}
+	if _, exists := messages[5].(map[string]any)["weight"]; exists {
+		t.Errorf("Expected no weight on the last assistant message")
+	}
+	if tools := decoded["tools"].([]any); len(tools) != 1 {
+		t.Errorf("Expected 1 tool, got %d", len(tools))
+	}
+}
+
+func TestTrainingDataBuilderSplit(t *testing.T) {
+	builder := NewTrainingDataBuilder()
+	for i := 0; i < 10; i++ {
+		if _, err := builder.Add(NewTrainingExample([]ChatMessage{
+			NewChatUserMessage(fmt.Sprintf("question %d", i)),
+			NewChatAssistantMessage(fmt.Sprintf("answer %d", i)),
+		})); err != nil {
+			t.Fatalf("failed to add training example: %v", err)
+		}
+	}
+
+	training, validation := builder.Split(0.2, 42)
+	if len(training) != 8 || len(validation) != 2 {
+		t.Errorf("Expected 8/2 split, got %d/%d", len(training), len(validation))
+	}
+
+	// same seed, same split
+	_, again := builder.Split(0.2, 42)
+	for i := range validation {
+		if fmt.Sprintf("%v", validation[i]) != fmt.Sprintf("%v", again[i]) {
+			t.Errorf("Expected same split with same seed")
+		}
+	}
+
+	trainingJSONL, validationJSONL, err := builder.SplitJSONL(0.2, 42)
+	if err != nil {
+		t.Fatalf("failed to split jsonl: %v", err)
+	}
+	if bytes.Count(trainingJSONL, []byte("\n")) != 8 || bytes.Count(validationJSONL, []byte("\n")) != 2 {
+		t.Errorf("Unexpected number of lines in split jsonl")
+	}
+}
+
+func TestPreferenceDataBuilder(t *testing.T) {
+	input := []ChatMessage{NewChatUserMessage("Say hello.")}
+
+	builder := NewPreferenceDataBuilder()
+	if _, err := builder.Add(NewPreferenceExample(input, NewChatAssistantMessage("Hello!"), NewChatAssistantMessage("No."))); err != nil {
+		t.Fatalf("failed to add preference example: %v", err)
+	}
+	if _, err := builder.Add(NewPreferenceExample(input, NewChatUserMessage("Hello!"), NewChatAssistantMessage("No."))); err == nil {
+		t.Errorf("Expected error for non-assistant preferred output")
+	}
+
+	chosen := ChatCompletion{Choices: []ChatCompletionChoice{{Message: NewChatAssistantMessage("Hi there!")}}}
+	if _, err := NewPreferenceExampleFromCompletions(input, chosen, ChatCompletion{}); err == nil {
+		t.Errorf("Expected error for completion without choices")
+	}
+
+	jsonl, err := builder.JSONL()
+	if err != nil {
+		t.Fatalf("failed to build jsonl: %v", err)
+	}
+
+	var decoded PreferenceExample
+	if err := json.Unmarshal(bytes.TrimSpace(jsonl), &decoded); err != nil {
+		t.Fatalf("failed to decode jsonl: %v", err)
+	}
+	if preferred, _ := decoded.PreferredOutput[0].ContentString(); preferred != "Hello!" {
+		t.Errorf("Expected preferred output 'Hello!', got %s", preferred)
+	}
+	if len(decoded.Input.Messages) != 1 {
+		t.Errorf("Expected 1 input message, got %d", len(decoded.Input.Messages))
+	}
+}
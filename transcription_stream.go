@@ -0,0 +1,94 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// streaming transcription
+
+// TranscriptionStreamEventType type for constants
+type TranscriptionStreamEventType string
+
+const (
+	TranscriptionStreamEventTextDelta TranscriptionStreamEventType = "transcript.text.delta"
+	TranscriptionStreamEventTextDone  TranscriptionStreamEventType = "transcript.text.done"
+	TranscriptionStreamEventError     TranscriptionStreamEventType = "error"
+)
+
+// TranscriptionStreamEvent struct for server-sent events of transcription stream
+//
+// https://platform.openai.com/docs/api-reference/audio/transcript-text-delta-event
+type TranscriptionStreamEvent struct {
+	Type TranscriptionStreamEventType `json:"type"`
+
+	Delta string `json:"delta,omitempty"` // for `transcript.text.delta`
+	Text  string `json:"text,omitempty"`  // for `transcript.text.done`
+
+	// with `include[]` of `logprobs`
+	Logprobs []TranscriptionLogprob `json:"logprobs,omitempty"`
+
+	Usage *TranscriptionUsage `json:"usage,omitempty"` // for `transcript.text.done`
+	Error *Error              `json:"error,omitempty"`
+}
+
+// Transcription returns the final result of a `transcript.text.done` event as a Transcription.
+func (e TranscriptionStreamEvent) Transcription() Transcription {
+	text := e.Text
+	return Transcription{
+		Text:     &text,
+		Logprobs: e.Logprobs,
+		Usage:    e.Usage,
+	}
+}
+
+func (e TranscriptionStreamEvent) withName(name string) TranscriptionStreamEvent {
+	if e.Type == "" {
+		e.Type = TranscriptionStreamEventType(name)
+	}
+	return e
+}
+
+func (e TranscriptionStreamEvent) eventError() *Error {
+	return e.Error
+}
+
+func (e TranscriptionStreamEvent) isLast() bool {
+	return e.Type == TranscriptionStreamEventTextDone
+}
+
+// callback function for transcription stream events
+type transcriptionStreamCallback func(event TranscriptionStreamEvent, done bool, err error)
+
+// CreateTranscriptionStream transcribes given audio file into the input language, and streams its events to `cb`.
+//
+// `stream` is set to true, and `cb` is called with `done` == true on the last event (`transcript.text.done`).
+//
+// Only newer models (eg. `gpt-4o-transcribe`) support streaming; `whisper-1` ignores it,
+// so an error is returned when the response is not an event stream.
+//
+// https://platform.openai.com/docs/api-reference/audio/createTranscription#audio-createtranscription-stream
+func (c *Client) CreateTranscriptionStream(ctx context.Context, file FileParam, model string, options TranscriptionOptions, cb transcriptionStreamCallback) (err error) {
+	if options == nil {
+		options = TranscriptionOptions{}
+	}
+	options["file"] = file
+	options["model"] = model
+	options["stream"] = true
+
+	var resp *http.Response
+	if resp, err = c.openStreamWithContext(ctx, "audio/transcriptions", options); err != nil {
+		return err
+	}
+
+	if contentType := resp.Header.Get(kContentType); !strings.HasPrefix(contentType, "text/event-stream") {
+		resp.Body.Close()
+		return fmt.Errorf("response is not an event stream (Content-Type: '%s'), model '%s' may not support streaming", contentType, model)
+	}
+
+	streamTypedEvents(ctx, resp, cb)
+
+	return nil
+}
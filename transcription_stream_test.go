@@ -0,0 +1,130 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateTranscriptionStreamMock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+			return
+		}
+		if r.FormValue("stream") != "true" || r.FormValue("model") != "gpt-4o-transcribe" {
+			t.Errorf("Unexpected form values: %v", r.MultipartForm.Value)
+		}
+		if include := r.MultipartForm.Value["include[]"]; len(include) != 1 || include[0] != "logprobs" {
+			t.Errorf("Expected include[]: [logprobs], got %v", include)
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			t.Errorf("Expected a file part: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hello\",\"logprobs\":[{\"token\":\"Hello\",\"logprob\":-0.1}]}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\" world.\",\"logprobs\":[{\"token\":\" world.\",\"logprob\":-0.2}]}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.done\",\"text\":\"Hello world.\",\"logprobs\":[{\"token\":\"Hello\",\"logprob\":-0.1},{\"token\":\" world.\",\"logprob\":-0.2}],\"usage\":{\"type\":\"tokens\",\"input_tokens\":10,\"output_tokens\":3,\"total_tokens\":13}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	deltas := []string{}
+	var result Transcription
+	finished := make(chan error, 1)
+	if err := client.CreateTranscriptionStream(context.Background(), NewFileParamFromBytes([]byte("RIFF....WAVE")), "gpt-4o-transcribe", TranscriptionOptions{}.
+		SetInclude(TranscriptionIncludeLogprobs), func(event TranscriptionStreamEvent, done bool, err error) {
+		if err == nil {
+			switch event.Type {
+			case TranscriptionStreamEventTextDelta:
+				deltas = append(deltas, event.Delta)
+				if len(event.Logprobs) != 1 {
+					t.Errorf("Expected 1 logprob in delta, got %+v", event.Logprobs)
+				}
+			case TranscriptionStreamEventTextDone:
+				result = event.Transcription()
+			}
+		}
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateTranscriptionStream failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+	if strings.Join(deltas, "") != "Hello world." {
+		t.Errorf("Unexpected deltas: %q", deltas)
+	}
+	if result.Text == nil || *result.Text != "Hello world." || len(result.Logprobs) != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.Usage == nil || result.Usage.TotalTokens != 13 {
+		t.Errorf("Unexpected usage: %+v", result.Usage)
+	}
+}
+
+func TestCreateTranscriptionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hel\"}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"error\",\"error\":{\"message\":\"Audio is corrupted.\",\"type\":\"invalid_request_error\"}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	finished := make(chan error, 1)
+	if err := client.CreateTranscriptionStream(context.Background(), NewFileParamFromBytes([]byte("RIFF....WAVE")), "gpt-4o-mini-transcribe", nil, func(event TranscriptionStreamEvent, done bool, err error) {
+		if done {
+			finished <- err
+		}
+	}); err != nil {
+		t.Fatalf("CreateTranscriptionStream failed: %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err == nil || !strings.Contains(err.Error(), "Audio is corrupted.") {
+			t.Errorf("Expected stream error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream did not finish")
+	}
+}
+
+func TestCreateTranscriptionStreamNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// `whisper-1` ignores `stream`
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"text":"Hello world."}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	if err := client.CreateTranscriptionStream(context.Background(), NewFileParamFromBytes([]byte("RIFF....WAVE")), "whisper-1", nil, func(event TranscriptionStreamEvent, done bool, err error) {
+		t.Errorf("Unexpected callback: %+v, %v", event, err)
+	}); err == nil || !strings.Contains(err.Error(), "not an event stream") {
+		t.Errorf("Expected an error for a non-stream response, got %v", err)
+	}
+}
@@ -0,0 +1,1045 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// https://platform.openai.com/docs/api-reference/vector-stores
+
+// VectorStoreStatus type for constants
+type VectorStoreStatus string
+
+// VectorStoreStatus constants
+const (
+	VectorStoreStatusExpired    VectorStoreStatus = "expired"
+	VectorStoreStatusInProgress VectorStoreStatus = "in_progress"
+	VectorStoreStatusCompleted  VectorStoreStatus = "completed"
+)
+
+// VectorStore struct for vector store object
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/object
+type VectorStore struct {
+	CommonResponse
+
+	ID           string                   `json:"id"`
+	CreatedAt    int                      `json:"created_at"`
+	Name         string                   `json:"name"`
+	UsageBytes   int                      `json:"usage_bytes"`
+	FileCounts   VectorStoreFileCounts    `json:"file_counts"`
+	Status       VectorStoreStatus        `json:"status"`
+	ExpiresAfter *VectorStoreExpiresAfter `json:"expires_after,omitempty"`
+	ExpiresAt    *int                     `json:"expires_at,omitempty"`
+	LastActiveAt *int                     `json:"last_active_at,omitempty"`
+	Metadata     map[string]string        `json:"metadata"`
+}
+
+// VectorStoreFileCounts struct for VectorStore and VectorStoreFileBatch
+type VectorStoreFileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// VectorStoreExpiresAfter struct for the expiration policy of a vector store
+type VectorStoreExpiresAfter struct {
+	Anchor string `json:"anchor"` // == 'last_active_at'
+	Days   int    `json:"days"`   // 1 ~ 365
+}
+
+// NewVectorStoreExpiresAfter returns a VectorStoreExpiresAfter which expires the vector store
+// after `days` since it was last active.
+func NewVectorStoreExpiresAfter(days int) VectorStoreExpiresAfter {
+	return VectorStoreExpiresAfter{
+		Anchor: "last_active_at",
+		Days:   days,
+	}
+}
+
+// ChunkingStrategy struct for splitting files into chunks
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/createFile#vector-stores-files-createfile-chunking_strategy
+type ChunkingStrategy struct {
+	Type   string                  `json:"type"`             // 'auto' | 'static' | 'other' (for files indexed before chunking strategies)
+	Static *StaticChunkingStrategy `json:"static,omitempty"` // Type == 'static'
+}
+
+// StaticChunkingStrategy struct for ChunkingStrategy
+type StaticChunkingStrategy struct {
+	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"` // 100 ~ 4096 (default: 800)
+	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`  // <= max_chunk_size_tokens / 2 (default: 400)
+}
+
+// NewAutoChunkingStrategy returns a ChunkingStrategy with type: 'auto'.
+func NewAutoChunkingStrategy() ChunkingStrategy {
+	return ChunkingStrategy{
+		Type: "auto",
+	}
+}
+
+// NewStaticChunkingStrategy returns a ChunkingStrategy with type: 'static'.
+func NewStaticChunkingStrategy(maxChunkSizeTokens, chunkOverlapTokens int) ChunkingStrategy {
+	return ChunkingStrategy{
+		Type: "static",
+		Static: &StaticChunkingStrategy{
+			MaxChunkSizeTokens: maxChunkSizeTokens,
+			ChunkOverlapTokens: chunkOverlapTokens,
+		},
+	}
+}
+
+// CreateVectorStoreOptions for creating vector store
+type CreateVectorStoreOptions map[string]any
+
+// SetName sets the `name` parameter of vector store creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create#vector-stores-create-name
+func (o CreateVectorStoreOptions) SetName(name string) CreateVectorStoreOptions {
+	o["name"] = name
+	return o
+}
+
+// SetFileIDs sets the `file_ids` parameter of vector store creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create#vector-stores-create-file_ids
+func (o CreateVectorStoreOptions) SetFileIDs(fileIDs []string) CreateVectorStoreOptions {
+	o["file_ids"] = fileIDs
+	return o
+}
+
+// SetExpiresAfter sets the `expires_after` parameter of vector store creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create#vector-stores-create-expires_after
+func (o CreateVectorStoreOptions) SetExpiresAfter(expiresAfter VectorStoreExpiresAfter) CreateVectorStoreOptions {
+	o["expires_after"] = expiresAfter
+	return o
+}
+
+// SetChunkingStrategy sets the `chunking_strategy` parameter of vector store creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create#vector-stores-create-chunking_strategy
+func (o CreateVectorStoreOptions) SetChunkingStrategy(strategy ChunkingStrategy) CreateVectorStoreOptions {
+	o["chunking_strategy"] = strategy
+	return o
+}
+
+// SetMetadata sets the `metadata` parameter of vector store creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create#vector-stores-create-metadata
+func (o CreateVectorStoreOptions) SetMetadata(metadata map[string]string) CreateVectorStoreOptions {
+	o["metadata"] = metadata
+	return o
+}
+
+// CreateVectorStore creates a vector store with given `options`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/create
+func (c *Client) CreateVectorStore(options CreateVectorStoreOptions) (response VectorStore, err error) {
+	if options == nil {
+		options = CreateVectorStoreOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.post("vector_stores", options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStore{}, err
+}
+
+// ListVectorStoresOptions for listing vector stores
+type ListVectorStoresOptions map[string]any
+
+// SetLimit sets the `limit` parameter of vector stores' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/list#vector-stores-list-limit
+func (o ListVectorStoresOptions) SetLimit(limit int) ListVectorStoresOptions {
+	o["limit"] = limit
+	return o
+}
+
+// SetOrder sets the `order` parameter of vector stores' listing request.
+//
+// `order` can be one of 'asc' or 'desc'. (default: 'desc')
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/list#vector-stores-list-order
+func (o ListVectorStoresOptions) SetOrder(order string) ListVectorStoresOptions {
+	o["order"] = order
+	return o
+}
+
+// SetAfter sets the `after` parameter of vector stores' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/list#vector-stores-list-after
+func (o ListVectorStoresOptions) SetAfter(after string) ListVectorStoresOptions {
+	o["after"] = after
+	return o
+}
+
+// SetBefore sets the `before` parameter of vector stores' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/list#vector-stores-list-before
+func (o ListVectorStoresOptions) SetBefore(before string) ListVectorStoresOptions {
+	o["before"] = before
+	return o
+}
+
+// VectorStores struct for API response
+type VectorStores struct {
+	CommonResponse
+
+	Data    []VectorStore `json:"data"`
+	FirstID string        `json:"first_id"`
+	LastID  string        `json:"last_id"`
+	HasMore bool          `json:"has_more"`
+}
+
+// ListVectorStores lists vector stores with given `options`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/list
+func (c *Client) ListVectorStores(options ListVectorStoresOptions) (response VectorStores, err error) {
+	if options == nil {
+		options = ListVectorStoresOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.get("vector_stores", options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStores{}, err
+}
+
+// RetrieveVectorStore retrieves a vector store with given `vectorStoreID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/retrieve
+func (c *Client) RetrieveVectorStore(vectorStoreID string) (response VectorStore, err error) {
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s", vectorStoreID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStore{}, err
+}
+
+// ModifyVectorStoreOptions for modifying vector store
+type ModifyVectorStoreOptions map[string]any
+
+// SetName sets the `name` parameter of vector store modification.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/modify#vector-stores-modify-name
+func (o ModifyVectorStoreOptions) SetName(name string) ModifyVectorStoreOptions {
+	o["name"] = name
+	return o
+}
+
+// SetExpiresAfter sets the `expires_after` parameter of vector store modification.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/modify#vector-stores-modify-expires_after
+func (o ModifyVectorStoreOptions) SetExpiresAfter(expiresAfter VectorStoreExpiresAfter) ModifyVectorStoreOptions {
+	o["expires_after"] = expiresAfter
+	return o
+}
+
+// SetMetadata sets the `metadata` parameter of vector store modification.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/modify#vector-stores-modify-metadata
+func (o ModifyVectorStoreOptions) SetMetadata(metadata map[string]string) ModifyVectorStoreOptions {
+	o["metadata"] = metadata
+	return o
+}
+
+// ModifyVectorStore modifies a vector store with given `vectorStoreID` and `options`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/modify
+func (c *Client) ModifyVectorStore(vectorStoreID string, options ModifyVectorStoreOptions) (response VectorStore, err error) {
+	if options == nil {
+		options = ModifyVectorStoreOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s", vectorStoreID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStore{}, err
+}
+
+// VectorStoreDeletionStatus struct for API response
+type VectorStoreDeletionStatus struct {
+	CommonResponse
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// DeleteVectorStore deletes a vector store with given `vectorStoreID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/delete
+func (c *Client) DeleteVectorStore(vectorStoreID string) (response VectorStoreDeletionStatus, err error) {
+	var bytes []byte
+	if bytes, err = c.delete(fmt.Sprintf("vector_stores/%s", vectorStoreID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreDeletionStatus{}, err
+}
+
+// VectorStoreComparisonFilter struct for filtering search results by a file attribute
+type VectorStoreComparisonFilter struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"` // 'eq' | 'ne' | 'gt' | 'gte' | 'lt' | 'lte'
+	Value any    `json:"value"`
+}
+
+// VectorStoreCompoundFilter struct for combining multiple filters
+type VectorStoreCompoundFilter struct {
+	Type    string `json:"type"`    // 'and' | 'or'
+	Filters []any  `json:"filters"` // VectorStoreComparisonFilter | VectorStoreCompoundFilter
+}
+
+// NewVectorStoreComparisonFilter returns a new VectorStoreComparisonFilter.
+//
+// `comparison` can be one of 'eq', 'ne', 'gt', 'gte', 'lt', or 'lte'.
+func NewVectorStoreComparisonFilter(key, comparison string, value any) VectorStoreComparisonFilter {
+	return VectorStoreComparisonFilter{
+		Key:   key,
+		Type:  comparison,
+		Value: value,
+	}
+}
+
+// NewVectorStoreCompoundFilter returns a new VectorStoreCompoundFilter.
+//
+// `operator` can be one of 'and' or 'or'.
+func NewVectorStoreCompoundFilter(operator string, filters ...any) VectorStoreCompoundFilter {
+	if filters == nil {
+		filters = []any{}
+	}
+	return VectorStoreCompoundFilter{
+		Type:    operator,
+		Filters: filters,
+	}
+}
+
+// SearchVectorStoreOptions for searching vector store
+type SearchVectorStoreOptions map[string]any
+
+// SetFilters sets the `filters` parameter of vector store search.
+//
+// `filters` can be a VectorStoreComparisonFilter or a VectorStoreCompoundFilter.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/search#vector-stores-search-filters
+func (o SearchVectorStoreOptions) SetFilters(filters any) SearchVectorStoreOptions {
+	o["filters"] = filters
+	return o
+}
+
+// SetMaxNumResults sets the `max_num_results` parameter of vector store search.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/search#vector-stores-search-max_num_results
+func (o SearchVectorStoreOptions) SetMaxNumResults(maxNumResults int) SearchVectorStoreOptions {
+	o["max_num_results"] = maxNumResults
+	return o
+}
+
+// SetRankingOptions sets the `ranking_options` parameter of vector store search.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/search#vector-stores-search-ranking_options
+func (o SearchVectorStoreOptions) SetRankingOptions(rankingOptions ToolFileSearchRankingOptions) SearchVectorStoreOptions {
+	o["ranking_options"] = rankingOptions
+	return o
+}
+
+// SetRewriteQuery sets the `rewrite_query` parameter of vector store search.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/search#vector-stores-search-rewrite_query
+func (o SearchVectorStoreOptions) SetRewriteQuery(rewriteQuery bool) SearchVectorStoreOptions {
+	o["rewrite_query"] = rewriteQuery
+	return o
+}
+
+// VectorStoreContent struct for a chunk of parsed file content
+type VectorStoreContent struct {
+	Type string `json:"type"` // == 'text'
+	Text string `json:"text"`
+}
+
+// VectorStoreSearchResult struct for VectorStoreSearchResults
+type VectorStoreSearchResult struct {
+	FileID     string               `json:"file_id"`
+	Filename   string               `json:"filename"`
+	Score      float64              `json:"score"`
+	Attributes map[string]any       `json:"attributes,omitempty"`
+	Content    []VectorStoreContent `json:"content"`
+}
+
+// VectorStoreSearchResults struct for API response
+type VectorStoreSearchResults struct {
+	CommonResponse
+
+	SearchQuery any                       `json:"search_query"` // string | []string
+	Data        []VectorStoreSearchResult `json:"data"`
+	HasMore     bool                      `json:"has_more"`
+	NextPage    *string                   `json:"next_page,omitempty"`
+}
+
+// SearchVectorStore searches a vector store with given `vectorStoreID`, `query`, and `options`.
+//
+// `query` can be a string or []string.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores/search
+func (c *Client) SearchVectorStore(vectorStoreID string, query any, options SearchVectorStoreOptions) (response VectorStoreSearchResults, err error) {
+	if options == nil {
+		options = SearchVectorStoreOptions{}
+	}
+	options["query"] = query
+
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s/search", vectorStoreID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreSearchResults{}, err
+}
+
+// VectorStoreFileStatus type for constants
+type VectorStoreFileStatus string
+
+// VectorStoreFileStatus constants
+const (
+	VectorStoreFileStatusInProgress VectorStoreFileStatus = "in_progress"
+	VectorStoreFileStatusCompleted  VectorStoreFileStatus = "completed"
+	VectorStoreFileStatusCancelled  VectorStoreFileStatus = "cancelled"
+	VectorStoreFileStatusFailed     VectorStoreFileStatus = "failed"
+)
+
+// VectorStoreFile struct for vector store file object
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/file-object
+type VectorStoreFile struct {
+	CommonResponse
+
+	ID               string                `json:"id"`
+	CreatedAt        int                   `json:"created_at"`
+	VectorStoreID    string                `json:"vector_store_id"`
+	UsageBytes       int                   `json:"usage_bytes"`
+	Status           VectorStoreFileStatus `json:"status"`
+	LastError        *VectorStoreFileError `json:"last_error,omitempty"`
+	ChunkingStrategy *ChunkingStrategy     `json:"chunking_strategy,omitempty"`
+	Attributes       map[string]any        `json:"attributes,omitempty"`
+}
+
+// VectorStoreFileError struct for VectorStoreFile
+type VectorStoreFileError struct {
+	Code    string `json:"code"` // 'server_error' | 'unsupported_file' | 'invalid_file'
+	Message string `json:"message"`
+}
+
+// CreateVectorStoreFileOptions for creating vector store file
+type CreateVectorStoreFileOptions map[string]any
+
+// SetAttributes sets the `attributes` parameter of vector store file creation.
+//
+// Values of `attributes` can be strings, numbers, or booleans.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/createFile#vector-stores-files-createfile-attributes
+func (o CreateVectorStoreFileOptions) SetAttributes(attributes map[string]any) CreateVectorStoreFileOptions {
+	o["attributes"] = attributes
+	return o
+}
+
+// SetChunkingStrategy sets the `chunking_strategy` parameter of vector store file creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/createFile#vector-stores-files-createfile-chunking_strategy
+func (o CreateVectorStoreFileOptions) SetChunkingStrategy(strategy ChunkingStrategy) CreateVectorStoreFileOptions {
+	o["chunking_strategy"] = strategy
+	return o
+}
+
+// CreateVectorStoreFile attaches a file with given `fileID` to a vector store with `vectorStoreID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/createFile
+func (c *Client) CreateVectorStoreFile(vectorStoreID, fileID string, options CreateVectorStoreFileOptions) (response VectorStoreFile, err error) {
+	if options == nil {
+		options = CreateVectorStoreFileOptions{}
+	}
+	options["file_id"] = fileID
+
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s/files", vectorStoreID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFile{}, err
+}
+
+// ListVectorStoreFilesOptions for listing vector store files
+type ListVectorStoreFilesOptions map[string]any
+
+// SetLimit sets the `limit` parameter of vector store files' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles#vector-stores-files-listfiles-limit
+func (o ListVectorStoreFilesOptions) SetLimit(limit int) ListVectorStoreFilesOptions {
+	o["limit"] = limit
+	return o
+}
+
+// SetOrder sets the `order` parameter of vector store files' listing request.
+//
+// `order` can be one of 'asc' or 'desc'. (default: 'desc')
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles#vector-stores-files-listfiles-order
+func (o ListVectorStoreFilesOptions) SetOrder(order string) ListVectorStoreFilesOptions {
+	o["order"] = order
+	return o
+}
+
+// SetAfter sets the `after` parameter of vector store files' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles#vector-stores-files-listfiles-after
+func (o ListVectorStoreFilesOptions) SetAfter(after string) ListVectorStoreFilesOptions {
+	o["after"] = after
+	return o
+}
+
+// SetBefore sets the `before` parameter of vector store files' listing request.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles#vector-stores-files-listfiles-before
+func (o ListVectorStoreFilesOptions) SetBefore(before string) ListVectorStoreFilesOptions {
+	o["before"] = before
+	return o
+}
+
+// SetFilter sets the `filter` parameter of vector store files' listing request.
+//
+// `filter` can be one of 'in_progress', 'completed', 'failed', or 'cancelled'.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles#vector-stores-files-listfiles-filter
+func (o ListVectorStoreFilesOptions) SetFilter(filter VectorStoreFileStatus) ListVectorStoreFilesOptions {
+	o["filter"] = filter
+	return o
+}
+
+// VectorStoreFiles struct for API response
+type VectorStoreFiles struct {
+	CommonResponse
+
+	Data    []VectorStoreFile `json:"data"`
+	FirstID string            `json:"first_id"`
+	LastID  string            `json:"last_id"`
+	HasMore bool              `json:"has_more"`
+}
+
+// ListVectorStoreFiles lists files of a vector store with given `vectorStoreID` and `options`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/listFiles
+func (c *Client) ListVectorStoreFiles(vectorStoreID string, options ListVectorStoreFilesOptions) (response VectorStoreFiles, err error) {
+	if options == nil {
+		options = ListVectorStoreFilesOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s/files", vectorStoreID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFiles{}, err
+}
+
+// RetrieveVectorStoreFile retrieves a vector store file with given `vectorStoreID` and `fileID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/getFile
+func (c *Client) RetrieveVectorStoreFile(vectorStoreID, fileID string) (response VectorStoreFile, err error) {
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s/files/%s", vectorStoreID, fileID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFile{}, err
+}
+
+// UpdateVectorStoreFileAttributes updates the attributes of a vector store file with given `vectorStoreID` and `fileID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/updateAttributes
+func (c *Client) UpdateVectorStoreFileAttributes(vectorStoreID, fileID string, attributes map[string]any) (response VectorStoreFile, err error) {
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s/files/%s", vectorStoreID, fileID), map[string]any{
+		"attributes": attributes,
+	}); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFile{}, err
+}
+
+// VectorStoreFileDeletionStatus struct for API response
+type VectorStoreFileDeletionStatus struct {
+	CommonResponse
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// DeleteVectorStoreFile removes a file with given `fileID` from a vector store with `vectorStoreID`.
+//
+// The file itself is not deleted; use `DeleteFile` for it.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/deleteFile
+func (c *Client) DeleteVectorStoreFile(vectorStoreID, fileID string) (response VectorStoreFileDeletionStatus, err error) {
+	var bytes []byte
+	if bytes, err = c.delete(fmt.Sprintf("vector_stores/%s/files/%s", vectorStoreID, fileID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFileDeletionStatus{}, err
+}
+
+// VectorStoreFileContent struct for API response
+type VectorStoreFileContent struct {
+	CommonResponse
+
+	FileID     string               `json:"file_id"`
+	Filename   string               `json:"filename"`
+	Attributes map[string]any       `json:"attributes,omitempty"`
+	Content    []VectorStoreContent `json:"content"`
+}
+
+// RetrieveVectorStoreFileContent retrieves the parsed content of a vector store file with given `vectorStoreID` and `fileID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-files/getContent
+func (c *Client) RetrieveVectorStoreFileContent(vectorStoreID, fileID string) (response VectorStoreFileContent, err error) {
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s/files/%s/content", vectorStoreID, fileID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFileContent{}, err
+}
+
+// VectorStoreFileBatch struct for vector store file batch object
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/batch-object
+type VectorStoreFileBatch struct {
+	CommonResponse
+
+	ID            string                `json:"id"`
+	CreatedAt     int                   `json:"created_at"`
+	VectorStoreID string                `json:"vector_store_id"`
+	Status        VectorStoreFileStatus `json:"status"`
+	FileCounts    VectorStoreFileCounts `json:"file_counts"`
+}
+
+// CreateVectorStoreFileBatchOptions for creating vector store file batch
+type CreateVectorStoreFileBatchOptions map[string]any
+
+// SetAttributes sets the `attributes` parameter of vector store file batch creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/createBatch#vector-stores-file-batches-createbatch-attributes
+func (o CreateVectorStoreFileBatchOptions) SetAttributes(attributes map[string]any) CreateVectorStoreFileBatchOptions {
+	o["attributes"] = attributes
+	return o
+}
+
+// SetChunkingStrategy sets the `chunking_strategy` parameter of vector store file batch creation.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/createBatch#vector-stores-file-batches-createbatch-chunking_strategy
+func (o CreateVectorStoreFileBatchOptions) SetChunkingStrategy(strategy ChunkingStrategy) CreateVectorStoreFileBatchOptions {
+	o["chunking_strategy"] = strategy
+	return o
+}
+
+// CreateVectorStoreFileBatch attaches files with given `fileIDs` to a vector store with `vectorStoreID` at once.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/createBatch
+func (c *Client) CreateVectorStoreFileBatch(vectorStoreID string, fileIDs []string, options CreateVectorStoreFileBatchOptions) (response VectorStoreFileBatch, err error) {
+	if options == nil {
+		options = CreateVectorStoreFileBatchOptions{}
+	}
+	options["file_ids"] = fileIDs
+
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s/file_batches", vectorStoreID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFileBatch{}, err
+}
+
+// RetrieveVectorStoreFileBatch retrieves a vector store file batch with given `vectorStoreID` and `batchID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/getBatch
+func (c *Client) RetrieveVectorStoreFileBatch(vectorStoreID, batchID string) (response VectorStoreFileBatch, err error) {
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s/file_batches/%s", vectorStoreID, batchID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFileBatch{}, err
+}
+
+// CancelVectorStoreFileBatch cancels a vector store file batch with given `vectorStoreID` and `batchID`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/cancelBatch
+func (c *Client) CancelVectorStoreFileBatch(vectorStoreID, batchID string) (response VectorStoreFileBatch, err error) {
+	var bytes []byte
+	if bytes, err = c.post(fmt.Sprintf("vector_stores/%s/file_batches/%s/cancel", vectorStoreID, batchID), nil); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFileBatch{}, err
+}
+
+// ListVectorStoreFileBatchFiles lists files of a vector store file batch with given `vectorStoreID`, `batchID`, and `options`.
+//
+// https://platform.openai.com/docs/api-reference/vector-stores-file-batches/listBatchFiles
+func (c *Client) ListVectorStoreFileBatchFiles(vectorStoreID, batchID string, options ListVectorStoreFilesOptions) (response VectorStoreFiles, err error) {
+	if options == nil {
+		options = ListVectorStoreFilesOptions{}
+	}
+
+	var bytes []byte
+	if bytes, err = c.get(fmt.Sprintf("vector_stores/%s/file_batches/%s/files", vectorStoreID, batchID), options); err == nil {
+		if err = json.Unmarshal(bytes, &response); err == nil {
+			if response.Error == nil {
+				return response, nil
+			}
+
+			err = response.Error.err()
+		}
+	} else {
+		var res CommonResponse
+		if e := json.Unmarshal(bytes, &res); e == nil {
+			err = fmt.Errorf("%s: %s", err, res.Error.err())
+		}
+	}
+
+	return VectorStoreFiles{}, err
+}
+
+// helpers for uploading files and waiting for them to be indexed
+
+const (
+	defaultVectorStoreIndexInitialInterval = 1 * time.Second
+	defaultVectorStoreIndexMaxInterval     = 10 * time.Second
+
+	vectorStoreIndexListLimit = 100
+)
+
+// VectorStoreIndexOptions struct for uploading and indexing files
+type VectorStoreIndexOptions struct {
+	// polling interval at first (default: 1 second)
+	InitialInterval time.Duration
+
+	// polling interval doubles up to this value (default: 10 seconds)
+	MaxInterval time.Duration
+
+	// chunking strategy and attributes applied to all files
+	ChunkingStrategy *ChunkingStrategy
+	Attributes       map[string]any
+}
+
+// VectorStoreIndexResult struct for the result of uploaded and indexed files
+type VectorStoreIndexResult struct {
+	// ids of uploaded files, in the same order of given files
+	FileIDs []string
+
+	// the last retrieved state of the file batch
+	Batch VectorStoreFileBatch
+
+	// all files of the batch, with their final statuses
+	Files []VectorStoreFile
+}
+
+// VectorStoreIndexError is returned when some files failed to be indexed.
+type VectorStoreIndexError struct {
+	Failed []VectorStoreFile
+}
+
+// Error returns the error message.
+func (e *VectorStoreIndexError) Error() string {
+	failures := []string{}
+	for _, file := range e.Failed {
+		if file.LastError != nil {
+			failures = append(failures, fmt.Sprintf("%s (%s: %s)", file.ID, file.LastError.Code, file.LastError.Message))
+		} else {
+			failures = append(failures, fmt.Sprintf("%s (%s)", file.ID, file.Status))
+		}
+	}
+	return fmt.Sprintf("failed to index %d file(s): %s", len(e.Failed), strings.Join(failures, ", "))
+}
+
+// UploadAndIndex uploads given `files` with `UploadFile`, adds them to a vector store with `vectorStoreID` as a file batch,
+// and polls the batch until all files are processed.
+//
+// Files should have filenames with extensions supported by file search (eg. '.txt', '.md', '.docx'),
+// so create them with `NewFileParamFromFilepath` or `NewFileParamFromBytesWithFilename`.
+//
+// Returns *VectorStoreIndexError (along with the result) if any file was not indexed successfully.
+func (c *Client) UploadAndIndex(ctx context.Context, vectorStoreID string, files ...FileParam) (result VectorStoreIndexResult, err error) {
+	return c.UploadAndIndexWithOptions(ctx, vectorStoreID, VectorStoreIndexOptions{}, files...)
+}
+
+// UploadAndIndexWithOptions does the same as `UploadAndIndex` with given `options`.
+func (c *Client) UploadAndIndexWithOptions(ctx context.Context, vectorStoreID string, options VectorStoreIndexOptions, files ...FileParam) (result VectorStoreIndexResult, err error) {
+	if len(files) <= 0 {
+		return result, fmt.Errorf("no file was given for indexing")
+	}
+
+	initial := options.InitialInterval
+	if initial <= 0 {
+		initial = defaultVectorStoreIndexInitialInterval
+	}
+	maxInterval := options.MaxInterval
+	if maxInterval < initial {
+		maxInterval = defaultVectorStoreIndexMaxInterval
+		if maxInterval < initial {
+			maxInterval = initial
+		}
+	}
+
+	// upload files
+	for i, file := range files {
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var uploaded UploadedFile
+		if uploaded, err = c.UploadFile(file, "assistants"); err != nil {
+			return result, fmt.Errorf("failed to upload file #%d: %s", i, err)
+		}
+		result.FileIDs = append(result.FileIDs, uploaded.ID)
+	}
+
+	// create a file batch
+	batchOptions := CreateVectorStoreFileBatchOptions{}
+	if options.ChunkingStrategy != nil {
+		batchOptions.SetChunkingStrategy(*options.ChunkingStrategy)
+	}
+	if options.Attributes != nil {
+		batchOptions.SetAttributes(options.Attributes)
+	}
+	if result.Batch, err = c.CreateVectorStoreFileBatch(vectorStoreID, result.FileIDs, batchOptions); err != nil {
+		return result, fmt.Errorf("failed to create file batch: %s", err)
+	}
+
+	// poll until it is processed
+	interval := initial
+	for result.Batch.Status == VectorStoreFileStatusInProgress {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if result.Batch, err = c.RetrieveVectorStoreFileBatch(vectorStoreID, result.Batch.ID); err != nil {
+			return result, fmt.Errorf("failed to retrieve file batch: %s", err)
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+
+	// collect final statuses of files
+	listOptions := ListVectorStoreFilesOptions{}.SetLimit(vectorStoreIndexListLimit).SetOrder("asc")
+	for {
+		var listed VectorStoreFiles
+		if listed, err = c.ListVectorStoreFileBatchFiles(vectorStoreID, result.Batch.ID, listOptions); err != nil {
+			return result, fmt.Errorf("failed to list files of file batch: %s", err)
+		}
+		result.Files = append(result.Files, listed.Data...)
+
+		if !listed.HasMore || len(listed.Data) <= 0 {
+			break
+		}
+		listOptions.SetAfter(listed.LastID)
+	}
+
+	failed := []VectorStoreFile{}
+	for _, file := range result.Files {
+		if file.Status != VectorStoreFileStatusCompleted {
+			failed = append(failed, file)
+		}
+	}
+	if len(failed) > 0 {
+		return result, &VectorStoreIndexError{Failed: failed}
+	}
+	if result.Batch.Status != VectorStoreFileStatusCompleted {
+		return result, fmt.Errorf("file batch was not completed: %s", result.Batch.Status)
+	}
+
+	return result, nil
+}
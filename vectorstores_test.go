@@ -0,0 +1,222 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVectorStoresMock(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if beta := r.Header.Get("OpenAI-Beta"); beta != "assistants=v2" {
+			t.Errorf("Expected beta header 'assistants=v2', got '%s'", beta)
+		}
+		body = nil
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/vector_stores":
+			w.Write([]byte(`{"id":"vs_1","object":"vector_store","name":"docs","status":"in_progress","file_counts":{"in_progress":1,"total":1},"expires_after":{"anchor":"last_active_at","days":7}}`))
+		case "/vector_stores/vs_1/search":
+			w.Write([]byte(`{"object":"vector_store.search_results.page","search_query":"refund policy","data":[{"file_id":"file_1","filename":"policy.md","score":0.87,"attributes":{"year":2024},"content":[{"type":"text","text":"Refunds are available within 30 days."}]}],"has_more":false}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	// create
+	store, err := client.CreateVectorStore(CreateVectorStoreOptions{}.
+		SetName("docs").
+		SetFileIDs([]string{"file_1"}).
+		SetExpiresAfter(NewVectorStoreExpiresAfter(7)).
+		SetChunkingStrategy(NewStaticChunkingStrategy(400, 100)))
+	if err != nil {
+		t.Fatalf("CreateVectorStore failed: %v", err)
+	}
+	if store.ID != "vs_1" || store.ExpiresAfter == nil || store.ExpiresAfter.Days != 7 || store.FileCounts.Total != 1 {
+		t.Errorf("Unexpected vector store: %+v", store)
+	}
+	strategy, _ := body["chunking_strategy"].(map[string]any)
+	if static, ok := strategy["static"].(map[string]any); !ok || static["max_chunk_size_tokens"] != float64(400) || static["chunk_overlap_tokens"] != float64(100) {
+		t.Errorf("Unexpected chunking strategy: %v", body["chunking_strategy"])
+	}
+
+	// search
+	results, err := client.SearchVectorStore("vs_1", "refund policy", SearchVectorStoreOptions{}.
+		SetFilters(NewVectorStoreCompoundFilter("and",
+			NewVectorStoreComparisonFilter("year", "gte", 2024),
+			NewVectorStoreComparisonFilter("lang", "eq", "en"),
+		)).
+		SetMaxNumResults(5))
+	if err != nil {
+		t.Fatalf("SearchVectorStore failed: %v", err)
+	}
+	if filters, ok := body["filters"].(map[string]any); !ok || filters["type"] != "and" || len(filters["filters"].([]any)) != 2 {
+		t.Errorf("Unexpected filters: %v", body["filters"])
+	}
+	if len(results.Data) != 1 || results.Data[0].Score != 0.87 || results.Data[0].Content[0].Text != "Refunds are available within 30 days." {
+		t.Errorf("Unexpected search results: %+v", results)
+	}
+}
+
+func TestUploadAndIndexMock(t *testing.T) {
+	var mutex sync.Mutex
+	uploaded := 0
+	retrieved := 0
+	var batchBody map[string]any
+	filenames := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/files":
+			if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") || r.FormValue("purpose") != "assistants" {
+				t.Errorf("Unexpected upload request: %s", r.Header.Get("Content-Type"))
+			}
+			if _, header, err := r.FormFile("file"); err == nil {
+				filenames = append(filenames, header.Filename)
+			} else {
+				t.Errorf("Expected a file part: %v", err)
+			}
+			uploaded++
+			fmt.Fprintf(w, `{"id":"file_%d","object":"file","purpose":"assistants"}`, uploaded)
+		case r.URL.Path == "/vector_stores/vs_1/file_batches":
+			if err := json.NewDecoder(r.Body).Decode(&batchBody); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			w.Write([]byte(`{"id":"vsfb_1","object":"vector_store.file_batch","vector_store_id":"vs_1","status":"in_progress","file_counts":{"in_progress":2,"total":2}}`))
+		case r.URL.Path == "/vector_stores/vs_1/file_batches/vsfb_1":
+			retrieved++
+			if retrieved < 2 {
+				w.Write([]byte(`{"id":"vsfb_1","status":"in_progress","file_counts":{"in_progress":1,"completed":1,"total":2}}`))
+			} else {
+				w.Write([]byte(`{"id":"vsfb_1","status":"completed","file_counts":{"completed":1,"failed":1,"total":2}}`))
+			}
+		case r.URL.Path == "/vector_stores/vs_1/file_batches/vsfb_1/files":
+			if r.URL.Query().Get("after") == "" {
+				w.Write([]byte(`{"object":"list","data":[{"id":"file_1","status":"completed"}],"first_id":"file_1","last_id":"file_1","has_more":true}`))
+			} else {
+				w.Write([]byte(`{"object":"list","data":[{"id":"file_2","status":"failed","last_error":{"code":"unsupported_file","message":"Unsupported file type."}}],"first_id":"file_2","last_id":"file_2","has_more":false}`))
+			}
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	path := filepath.Join(t.TempDir(), "guide.txt")
+	if err := os.WriteFile(path, []byte("world"), 0644); err != nil {
+		t.Fatalf("Failed to write a file: %v", err)
+	}
+	file, err := NewFileParamFromFilepath(path)
+	if err != nil {
+		t.Fatalf("Failed to read a file: %v", err)
+	}
+
+	strategy := NewAutoChunkingStrategy()
+	result, err := client.UploadAndIndexWithOptions(context.Background(), "vs_1", VectorStoreIndexOptions{
+		InitialInterval:  time.Millisecond,
+		MaxInterval:      5 * time.Millisecond,
+		ChunkingStrategy: &strategy,
+		Attributes:       map[string]any{"source": "test"},
+	}, NewFileParamFromBytesWithFilename([]byte("# hello"), "notes.md"), file)
+
+	var indexErr *VectorStoreIndexError
+	if !errors.As(err, &indexErr) || len(indexErr.Failed) != 1 || indexErr.Failed[0].ID != "file_2" {
+		t.Fatalf("Expected VectorStoreIndexError for file_2, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "unsupported_file") {
+		t.Errorf("Expected error code in message, got '%s'", err.Error())
+	}
+	if strings.Join(filenames, ",") != "notes.md,guide.txt" {
+		t.Errorf("Expected uploaded filenames [notes.md guide.txt], got %v", filenames)
+	}
+	if len(result.FileIDs) != 2 || result.FileIDs[0] != "file_1" || result.FileIDs[1] != "file_2" {
+		t.Errorf("Unexpected file ids: %v", result.FileIDs)
+	}
+	if fileIDs, ok := batchBody["file_ids"].([]any); !ok || len(fileIDs) != 2 {
+		t.Errorf("Unexpected file batch request: %v", batchBody)
+	}
+	if batchBody["attributes"] == nil || batchBody["chunking_strategy"] == nil {
+		t.Errorf("Expected attributes and chunking strategy in file batch request, got %v", batchBody)
+	}
+	if result.Batch.Status != VectorStoreFileStatusCompleted || retrieved != 2 {
+		t.Errorf("Expected the batch to be polled until completed, got %s after %d retrieval(s)", result.Batch.Status, retrieved)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("Expected 2 files, got %d", len(result.Files))
+	}
+}
+
+func TestUploadAndIndexCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/files":
+			w.Write([]byte(`{"id":"file_1","object":"file"}`))
+		default:
+			w.Write([]byte(`{"id":"vsfb_1","status":"in_progress"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.UploadAndIndexWithOptions(ctx, "vs_1", VectorStoreIndexOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     2 * time.Millisecond,
+	}, NewFileParamFromBytes([]byte("hello")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUploadFileQuotedFilename(t *testing.T) {
+	filename := `my "quoted" notes.md`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Expected a file part: %v", err)
+			return
+		}
+		if header.Filename != filename {
+			t.Errorf("Expected filename '%s', got '%s'", filename, header.Filename)
+		}
+		w.Write([]byte(`{"id":"file_1","object":"file","purpose":"assistants"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-org")
+	client.baseURL = &server.URL
+
+	if _, err := client.UploadFile(NewFileParamFromBytesWithFilename([]byte("# hello"), filename), "assistants"); err != nil {
+		t.Errorf("Failed to upload file: %s", err)
+	}
+}
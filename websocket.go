@@ -0,0 +1,350 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// minimal WebSocket (RFC 6455) client for the realtime API
+//
+// https://www.rfc-editor.org/rfc/rfc6455
+
+// WebSocket opcodes
+const (
+	websocketOpContinuation = 0x0
+	websocketOpText         = 0x1
+	websocketOpBinary       = 0x2
+	websocketOpClose        = 0x8
+	websocketOpPing         = 0x9
+	websocketOpPong         = 0xA
+)
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	websocketCloseNormal = 1000
+
+	maxWebSocketMessageSize = 16 * 1024 * 1024
+)
+
+// WebSocketCloseError is returned when the peer closes a WebSocket connection.
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error returns the error message.
+func (e *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("websocket closed with code %d: %s", e.Code, e.Reason)
+}
+
+// client side of a WebSocket connection
+type websocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMutex sync.Mutex
+}
+
+// dials a WebSocket server at `rawURL` (ws, wss, http, or https) with given `header`
+func dialWebSocket(ctx context.Context, rawURL string, header http.Header) (ws *websocketConn, err error) {
+	var u *url.URL
+	if u, err = url.Parse(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid websocket url: %s", err)
+	}
+
+	secure := false
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("unsupported websocket url scheme: %s", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if secure {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	// connect
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: DialTimeout, KeepAlive: KeepAlive}
+	if secure {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}).DialContext(ctx, "tcp", host)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// abort the handshake when `ctx` is done
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	// handshake
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	u.Scheme = "http"
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send websocket handshake: %s", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	var resp *http.Response
+	if resp, err = http.ReadResponse(reader, req); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read websocket handshake: %s", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		var res CommonResponse
+		if e := json.Unmarshal(body, &res); e == nil && res.Error != nil {
+			return nil, fmt.Errorf("websocket handshake failed with status %d: %s", resp.StatusCode, res.Error.err())
+		}
+		return nil, fmt.Errorf("websocket handshake failed with status %d", resp.StatusCode)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != websocketAcceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("invalid websocket handshake response")
+	}
+
+	return &websocketConn{
+		conn:   conn,
+		reader: reader,
+	}, nil
+}
+
+// returns the `Sec-WebSocket-Accept` value for given `key`
+func websocketAcceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// writes a text message
+func (ws *websocketConn) writeText(payload []byte) error {
+	return ws.write(websocketOpText, payload)
+}
+
+// writes a frame with given `opcode` and `payload`
+func (ws *websocketConn) write(opcode byte, payload []byte) error {
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+
+	return writeWebSocketFrame(ws.conn, opcode, payload, true)
+}
+
+// reads the next data message, handling control frames in between
+func (ws *websocketConn) readMessage() (opcode byte, payload []byte, err error) {
+	var message []byte
+	messageOpcode := byte(0)
+
+	for {
+		var fin bool
+		var frameOpcode byte
+		var frame []byte
+		if fin, frameOpcode, frame, err = readWebSocketFrame(ws.reader); err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOpcode {
+		case websocketOpPing:
+			if err = ws.write(websocketOpPong, frame); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case websocketOpPong:
+			continue
+		case websocketOpClose:
+			closeErr := &WebSocketCloseError{Code: websocketCloseNormal}
+			if len(frame) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(frame[:2]))
+				closeErr.Reason = string(frame[2:])
+			}
+			_ = ws.write(websocketOpClose, frame[:minInt(len(frame), 2)])
+			return 0, nil, closeErr
+		case websocketOpContinuation:
+			if messageOpcode == 0 {
+				return 0, nil, fmt.Errorf("unexpected websocket continuation frame")
+			}
+		default:
+			if messageOpcode != 0 {
+				return 0, nil, fmt.Errorf("unexpected websocket frame in a fragmented message")
+			}
+			messageOpcode = frameOpcode
+		}
+
+		if len(message)+len(frame) > maxWebSocketMessageSize {
+			return 0, nil, fmt.Errorf("websocket message is too large")
+		}
+		message = append(message, frame...)
+
+		if fin {
+			return messageOpcode, message, nil
+		}
+	}
+}
+
+// sends a close frame with given `code` and `reason`, and closes the connection
+func (ws *websocketConn) close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+
+	writeErr := ws.write(websocketOpClose, payload)
+	closeErr := ws.conn.Close()
+	if writeErr != nil && !errors.Is(writeErr, net.ErrClosed) {
+		return writeErr
+	}
+	return closeErr
+}
+
+// writes a single (final) frame, masked if `mask` is true (as clients should)
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode // FIN
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if mask {
+		header[1] |= 0x80
+
+		key := make([]byte, 4)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		header = append(header, key...)
+
+		masked := make([]byte, length)
+		for i := range payload {
+			masked[i] = payload[i] ^ key[i%4]
+		}
+		payload = masked
+	}
+
+	if _, err := w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reads a single frame, unmasking its payload if needed
+func readWebSocketFrame(r *bufio.Reader) (fin bool, opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err = io.ReadFull(r, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err = io.ReadFull(r, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+	if length > maxWebSocketMessageSize {
+		return false, 0, nil, fmt.Errorf("websocket frame is too large: %d bytes", length)
+	}
+
+	var key []byte
+	if masked {
+		key = make([]byte, 4)
+		if _, err = io.ReadFull(r, key); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// returns the smaller one of given integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}